@@ -57,6 +57,12 @@ func (at *Test) Run(t *testing.T, table []Table, testName string) {
 				t.Fatalf("%s: Should receive a status code of %d for the response : %d", tt.Name, tt.StatusCode, w.Code)
 			}
 
+			for key, value := range tt.ExpHeader {
+				if got := w.Header().Get(key); got != value {
+					t.Fatalf("%s: Should receive header %s %q for the response : %q", tt.Name, key, value, got)
+				}
+			}
+
 			if tt.StatusCode == http.StatusNoContent || w.Body.Bytes() == nil {
 				return
 			}
@@ -1,6 +1,9 @@
 package apptest
 
-import "github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+import (
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+)
 
 // Account represents a test account.
 type Account struct {
@@ -9,7 +12,12 @@ type Account struct {
 
 // SeedData represents users for api tests.
 type SeedData struct {
-	Accounts []Account
+	Accounts  []Account
+	Transfers []transferbus.Transfer
+	Quotes    []transferbus.Quote
+	Holds     []transferbus.Hold
+	Schedules []transferbus.ScheduledTransfer
+	Webhooks  []transferbus.WebhookSubscription
 }
 
 // Table represent fields needed for running an api test.
@@ -18,6 +26,7 @@ type Table struct {
 	URL        string
 	Method     string
 	StatusCode int
+	ExpHeader  map[string]string
 	Input      any
 	GotResp    any
 	ExpResp    any
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/danipurwadi/internal-transfer-system/foundation/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCLogger logs the start and completion of every unary call.
+func GRPCLogger(log *logger.Logger) grpc.UnaryServerInterceptor {
+	i := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		now := time.Now()
+
+		log.Info(ctx, "call started", "method", info.FullMethod)
+
+		resp, err := handler(ctx, req)
+
+		log.Info(ctx, "call completed", "method", info.FullMethod,
+			"code", status.Code(err).String(), "sinceInMs", time.Since(now).Milliseconds())
+		return resp, err
+	}
+	return i
+}
+
+// GRPCErrors converts the errors of every unary call the same way Errors does
+// for HTTP requests. The gRPC status of the call is taken from the resulting
+// customerror.Error.
+func GRPCErrors(log *logger.Logger) grpc.UnaryServerInterceptor {
+	i := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var resp any
+		hdl := func(ctx context.Context) error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		}
+
+		if err := ConvertError(ctx, log, hdl); err != nil {
+			return nil, err.(customerror.Error)
+		}
+		return resp, nil
+	}
+	return i
+}
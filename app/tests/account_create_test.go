@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
 	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
@@ -17,14 +19,53 @@ func accountCreation200() []apptest.Table {
 			URL:        "/accounts",
 			Method:     http.MethodPost,
 			StatusCode: http.StatusCreated,
+			ExpHeader:  map[string]string{"Location": "/accounts/2"},
 			Input: &transferapp.AccountCreationRequest{
 				AccountID:      2,
 				InitialBalance: "100.12345",
 			},
-			GotResp: nil,
-			ExpResp: nil,
+			GotResp: &transferapp.AccountResponse{},
+			ExpResp: &transferapp.AccountResponse{
+				BalanceResponse: transferapp.BalanceResponse{
+					AccountID:        "2",
+					Currency:         "USD",
+					Status:           string(transferbus.AccountStatusActive),
+					Type:             string(transferbus.AccountTypeCustomer),
+					Labels:           []string{},
+					Balance:          "100.12",
+					AvailableBalance: "100.12",
+				},
+			},
 			CmpFunc: func(got any, exp any) string {
-				return cmp.Diff(got, exp)
+				gotResp := got.(*transferapp.AccountResponse)
+				if gotResp.CreatedDate == "" || gotResp.LastModifiedDate == "" {
+					return "created and last modified dates should be set"
+				}
+
+				expResp := *exp.(*transferapp.AccountResponse)
+				expResp.CreatedDate = gotResp.CreatedDate
+				expResp.LastModifiedDate = gotResp.LastModifiedDate
+				return cmp.Diff(gotResp, &expResp)
+			},
+		},
+		{
+			Name:       "allocatedid",
+			URL:        "/accounts",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.AccountCreationRequest{
+				InitialBalance: "10",
+			},
+			GotResp: &transferapp.AccountResponse{},
+			ExpResp: "10",
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.AccountResponse)
+
+				accID, err := strconv.ParseInt(gotResp.AccountID, 10, 64)
+				if err != nil || accID < transferbus.FirstAllocatedAccountID {
+					return fmt.Sprintf("expected an allocated account id, got %q", gotResp.AccountID)
+				}
+				return cmp.Diff(gotResp.Balance, exp)
 			},
 		},
 	}
@@ -35,15 +76,16 @@ func accountCreation200() []apptest.Table {
 func accountCreation400() []apptest.Table {
 	table := []apptest.Table{
 		{
-			Name:       "missingaccountid",
+			Name:       "reservedaccountid",
 			URL:        "/accounts",
 			Method:     http.MethodPost,
 			StatusCode: http.StatusBadRequest,
 			Input: &transferapp.AccountCreationRequest{
+				AccountID:      transferbus.FirstAllocatedAccountID,
 				InitialBalance: "100.12345",
 			},
 			GotResp: &customerror.Error{},
-			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "validate: [{\"field\":\"account_id\",\"error\":\"account_id is a required field\"}]")),
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrAccIDReserved.Error())),
 			CmpFunc: func(got any, exp any) string {
 				return cmp.Diff(got, exp)
 			},
@@ -78,6 +120,22 @@ func accountCreation400() []apptest.Table {
 				return cmp.Diff(got, exp)
 			},
 		},
+		{
+			Name:       "unsupportedcurrency",
+			URL:        "/accounts",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.AccountCreationRequest{
+				AccountID:      2,
+				Currency:       "XYZ",
+				InitialBalance: "100",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "invalid currency \"XYZ\"")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
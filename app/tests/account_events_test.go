@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func accountEvents400(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "invalidaccountid",
+			URL:        "/accounts/abc/events",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid account id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidlasteventid",
+			URL:        fmt.Sprintf("/accounts/%d/events?last_event_id=abc", sd.Accounts[0].AccountID),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid last event id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accountEvents404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "accountnotfound",
+			URL:        "/accounts/999999/events",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "systemaccount",
+			URL:        "/accounts/-1/events",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
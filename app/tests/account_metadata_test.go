@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func accountMetadata200(sd apptest.SeedData) []apptest.Table {
+	tagged := sd.Accounts[5]
+
+	owner := "owner-4000"
+	name := "fee income"
+	labels := []string{"billing", "monthly"}
+
+	updated := &transferapp.BalanceResponse{
+		AccountID:        strconv.FormatInt(tagged.AccountID, 10),
+		Currency:         tagged.Currency.String(),
+		Status:           string(transferbus.AccountStatusActive),
+		Type:             string(transferbus.AccountTypeFee),
+		Owner:            owner,
+		Name:             name,
+		Labels:           labels,
+		Balance:          tagged.Balance.String(),
+		AvailableBalance: tagged.Balance.String(),
+	}
+
+	table := []apptest.Table{
+		{
+			Name:       "update",
+			URL:        fmt.Sprintf("/accounts/%d", tagged.AccountID),
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.AccountUpdateRequest{
+				Name:   &name,
+				Labels: &labels,
+			},
+			GotResp: &transferapp.BalanceResponse{},
+			ExpResp: updated,
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "searchbyowner",
+			URL:        "/accounts?owner=" + owner,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.AccountsResponse{},
+			ExpResp: &transferapp.AccountsResponse{
+				Items: []transferapp.BalanceResponse{*updated},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "searchbytypeandlabels",
+			URL:        "/accounts?type=fee&label=billing&label=monthly",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.AccountsResponse{},
+			ExpResp: &transferapp.AccountsResponse{
+				Items: []transferapp.BalanceResponse{*updated},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "searchnomatch",
+			URL:        "/accounts?owner=" + owner + "&label=yearly",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.AccountsResponse{},
+			ExpResp: &transferapp.AccountsResponse{
+				Items: []transferapp.BalanceResponse{},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "searchpage",
+			URL:        "/accounts?type=customer&limit=1",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.AccountsResponse{},
+			ExpResp:    1,
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.AccountsResponse)
+				if gotResp.NextCursor == "" {
+					return "expected a cursor to the next page"
+				}
+				return cmp.Diff(len(gotResp.Items), exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accountMetadata400(sd apptest.SeedData) []apptest.Table {
+	unknownType := "savings"
+
+	table := []apptest.Table{
+		{
+			Name:       "invalidtype",
+			URL:        fmt.Sprintf("/accounts/%d", sd.Accounts[5].AccountID),
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.AccountUpdateRequest{
+				Type: &unknownType,
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "validate: [{\"field\":\"type\",\"error\":\"type must be one of [customer operational fee suspense]\"}]")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidaccountid",
+			URL:        "/accounts/abc",
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusBadRequest,
+			Input:      &transferapp.AccountUpdateRequest{},
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid account id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "searchinvalidtype",
+			URL:        "/accounts?type=savings",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "type must be one of customer, operational, fee or suspense")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "searchinvalidlimit",
+			URL:        "/accounts?limit=0",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "limit must be between 1 and 200")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accountMetadata404() []apptest.Table {
+	name := "missing"
+
+	table := []apptest.Table{
+		{
+			Name:       "accountnotfound",
+			URL:        "/accounts/999999",
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusNotFound,
+			Input: &transferapp.AccountUpdateRequest{
+				Name: &name,
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "systemaccount",
+			URL:        "/accounts/-1",
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusNotFound,
+			Input: &transferapp.AccountUpdateRequest{
+				Name: &name,
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -22,8 +22,13 @@ func accountQuery200(sd apptest.SeedData) []apptest.Table {
 			Input:      nil,
 			GotResp:    &transferapp.BalanceResponse{},
 			ExpResp: &transferapp.BalanceResponse{
-				AccountID: strconv.FormatInt(sd.Accounts[0].AccountID, 10),
-				Balance:   sd.Accounts[0].Balance.String(),
+				AccountID:        strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				Currency:         sd.Accounts[0].Currency.String(),
+				Status:           string(transferbus.AccountStatusActive),
+				Type:             string(transferbus.AccountTypeCustomer),
+				Labels:           []string{},
+				Balance:          sd.Accounts[0].Balance.String(),
+				AvailableBalance: sd.Accounts[0].Balance.String(),
 			},
 			CmpFunc: func(got any, exp any) string {
 				return cmp.Diff(got, exp)
@@ -67,6 +72,18 @@ func accountQuery404() []apptest.Table {
 				return cmp.Diff(got, exp)
 			},
 		},
+		{
+			Name:       "systemaccount",
+			URL:        "/accounts/-1",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
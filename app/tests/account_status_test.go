@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func accountStatus200(sd apptest.SeedData) []apptest.Table {
+	swept := sd.Accounts[3]
+	empty := sd.Accounts[4]
+
+	balance := func(acc apptest.Account, status transferbus.AccountStatus, amount string) *transferapp.BalanceResponse {
+		return &transferapp.BalanceResponse{
+			AccountID:        strconv.FormatInt(acc.AccountID, 10),
+			Currency:         acc.Currency.String(),
+			Status:           string(status),
+			Type:             string(transferbus.AccountTypeCustomer),
+			Labels:           []string{},
+			Balance:          amount,
+			AvailableBalance: amount,
+		}
+	}
+
+	table := []apptest.Table{
+		{
+			Name:       "freeze",
+			URL:        fmt.Sprintf("/accounts/%d:freeze", swept.AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.BalanceResponse{},
+			ExpResp:    balance(swept, transferbus.AccountStatusFrozen, "5"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "freezeagain",
+			URL:        fmt.Sprintf("/accounts/%d:freeze", swept.AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.BalanceResponse{},
+			ExpResp:    balance(swept, transferbus.AccountStatusFrozen, "5"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "unfreeze",
+			URL:        fmt.Sprintf("/accounts/%d:unfreeze", swept.AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.BalanceResponse{},
+			ExpResp:    balance(swept, transferbus.AccountStatusActive, "5"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "closeempty",
+			URL:        fmt.Sprintf("/accounts/%d:close", empty.AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.BalanceResponse{},
+			ExpResp:    balance(empty, transferbus.AccountStatusClosed, "0"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "closewithsweep",
+			URL:        fmt.Sprintf("/accounts/%d:close", swept.AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.AccountCloseRequest{
+				SweepAccountID: sd.Accounts[0].AccountID,
+			},
+			GotResp: &transferapp.BalanceResponse{},
+			ExpResp: balance(swept, transferbus.AccountStatusClosed, "0"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accountStatus400(sd apptest.SeedData) []apptest.Table {
+	closed := sd.Accounts[3]
+
+	table := []apptest.Table{
+		{
+			Name:       "invalidid",
+			URL:        "/accounts/abc:freeze",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid account id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "closed",
+			URL:        fmt.Sprintf("/accounts/%d:freeze", closed.AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.FailedPrecondition, transferbus.ErrAccClosed.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "systemaccount",
+			URL:        fmt.Sprintf("/accounts/%d:freeze", transferbus.EquityAccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrSystemAccount.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "notempty",
+			URL:        fmt.Sprintf("/accounts/%d:close", sd.Accounts[2].AccountID),
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.FailedPrecondition, transferbus.ErrAccNotEmpty.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "transferfromclosed",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: closed.AccountID,
+				Amount:               "1",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "account[%d]: %s", closed.AccountID, transferbus.ErrAccClosed)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accountStatus404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "accountnotfound",
+			URL:        "/accounts/999999:freeze",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "unknownaction",
+			URL:        "/accounts/1:explode",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, "unknown account action %q", "explode")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func fxQuote201(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "basic",
+			URL:        "/fx/quotes",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.QuoteRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[2].AccountID,
+				Amount:               "10.0",
+			},
+			GotResp: &transferapp.QuoteResponse{},
+			ExpResp: &transferapp.QuoteResponse{
+				SourceAccountID:      strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				DestinationAccountID: strconv.FormatInt(sd.Accounts[2].AccountID, 10),
+				SourceCurrency:       "USD",
+				DestinationCurrency:  "EUR",
+				SourceAmount:         "10",
+				DestinationAmount:    "9",
+				Rate:                 "0.9",
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.QuoteResponse)
+				if gotResp.QuoteID == "" || gotResp.ExpiresDate == "" {
+					return "quote id and expiry should be set"
+				}
+
+				expResp := *exp.(*transferapp.QuoteResponse)
+				expResp.QuoteID = gotResp.QuoteID
+				expResp.CreatedDate = gotResp.CreatedDate
+				expResp.ExpiresDate = gotResp.ExpiresDate
+				return cmp.Diff(gotResp, &expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func fxQuote400(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "samecurrency",
+			URL:        "/fx/quotes",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.QuoteRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "10.0",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, fxbus.ErrSameCurrency.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "zeroamount",
+			URL:        "/fx/quotes",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.QuoteRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[2].AccountID,
+				Amount:               "0.001",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, fxbus.ErrInvalidAmount.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func fxQuote404(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "accountnotfound",
+			URL:        "/fx/quotes",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			Input: &transferapp.QuoteRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: 12345,
+				Amount:               "10.0",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func fxTransfer201(sd apptest.SeedData) []apptest.Table {
+	quote := sd.Quotes[0]
+
+	table := []apptest.Table{
+		{
+			Name:       "quoted",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      quote.SourceAccountID,
+				DestinationAccountID: quote.DestinationAccountID,
+				Amount:               quote.SourceAmount.String(),
+				QuoteID:              quote.QuoteID.String(),
+			},
+			GotResp: &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeFXTransfer),
+				SourceAccountID:      strconv.FormatInt(quote.SourceAccountID, 10),
+				DestinationAccountID: strconv.FormatInt(quote.DestinationAccountID, 10),
+				Amount:               "10",
+				Currency:             "USD",
+				DestinationAmount:    "9",
+				DestinationCurrency:  "EUR",
+				Rate:                 "0.9",
+				QuoteID:              quote.QuoteID.String(),
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.TransferResponse)
+
+				// the source, the fx account of each currency and the destination
+				if len(gotResp.Legs) != 4 {
+					return "fx transfer should have four legs"
+				}
+
+				expResp := *exp.(*transferapp.TransferResponse)
+				expResp.TransferID = gotResp.TransferID
+				expResp.CreatedDate = gotResp.CreatedDate
+				expResp.Legs = gotResp.Legs
+				return cmp.Diff(gotResp, &expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func fxTransfer409(sd apptest.SeedData) []apptest.Table {
+	quote := sd.Quotes[0]
+
+	table := []apptest.Table{
+		{
+			Name:       "quoteused",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      quote.SourceAccountID,
+				DestinationAccountID: quote.DestinationAccountID,
+				Amount:               quote.SourceAmount.String(),
+				QuoteID:              quote.QuoteID.String(),
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.AlreadyExists, transferbus.ErrQuoteUsed.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
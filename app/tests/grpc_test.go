@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"testing"
+
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp/transferpb"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Transfer_GRPC(t *testing.T) {
+	t.Parallel()
+
+	db, client := startGRPCTest(t, "Test_Transfer_GRPC")
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		db.Teardown()
+	}()
+
+	unittest.Run(t, grpcAccounts(client), "grpc-accounts")
+	unittest.Run(t, grpcTransfers(client), "grpc-transfers")
+}
+
+// grpcError is the error returned to the client by a call, or the response
+// when the call succeeded.
+func grpcError(resp any, err error) any {
+	if err != nil {
+		return toErrorPtr(customerror.FromStatus(status.Convert(err)))
+	}
+	return resp
+}
+
+func cmpGRPCError(got any, exp any) string {
+	return cmp.Diff(got, exp)
+}
+
+func grpcAccounts(client transferpb.TransferServiceClient) []unittest.Table {
+	table := []unittest.Table{
+		{
+			Name:    "create",
+			ExpResp: []string{"USD", "active", "customer", "100", "100"},
+			ExcFunc: func(ctx context.Context) any {
+				acc, err := client.CreateAccount(ctx, &transferpb.CreateAccountRequest{
+					AccountId:      20000,
+					InitialBalance: "100",
+				})
+				if err != nil {
+					return err
+				}
+
+				acc, err = client.GetBalance(ctx, &transferpb.GetBalanceRequest{AccountId: 20000})
+				if err != nil {
+					return err
+				}
+				return []string{acc.GetCurrency(), acc.GetStatus(), acc.GetType(), acc.GetBalance(), acc.GetAvailableBalance()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]string)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+				return cmp.Diff(gotResp, exp)
+			},
+		},
+		{
+			Name:    "alreadyexists",
+			ExpResp: toErrorPtr(customerror.New(customerror.AlreadyExists, transferbus.ErrAccAlreadyExist)),
+			ExcFunc: func(ctx context.Context) any {
+				return grpcError(client.CreateAccount(ctx, &transferpb.CreateAccountRequest{
+					AccountId:      20000,
+					InitialBalance: "100",
+				}))
+			},
+			CmpFunc: cmpGRPCError,
+		},
+		{
+			Name:    "invalidargument",
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid account id")),
+			ExcFunc: func(ctx context.Context) any {
+				return grpcError(client.GetBalance(ctx, &transferpb.GetBalanceRequest{}))
+			},
+			CmpFunc: cmpGRPCError,
+		},
+		{
+			Name:    "notfound",
+			ExpResp: toErrorPtr(customerror.New(customerror.NotFound, transferbus.ErrAccNotFound)),
+			ExcFunc: func(ctx context.Context) any {
+				return grpcError(client.GetBalance(ctx, &transferpb.GetBalanceRequest{AccountId: 999999}))
+			},
+			CmpFunc: cmpGRPCError,
+		},
+	}
+
+	return table
+}
+
+func grpcTransfers(client transferpb.TransferServiceClient) []unittest.Table {
+	table := []unittest.Table{
+		{
+			Name:    "create",
+			ExpResp: []string{"70", "-30", "30"},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := client.CreateAccount(ctx, &transferpb.CreateAccountRequest{
+					AccountId:      20001,
+					InitialBalance: "0",
+				}); err != nil {
+					return err
+				}
+
+				transfer, err := client.CreateTransfer(ctx, &transferpb.CreateTransferRequest{
+					SourceAccountId:      20000,
+					DestinationAccountId: 20001,
+					Amount:               "30",
+				})
+				if err != nil {
+					return err
+				}
+
+				resp := []string{transfer.GetSourceBalance()}
+				for _, leg := range transfer.GetLegs() {
+					resp = append(resp, leg.GetAmount())
+				}
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]string)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+				return cmp.Diff(gotResp, exp)
+			},
+		},
+		{
+			Name:    "list",
+			ExpResp: []string{"-30", "100"},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := client.ListTransactions(ctx, &transferpb.ListTransactionsRequest{
+					AccountId: 20000,
+				})
+				if err != nil {
+					return err
+				}
+				if resp.GetNextCursor() != 0 {
+					return fmt.Errorf("next cursor %d on the last page", resp.GetNextCursor())
+				}
+
+				var amounts []string
+				for _, item := range resp.GetItems() {
+					amounts = append(amounts, item.GetAmount())
+				}
+				return amounts
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]string)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+				return cmp.Diff(gotResp, exp)
+			},
+		},
+		{
+			Name:    "invalidlimit",
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "limit must be between 1 and 200")),
+			ExcFunc: func(ctx context.Context) any {
+				return grpcError(client.ListTransactions(ctx, &transferpb.ListTransactionsRequest{
+					AccountId: 20000,
+					Limit:     500,
+				}))
+			},
+			CmpFunc: cmpGRPCError,
+		},
+		{
+			Name:    "insufficientfunds",
+			ExpResp: customerror.FailedPrecondition,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := client.CreateTransfer(ctx, &transferpb.CreateTransferRequest{
+					SourceAccountId:      20001,
+					DestinationAccountId: 20000,
+					Amount:               "1000",
+				})
+				if err == nil {
+					return fmt.Errorf("transfer posted beyond the balance")
+				}
+				return customerror.FromStatus(status.Convert(err)).Code
+			},
+			CmpFunc: cmpGRPCError,
+		},
+		{
+			Name:    "invalidtransfer",
+			ExpResp: customerror.FailedPrecondition,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := client.CreateTransfer(ctx, &transferpb.CreateTransferRequest{
+					DestinationAccountId: 20000,
+					Amount:               "10",
+				})
+				if err == nil {
+					return fmt.Errorf("transfer posted without a source account")
+				}
+				return customerror.FromStatus(status.Convert(err)).Code
+			},
+			CmpFunc: cmpGRPCError,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func hold201(sd apptest.SeedData) []apptest.Table {
+	hold := sd.Holds[0]
+
+	table := []apptest.Table{
+		{
+			Name:       "authorize",
+			URL:        "/holds",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.HoldRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "5.0",
+				ExpiresIn:            "15m",
+			},
+			GotResp: &transferapp.HoldResponse{},
+			ExpResp: &transferapp.HoldResponse{
+				SourceAccountID:      strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				DestinationAccountID: strconv.FormatInt(sd.Accounts[1].AccountID, 10),
+				Currency:             "USD",
+				Amount:               "5",
+				CapturedAmount:       "0",
+				Status:               string(transferbus.HoldStatusAuthorized),
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.HoldResponse)
+				if gotResp.HoldID == "" || gotResp.ExpiresDate == "" {
+					return "hold id and expiry should be set"
+				}
+
+				expResp := *exp.(*transferapp.HoldResponse)
+				expResp.HoldID = gotResp.HoldID
+				expResp.CreatedDate = gotResp.CreatedDate
+				expResp.ExpiresDate = gotResp.ExpiresDate
+				return cmp.Diff(gotResp, &expResp)
+			},
+		},
+		{
+			Name:       "capture",
+			URL:        "/holds/" + hold.HoldID.String() + "/capture",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.CaptureRequest{
+				Amount: "1.5",
+			},
+			GotResp: &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeCapture),
+				SourceAccountID:      strconv.FormatInt(hold.SourceAccountID, 10),
+				DestinationAccountID: strconv.FormatInt(hold.DestinationAccountID, 10),
+				Amount:               "1.5",
+				Currency:             "USD",
+				DestinationAmount:    "1.5",
+				DestinationCurrency:  "USD",
+				Rate:                 "1",
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+	}
+
+	return table
+}
+
+func hold200(sd apptest.SeedData) []apptest.Table {
+	hold := sd.Holds[1]
+
+	expResp := &transferapp.HoldResponse{
+		HoldID:               hold.HoldID.String(),
+		SourceAccountID:      strconv.FormatInt(hold.SourceAccountID, 10),
+		DestinationAccountID: strconv.FormatInt(hold.DestinationAccountID, 10),
+		Currency:             "USD",
+		Amount:               "2",
+		CapturedAmount:       "0",
+		Status:               string(transferbus.HoldStatusAuthorized),
+	}
+
+	table := []apptest.Table{
+		{
+			Name:       "query",
+			URL:        "/holds/" + hold.HoldID.String(),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.HoldResponse{},
+			ExpResp:    expResp,
+			CmpFunc:    cmpHoldResponse,
+		},
+		{
+			Name:       "void",
+			URL:        "/holds/" + hold.HoldID.String() + "/void",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.HoldResponse{},
+			ExpResp: func() *transferapp.HoldResponse {
+				voided := *expResp
+				voided.Status = string(transferbus.HoldStatusVoided)
+				return &voided
+			}(),
+			CmpFunc: cmpHoldResponse,
+		},
+	}
+
+	return table
+}
+
+func hold400(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "invalidexpiry",
+			URL:        "/holds",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.HoldRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "5.0",
+				ExpiresIn:            "soon",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "expires_in must be a positive duration")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "insufficientfunds",
+			URL:        "/holds",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.HoldRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "1000000.0",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: insufficientFunds("%s", sd.Accounts[0].AccountID),
+			CmpFunc: cmpInsufficientFunds,
+		},
+		{
+			Name:       "closed",
+			URL:        "/holds/" + sd.Holds[1].HoldID.String() + "/capture",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.FailedPrecondition, transferbus.ErrHoldClosed.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func hold404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "holdnotfound",
+			URL:        "/holds/" + uuid.NewString() + "/capture",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrHoldNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// cmpHoldResponse compares hold responses ignoring their dates, which must
+// still be populated.
+func cmpHoldResponse(got any, exp any) string {
+	gotResp := got.(*transferapp.HoldResponse)
+	if gotResp.CreatedDate == "" || gotResp.ExpiresDate == "" {
+		return "hold dates should be set"
+	}
+
+	expResp := *exp.(*transferapp.HoldResponse)
+	expResp.CreatedDate = gotResp.CreatedDate
+	expResp.ExpiresDate = gotResp.ExpiresDate
+	return cmp.Diff(gotResp, &expResp)
+}
@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func limits200(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "account",
+			URL:        fmt.Sprintf("/admin/accounts/%d/limits", sd.Accounts[5].AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.TransferLimitsRequest{
+				MaxAmount:   "5",
+				DailyAmount: "100.50",
+			},
+			GotResp: &transferapp.TransferLimitsResponse{},
+			ExpResp: &transferapp.TransferLimitsResponse{
+				MaxAmount:     "5",
+				DailyAmount:   "100.5",
+				MonthlyAmount: "0",
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "accounttype",
+			URL:        "/admin/account-types/suspense/limits",
+			Method:     http.MethodPut,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.AccountTypeLimitsRequest{
+				Currency: "EUR",
+				TransferLimitsRequest: transferapp.TransferLimitsRequest{
+					MonthlyAmount: "1000000",
+				},
+			},
+			GotResp: &transferapp.TransferLimitsResponse{},
+			ExpResp: &transferapp.TransferLimitsResponse{
+				MaxAmount:     "0",
+				DailyAmount:   "0",
+				MonthlyAmount: "1000000",
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func limits400(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "invalidamount",
+			URL:        fmt.Sprintf("/admin/accounts/%d/limits", sd.Accounts[5].AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.TransferLimitsRequest{
+				DailyAmount: "plenty",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid daily_amount")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "negativeamount",
+			URL:        fmt.Sprintf("/admin/accounts/%d/limits", sd.Accounts[5].AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.TransferLimitsRequest{
+				MaxAmount: "-5",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrNegativeLimit.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidcurrency",
+			URL:        "/admin/account-types/suspense/limits",
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.AccountTypeLimitsRequest{
+				Currency: "ZZZ",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid currency \"ZZZ\"")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func limits404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "accountnotfound",
+			URL:        "/admin/accounts/999999/limits",
+			Method:     http.MethodPut,
+			StatusCode: http.StatusNotFound,
+			Input:      &transferapp.TransferLimitsRequest{},
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "unknowntype",
+			URL:        "/admin/account-types/savings/limits",
+			Method:     http.MethodPut,
+			StatusCode: http.StatusNotFound,
+			Input:      &transferapp.TransferLimitsRequest{},
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrUnknownAccountType.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func limits429(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "maxamount",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusTooManyRequests,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[5].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "6",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.ResourceExhausted, "%s: single transfer limit 5", transferbus.ErrLimitExceeded)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
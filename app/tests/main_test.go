@@ -1,19 +1,29 @@
 package tests
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
 	"github.com/danipurwadi/internal-transfer-system/app/api/middleware"
 	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp/transferpb"
 	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
 	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
 	"github.com/danipurwadi/internal-transfer-system/foundation/docker"
 	"github.com/danipurwadi/internal-transfer-system/foundation/web"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 var c *docker.Container
@@ -42,10 +52,11 @@ func run(m *testing.M) (int, error) {
 func startTest(t *testing.T, testName string) *apptest.Test {
 	db := dbtest.NewDatabase(t, c, testName)
 
-	dbClient := transferdb.NewTxQueries(db.DB)
+	dbClient := transferdb.NewTxQueries(db.DB, transferdb.DefaultRetryConfig)
 	// -------------------------------------------------------------------------
 	// initialise business layer
-	transferBus := transferbus.New(dbClient, db.Log)
+	fxBus := fxbus.New(fxbus.TestRates(), time.Minute)
+	transferBus := transferbus.New(dbClient, fxBus, transferbus.DefaultIsolation, db.Log)
 
 	// initialise app layer
 	transferApp := transferapp.NewApp(transferBus)
@@ -54,6 +65,58 @@ func startTest(t *testing.T, testName string) *apptest.Test {
 	return apptest.New(db, webClient)
 }
 
+// startGRPCTest serves the gRPC API over an in-memory connection and returns a
+// client calling it.
+func startGRPCTest(t *testing.T, testName string) (*dbtest.Database, transferpb.TransferServiceClient) {
+	db := dbtest.NewDatabase(t, c, testName)
+
+	dbClient := transferdb.NewTxQueries(db.DB, transferdb.DefaultRetryConfig)
+	fxBus := fxbus.New(fxbus.TestRates(), time.Minute)
+	transferBus := transferbus.New(dbClient, fxBus, transferbus.DefaultIsolation, db.Log)
+	transferApp := transferapp.NewApp(transferBus)
+
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(middleware.GRPCLogger(db.Log), middleware.GRPCErrors(db.Log)))
+	transferApp.RegisterGRPC(grpcServer)
+
+	lis := bufconn.Listen(1024 * 1024)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Should be able to connect to the grpc server : %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return db, transferpb.NewTransferServiceClient(conn)
+}
+
 func toErrorPtr(err customerror.Error) *customerror.Error {
 	return &err
 }
+
+// withoutAvailableCredit drops the available credit reported by insufficient
+// funds errors, which depends on the balances left behind by earlier tests.
+func withoutAvailableCredit(err *customerror.Error) *customerror.Error {
+	if err == nil {
+		return nil
+	}
+	trimmed := *err
+	trimmed.Message, _, _ = strings.Cut(err.Message, ": available credit ")
+	return &trimmed
+}
+
+// insufficientFunds is the error of a debit the account cannot cover, without
+// its available credit.
+func insufficientFunds(format string, accountID int64) *customerror.Error {
+	return toErrorPtr(customerror.Newf(customerror.FailedPrecondition, format, fmt.Sprintf("%s: account[%d]", transferbus.ErrInsufficientFunds, accountID)))
+}
+
+func cmpInsufficientFunds(got any, exp any) string {
+	return cmp.Diff(withoutAvailableCredit(got.(*customerror.Error)), exp)
+}
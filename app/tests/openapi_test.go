@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/foundation/openapi"
+	"github.com/danipurwadi/internal-transfer-system/foundation/web"
+)
+
+// Test_OpenAPI_Routes fails when a route is registered without being described
+// in the OpenAPI document, or the document describes a route that is gone.
+func Test_OpenAPI_Routes(t *testing.T) {
+	t.Parallel()
+
+	mux := web.NewClient()
+	transferapp.NewApp(nil).Routes(mux)
+	doc := transferapp.OpenAPI()
+
+	registered := make(map[web.Route]bool)
+	for _, route := range mux.Routes() {
+		registered[route] = true
+
+		if doc.Operation(route.Method, route.Path) == nil {
+			t.Errorf("%s %s: route should be described in the openapi document", route.Method, route.Path)
+		}
+	}
+
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			route := web.Route{Method: strings.ToUpper(method), Path: path}
+			if !registered[route] {
+				t.Errorf("%s %s: described route should be registered", route.Method, route.Path)
+			}
+		}
+	}
+}
+
+func openAPI200() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "document",
+			URL:        "/openapi.json",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &openapi.Document{},
+			ExpResp:    transferapp.OpenAPI(),
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*openapi.Document)
+				expResp := exp.(*openapi.Document)
+
+				if gotResp.OpenAPI != openapi.Version {
+					return fmt.Sprintf("openapi version %q, expected %q", gotResp.OpenAPI, openapi.Version)
+				}
+				if len(gotResp.Paths) != len(expResp.Paths) {
+					return fmt.Sprintf("%d paths, expected %d", len(gotResp.Paths), len(expResp.Paths))
+				}
+
+				op := gotResp.Operation(http.MethodPost, "/transactions")
+				if op == nil || op.Responses["400"].Content["application/json"].Schema.Ref != "#/components/schemas/Error" {
+					return "POST /transactions should answer bad requests with an Error"
+				}
+
+				amount := gotResp.Components.Schemas["TransactionRequest"].Properties["amount"]
+				if amount == nil || amount.MinLength == nil || *amount.MinLength != 1 {
+					return "the required amount of a TransactionRequest should not be empty"
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
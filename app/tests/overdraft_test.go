@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+type overdraftResult struct {
+	Balance         string
+	OverdraftLimit  string
+	AvailableCredit string
+}
+
+func cmpOverdraft(got any, exp any) string {
+	gotResp := got.(*transferapp.BalanceResponse)
+	return cmp.Diff(overdraftResult{
+		Balance:         gotResp.Balance,
+		OverdraftLimit:  gotResp.OverdraftLimit,
+		AvailableCredit: gotResp.AvailableCredit,
+	}, exp)
+}
+
+func overdraft200(sd apptest.SeedData) []apptest.Table {
+	acc := sd.Accounts[5]
+
+	table := []apptest.Table{
+		{
+			Name:       "setlimit",
+			URL:        fmt.Sprintf("/admin/accounts/%d/overdraft-limit", acc.AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.OverdraftLimitRequest{
+				OverdraftLimit: "50",
+			},
+			GotResp: &transferapp.BalanceResponse{},
+			ExpResp: overdraftResult{Balance: "10", OverdraftLimit: "50", AvailableCredit: "60"},
+			CmpFunc: cmpOverdraft,
+		},
+		{
+			Name:       "transferintooverdraft",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      acc.AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "40",
+			},
+			GotResp: &transferapp.TransferCreatedResponse{},
+			ExpResp: "-30",
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got.(*transferapp.TransferCreatedResponse).SourceBalance, exp)
+			},
+		},
+		{
+			Name:       "removelimit",
+			URL:        fmt.Sprintf("/admin/accounts/%d/overdraft-limit", sd.Accounts[1].AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.OverdraftLimitRequest{
+				OverdraftLimit: "0",
+			},
+			GotResp: &transferapp.BalanceResponse{},
+			ExpResp: "",
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got.(*transferapp.BalanceResponse).OverdraftLimit, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func overdraft400(sd apptest.SeedData) []apptest.Table {
+	acc := sd.Accounts[5]
+
+	table := []apptest.Table{
+		{
+			Name:       "beyondlimit",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      acc.AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "21",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "%s: account[%d]: available credit 20", transferbus.ErrInsufficientFunds, acc.AccountID)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "limitinuse",
+			URL:        fmt.Sprintf("/admin/accounts/%d/overdraft-limit", acc.AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.OverdraftLimitRequest{
+				OverdraftLimit: "20",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, transferbus.ErrOverdraftInUse.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "negativelimit",
+			URL:        fmt.Sprintf("/admin/accounts/%d/overdraft-limit", acc.AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.OverdraftLimitRequest{
+				OverdraftLimit: "-1",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrNegativeOverdraft.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidlimit",
+			URL:        fmt.Sprintf("/admin/accounts/%d/overdraft-limit", acc.AccountID),
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.OverdraftLimitRequest{
+				OverdraftLimit: "lots",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid overdraft_limit")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func overdraft404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "accountnotfound",
+			URL:        "/admin/accounts/999999/overdraft-limit",
+			Method:     http.MethodPut,
+			StatusCode: http.StatusNotFound,
+			Input: &transferapp.OverdraftLimitRequest{
+				OverdraftLimit: "10",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
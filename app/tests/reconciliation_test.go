@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func reconciliation200() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "balanced",
+			URL:        "/admin/reconciliation?batch_size=1",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			Input:      nil,
+			GotResp:    &transferapp.ReconciliationResponse{},
+			ExpResp: &transferapp.ReconciliationResponse{
+				Balanced: true,
+				Drifts:   []transferapp.AccountDriftResponse{},
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.ReconciliationResponse)
+				expResp := exp.(*transferapp.ReconciliationResponse)
+
+				// every seeded account and the equity account must be scanned
+				if gotResp.AccountsScanned < 3 {
+					return "expected the seeded accounts to be scanned"
+				}
+				// every currency must sum to zero
+				for cur, total := range gotResp.TotalBalances {
+					if total != "0" {
+						return fmt.Sprintf("expected %s to sum to zero, got %s", cur, total)
+					}
+				}
+				expResp.AccountsScanned = gotResp.AccountsScanned
+				expResp.TotalBalances = gotResp.TotalBalances
+				expResp.ReconciledDate = gotResp.ReconciledDate
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func reconciliation400() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "invalidbatchsize",
+			URL:        "/admin/reconciliation?batch_size=0",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "batch_size must be between 1 and 10000")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidalert",
+			URL:        "/admin/reconciliation?alert=maybe",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "alert must be a boolean")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
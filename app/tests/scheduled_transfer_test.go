@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// cmpScheduledTransfer compares the responses without the ids and dates set by
+// the service.
+func cmpScheduledTransfer(got any, exp any) string {
+	gotResp := got.(*transferapp.ScheduledTransferResponse)
+	if gotResp.ScheduleID == "" || gotResp.CreatedDate == "" {
+		return "schedule id and created date should be set"
+	}
+
+	expResp := *exp.(*transferapp.ScheduledTransferResponse)
+	expResp.ScheduleID = gotResp.ScheduleID
+	expResp.CreatedDate = gotResp.CreatedDate
+	if expResp.NextRunDate == "" {
+		expResp.NextRunDate = gotResp.NextRunDate
+	}
+	return cmp.Diff(gotResp, &expResp)
+}
+
+func scheduledTransfer201(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "recurring",
+			URL:        "/scheduled-transfers",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.ScheduledTransferRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "12.50",
+				Recurrence:           "0 9 * * 1-5",
+				StartDate:            "2030-01-01T09:00:00Z",
+			},
+			GotResp: &transferapp.ScheduledTransferResponse{},
+			ExpResp: &transferapp.ScheduledTransferResponse{
+				SourceAccountID:      strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				DestinationAccountID: strconv.FormatInt(sd.Accounts[1].AccountID, 10),
+				Currency:             "USD",
+				Amount:               "12.5",
+				Recurrence:           "0 9 * * 1-5",
+				Status:               string(transferbus.ScheduleStatusScheduled),
+				NextRunDate:          "2030-01-01T09:00:00Z",
+			},
+			CmpFunc: cmpScheduledTransfer,
+		},
+	}
+
+	return table
+}
+
+func scheduledTransfer200(sd apptest.SeedData) []apptest.Table {
+	st := sd.Schedules[0]
+	url := "/scheduled-transfers/" + st.ScheduleID.String()
+
+	exp := transferapp.ScheduledTransferResponse{
+		SourceAccountID:      strconv.FormatInt(st.SourceAccountID, 10),
+		DestinationAccountID: strconv.FormatInt(st.DestinationAccountID, 10),
+		Currency:             st.Currency.String(),
+		Amount:               st.Amount.String(),
+		Recurrence:           st.Recurrence,
+		Status:               string(transferbus.ScheduleStatusScheduled),
+	}
+
+	updated := exp
+	updated.Amount = "7.25"
+
+	cancelled := updated
+	cancelled.Status = string(transferbus.ScheduleStatusCancelled)
+
+	amount := "7.25"
+
+	table := []apptest.Table{
+		{
+			Name:       "query",
+			URL:        url,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.ScheduledTransferResponse{},
+			ExpResp:    &exp,
+			CmpFunc:    cmpScheduledTransfer,
+		},
+		{
+			Name:       "update",
+			URL:        url,
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.ScheduledTransferUpdateRequest{
+				Amount: &amount,
+			},
+			GotResp: &transferapp.ScheduledTransferResponse{},
+			ExpResp: &updated,
+			CmpFunc: cmpScheduledTransfer,
+		},
+		{
+			Name:       "cancel",
+			URL:        url,
+			Method:     http.MethodDelete,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.ScheduledTransferResponse{},
+			ExpResp:    &cancelled,
+			CmpFunc:    cmpScheduledTransfer,
+		},
+	}
+
+	return table
+}
+
+func scheduledTransfer400(sd apptest.SeedData) []apptest.Table {
+	amount := "1"
+
+	table := []apptest.Table{
+		{
+			Name:       "invalidrecurrence",
+			URL:        "/scheduled-transfers",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.ScheduledTransferRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "1",
+				Recurrence:           "every day",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "%s: cron expression \"every day\" must have 5 fields", transferbus.ErrInvalidRecurrence)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidstartdate",
+			URL:        "/scheduled-transfers",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.ScheduledTransferRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "1",
+				StartDate:            "tomorrow",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "start_date must be an RFC3339 timestamp")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "sameaccount",
+			URL:        "/scheduled-transfers",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.ScheduledTransferRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[0].AccountID,
+				Amount:               "1",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrSameAccount.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "cancelled",
+			URL:        "/scheduled-transfers/" + sd.Schedules[0].ScheduleID.String(),
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.ScheduledTransferUpdateRequest{
+				Amount: &amount,
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, transferbus.ErrScheduleClosed.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidscheduleid",
+			URL:        "/scheduled-transfers/abc",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid schedule id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func scheduledTransfer404(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "schedulenotfound",
+			URL:        "/scheduled-transfers/" + uuid.NewString(),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrScheduleNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "accountnotfound",
+			URL:        "/scheduled-transfers",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			Input: &transferapp.ScheduledTransferRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: 999999,
+				Amount:               "1",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+)
+
+func transactionBatch200(sd apptest.SeedData) []apptest.Table {
+	transfer := transferapp.TransactionRequest{
+		SourceAccountID:      sd.Accounts[0].AccountID,
+		DestinationAccountID: sd.Accounts[1].AccountID,
+		Amount:               "1.0",
+	}
+	overdraft := transfer
+	overdraft.Amount = "1000000.0"
+
+	table := []apptest.Table{
+		{
+			Name:       "atomic",
+			URL:        "/transactions:batch",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.BatchRequest{
+				Transactions: []transferapp.TransactionRequest{transfer, transfer},
+			},
+			GotResp: &transferapp.BatchResponse{},
+			ExpResp: &transferapp.BatchResponse{
+				Mode:      string(transferbus.BatchModeAtomic),
+				Succeeded: 2,
+				Results: []transferapp.BatchItemResponse{
+					{Index: 0, Status: "succeeded"},
+					{Index: 1, Status: "succeeded"},
+				},
+			},
+			CmpFunc: cmpBatchResponse,
+		},
+		{
+			Name:       "besteffort",
+			URL:        "/transactions:batch",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.BatchRequest{
+				Mode:         string(transferbus.BatchModeBestEffort),
+				Transactions: []transferapp.TransactionRequest{transfer, overdraft, transfer},
+			},
+			GotResp: &transferapp.BatchResponse{},
+			ExpResp: &transferapp.BatchResponse{
+				Mode:      string(transferbus.BatchModeBestEffort),
+				Succeeded: 2,
+				Failed:    1,
+				Results: []transferapp.BatchItemResponse{
+					{Index: 0, Status: "succeeded"},
+					{Index: 1, Status: "failed", Error: insufficientFunds("%s", sd.Accounts[0].AccountID)},
+					{Index: 2, Status: "succeeded"},
+				},
+			},
+			CmpFunc: cmpBatchResponse,
+		},
+	}
+
+	return table
+}
+
+func transactionBatch400(sd apptest.SeedData) []apptest.Table {
+	transfer := transferapp.TransactionRequest{
+		SourceAccountID:      sd.Accounts[0].AccountID,
+		DestinationAccountID: sd.Accounts[1].AccountID,
+		Amount:               "1.0",
+	}
+	overdraft := transfer
+	overdraft.Amount = "1000000.0"
+
+	table := []apptest.Table{
+		{
+			Name:       "atomicfailure",
+			URL:        "/transactions:batch",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.BatchRequest{
+				Transactions: []transferapp.TransactionRequest{transfer, overdraft},
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: insufficientFunds("transactions[1]: %s", sd.Accounts[0].AccountID),
+			CmpFunc: cmpInsufficientFunds,
+		},
+		{
+			Name:       "invaliditem",
+			URL:        "/transactions:batch",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.BatchRequest{
+				Transactions: []transferapp.TransactionRequest{transfer, {DestinationAccountID: sd.Accounts[1].AccountID, Amount: "1.0"}},
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "transactions[1]: validate: [{\"field\":\"source_account_id\",\"error\":\"source_account_id is a required field\"}]")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidmode",
+			URL:        "/transactions:batch",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.BatchRequest{
+				Mode:         "eventually",
+				Transactions: []transferapp.TransactionRequest{transfer},
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "mode must be atomic or best_effort")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "empty",
+			URL:        "/transactions:batch",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      &transferapp.BatchRequest{},
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "transactions must hold between 1 and 5000 items")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// cmpBatchResponse compares batch responses, checking that every succeeded
+// item carries its transfer without comparing the transfers themselves.
+func cmpBatchResponse(got any, exp any) string {
+	gotResp := *got.(*transferapp.BatchResponse)
+	gotResp.Results = append([]transferapp.BatchItemResponse(nil), gotResp.Results...)
+
+	for i, item := range gotResp.Results {
+		if (item.Status == "succeeded") != (item.Transfer != nil) {
+			return fmt.Sprintf("results[%d]: transfer should be set exactly when the item succeeded", i)
+		}
+		gotResp.Results[i].Transfer = nil
+		gotResp.Results[i].Error = withoutAvailableCredit(item.Error)
+	}
+
+	return cmp.Diff(&gotResp, exp)
+}
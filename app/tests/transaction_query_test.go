@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func transactionQuery200(sd apptest.SeedData) []apptest.Table {
+	transfer := sd.Transfers[0]
+
+	table := []apptest.Table{
+		{
+			Name:       "transfer",
+			URL:        "/transactions/" + transfer.TransferID.String(),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			Input:      nil,
+			GotResp:    &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				TransferID:           transfer.TransferID.String(),
+				EntryType:            string(transferbus.EntryTypeTransfer),
+				SourceAccountID:      strconv.FormatInt(transfer.SourceAccountID, 10),
+				DestinationAccountID: strconv.FormatInt(transfer.DestinationAccountID, 10),
+				Amount:               transfer.Amount.String(),
+				Currency:             transfer.Currency.String(),
+				DestinationAmount:    transfer.DestinationAmount.String(),
+				DestinationCurrency:  transfer.DestinationCurrency.String(),
+				Rate:                 transfer.Rate.String(),
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+		{
+			Name:       "debits",
+			URL:        fmt.Sprintf("/accounts/%d/transactions?direction=debit", transfer.SourceAccountID),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			Input:      nil,
+			GotResp:    &transferapp.TransactionHistoryResponse{},
+			ExpResp:    transfer.TransferID.String(),
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.TransactionHistoryResponse)
+
+				// the seeded transfer must be listed among debits only
+				var found bool
+				for _, item := range gotResp.Items {
+					if item.Amount[0] != '-' {
+						return fmt.Sprintf("expected debits only, got %s", item.Amount)
+					}
+					found = found || item.TransferID == exp
+				}
+				if !found {
+					return fmt.Sprintf("transfer %s not found in history", exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:       "paginated",
+			URL:        fmt.Sprintf("/accounts/%d/transactions?limit=1", transfer.DestinationAccountID),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			Input:      nil,
+			GotResp:    &transferapp.TransactionHistoryResponse{},
+			ExpResp:    1,
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.TransactionHistoryResponse)
+				if gotResp.NextCursor == "" {
+					return "expected a cursor to the next page"
+				}
+				return cmp.Diff(len(gotResp.Items), exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func transactionQuery400() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "invalidtransferid",
+			URL:        "/transactions/invalid",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid transfer id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invaliddirection",
+			URL:        "/accounts/1/transactions?direction=sideways",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "direction must be either debit or credit")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidlimit",
+			URL:        "/accounts/1/transactions?limit=0",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "limit must be between 1 and 200")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func transactionQuery404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "transfernotfound",
+			URL:        "/transactions/" + uuid.NewString(),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrTransferNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "accountnotfound",
+			URL:        "/accounts/12345/transactions",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			Input:      nil,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
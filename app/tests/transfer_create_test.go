@@ -2,12 +2,14 @@ package tests
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
 	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
 	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
 	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
 )
 
 func transactionSubmission201(sd apptest.SeedData) []apptest.Table {
@@ -22,10 +24,86 @@ func transactionSubmission201(sd apptest.SeedData) []apptest.Table {
 				DestinationAccountID: sd.Accounts[1].AccountID,
 				Amount:               "10.0",
 			},
-			GotResp: nil,
-			ExpResp: nil,
+			GotResp: &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeTransfer),
+				SourceAccountID:      strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				DestinationAccountID: strconv.FormatInt(sd.Accounts[1].AccountID, 10),
+				Amount:               "10",
+				Currency:             "USD",
+				DestinationAmount:    "10",
+				DestinationCurrency:  "USD",
+				Rate:                 "1",
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+		{
+			Name:       "idempotencykey",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "10.0",
+				IdempotencyKey:       "transaction-submission-201",
+			},
+			GotResp: &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeTransfer),
+				SourceAccountID:      strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				DestinationAccountID: strconv.FormatInt(sd.Accounts[1].AccountID, 10),
+				Amount:               "10",
+				Currency:             "USD",
+				DestinationAmount:    "10",
+				DestinationCurrency:  "USD",
+				Rate:                 "1",
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+		{
+			Name:       "idempotentreplay",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "10.0",
+				IdempotencyKey:       "transaction-submission-201",
+			},
+			GotResp: &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeTransfer),
+				SourceAccountID:      strconv.FormatInt(sd.Accounts[0].AccountID, 10),
+				DestinationAccountID: strconv.FormatInt(sd.Accounts[1].AccountID, 10),
+				Amount:               "10",
+				Currency:             "USD",
+				DestinationAmount:    "10",
+				DestinationCurrency:  "USD",
+				Rate:                 "1",
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+		{
+			Name:       "sourcebalance",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "1",
+			},
+			GotResp: &transferapp.TransferCreatedResponse{},
+			// the two transfers above and this one moved 21 out of the account
+			ExpResp: sd.Accounts[0].Balance.Sub(decimal.NewFromInt(21)).String(),
 			CmpFunc: func(got any, exp any) string {
-				return cmp.Diff(got, exp)
+				gotResp := got.(*transferapp.TransferCreatedResponse)
+				if gotResp.TransferID == "" {
+					return "transfer id should be set"
+				}
+				return cmp.Diff(gotResp.SourceBalance, exp)
 			},
 		},
 	}
@@ -67,6 +145,22 @@ func transactionSubmission400(sd apptest.SeedData) []apptest.Table {
 				return cmp.Diff(got, exp)
 			},
 		},
+		{
+			Name:       "zeroamount",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "0.001",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrZeroAmount.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 		{
 			Name:       "insufficientfunds",
 			URL:        "/transactions",
@@ -78,7 +172,22 @@ func transactionSubmission400(sd apptest.SeedData) []apptest.Table {
 				Amount:               "1000000.0",
 			},
 			GotResp: &customerror.Error{},
-			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, transferbus.ErrInsufficientFunds.Error())),
+			ExpResp: insufficientFunds("%s", sd.Accounts[0].AccountID),
+			CmpFunc: cmpInsufficientFunds,
+		},
+		{
+			Name:       "currencymismatch",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "10.0",
+				Currency:             "EUR",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.FailedPrecondition, "%s: transfer in EUR between USD accounts", transferbus.ErrCurrencyMismatch)),
 			CmpFunc: func(got any, exp any) string {
 				return cmp.Diff(got, exp)
 			},
@@ -126,3 +235,46 @@ func transactionSubmission404(sd apptest.SeedData) []apptest.Table {
 
 	return table
 }
+
+func transactionSubmission409(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "idempotencykeyreused",
+			URL:        "/transactions",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input: &transferapp.TransactionRequest{
+				SourceAccountID:      sd.Accounts[0].AccountID,
+				DestinationAccountID: sd.Accounts[1].AccountID,
+				Amount:               "20.0",
+				IdempotencyKey:       "transaction-submission-201",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.AlreadyExists, transferbus.ErrIdempotencyKeyReused.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// cmpTransferResponse compares transfer responses ignoring the server
+// generated fields, which must still be populated.
+func cmpTransferResponse(got any, exp any) string {
+	gotResp := got.(*transferapp.TransferResponse)
+	if gotResp.TransferID == "" || gotResp.CreatedDate == "" {
+		return "transfer id and created date should be set"
+	}
+
+	if len(gotResp.Legs) != 2 {
+		return "transfer should have a debit and a credit leg"
+	}
+
+	expResp := *exp.(*transferapp.TransferResponse)
+	expResp.TransferID = gotResp.TransferID
+	expResp.CreatedDate = gotResp.CreatedDate
+	expResp.Legs = gotResp.Legs
+	return cmp.Diff(gotResp, &expResp)
+}
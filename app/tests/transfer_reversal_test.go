@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func transferReversal201(sd apptest.SeedData) []apptest.Table {
+	transfer := sd.Transfers[0]
+	url := "/transactions/" + transfer.TransferID.String() + "/reversal"
+
+	table := []apptest.Table{
+		{
+			Name:       "partial",
+			URL:        url,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.ReversalRequest{
+				Amount: "0.4",
+			},
+			GotResp: &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeReversal),
+				SourceAccountID:      strconv.FormatInt(transfer.DestinationAccountID, 10),
+				DestinationAccountID: strconv.FormatInt(transfer.SourceAccountID, 10),
+				Amount:               "0.4",
+				Currency:             "USD",
+				DestinationAmount:    "0.4",
+				DestinationCurrency:  "USD",
+				Rate:                 "1",
+				ReversalOf:           transfer.TransferID.String(),
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+		{
+			Name:       "remainder",
+			URL:        url,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			GotResp:    &transferapp.TransferResponse{},
+			ExpResp: &transferapp.TransferResponse{
+				EntryType:            string(transferbus.EntryTypeReversal),
+				SourceAccountID:      strconv.FormatInt(transfer.DestinationAccountID, 10),
+				DestinationAccountID: strconv.FormatInt(transfer.SourceAccountID, 10),
+				Amount:               "0.6",
+				Currency:             "USD",
+				DestinationAmount:    "0.6",
+				DestinationCurrency:  "USD",
+				Rate:                 "1",
+				ReversalOf:           transfer.TransferID.String(),
+			},
+			CmpFunc: cmpTransferResponse,
+		},
+	}
+
+	return table
+}
+
+func transferReversal400(sd apptest.SeedData) []apptest.Table {
+	transfer := sd.Transfers[0]
+
+	table := []apptest.Table{
+		{
+			Name:       "invalidid",
+			URL:        "/transactions/abc/reversal",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid transfer id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "fullyreversed",
+			URL:        "/transactions/" + transfer.TransferID.String() + "/reversal",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.ReversalRequest{
+				Amount: "0.01",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrReversalExceeded.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "zeroamount",
+			URL:        "/transactions/" + transfer.TransferID.String() + "/reversal",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.ReversalRequest{
+				Amount: "0.001",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrZeroAmount.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func transferReversal404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "transfernotfound",
+			URL:        "/transactions/" + uuid.NewString() + "/reversal",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrTransferNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
 	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/shopspring/decimal"
 )
 
 func Test_Transfer_App(t *testing.T) {
@@ -45,6 +48,64 @@ func Test_Transfer_App(t *testing.T) {
 	apiTest.Run(t, transactionSubmission201(sd), "transaction-submission-201")
 	apiTest.Run(t, transactionSubmission400(sd), "transaction-submission-400")
 	apiTest.Run(t, transactionSubmission404(sd), "transaction-submission-404")
+	apiTest.Run(t, transactionSubmission409(sd), "transaction-submission-409")
+
+	apiTest.Run(t, transactionBatch200(sd), "transaction-batch-200")
+	apiTest.Run(t, transactionBatch400(sd), "transaction-batch-400")
+
+	apiTest.Run(t, transactionQuery200(sd), "transaction-query-200")
+	apiTest.Run(t, transactionQuery400(), "transaction-query-400")
+	apiTest.Run(t, transactionQuery404(), "transaction-query-404")
+
+	apiTest.Run(t, transferReversal201(sd), "transfer-reversal-201")
+	apiTest.Run(t, transferReversal400(sd), "transfer-reversal-400")
+	apiTest.Run(t, transferReversal404(), "transfer-reversal-404")
+
+	apiTest.Run(t, fxQuote201(sd), "fx-quote-201")
+	apiTest.Run(t, fxQuote400(sd), "fx-quote-400")
+	apiTest.Run(t, fxQuote404(sd), "fx-quote-404")
+	apiTest.Run(t, fxTransfer201(sd), "fx-transfer-201")
+	apiTest.Run(t, fxTransfer409(sd), "fx-transfer-409")
+
+	apiTest.Run(t, hold201(sd), "hold-201")
+	apiTest.Run(t, hold200(sd), "hold-200")
+	apiTest.Run(t, hold400(sd), "hold-400")
+	apiTest.Run(t, hold404(), "hold-404")
+
+	apiTest.Run(t, accountStatus200(sd), "account-status-200")
+	apiTest.Run(t, accountStatus400(sd), "account-status-400")
+	apiTest.Run(t, accountStatus404(), "account-status-404")
+
+	apiTest.Run(t, accountMetadata200(sd), "account-metadata-200")
+	apiTest.Run(t, accountMetadata400(sd), "account-metadata-400")
+	apiTest.Run(t, accountMetadata404(), "account-metadata-404")
+
+	apiTest.Run(t, overdraft200(sd), "overdraft-200")
+	apiTest.Run(t, overdraft400(sd), "overdraft-400")
+	apiTest.Run(t, overdraft404(), "overdraft-404")
+
+	apiTest.Run(t, limits200(sd), "limits-200")
+	apiTest.Run(t, limits400(sd), "limits-400")
+	apiTest.Run(t, limits404(), "limits-404")
+	apiTest.Run(t, limits429(sd), "limits-429")
+
+	apiTest.Run(t, scheduledTransfer201(sd), "scheduled-transfer-201")
+	apiTest.Run(t, scheduledTransfer200(sd), "scheduled-transfer-200")
+	apiTest.Run(t, scheduledTransfer400(sd), "scheduled-transfer-400")
+	apiTest.Run(t, scheduledTransfer404(sd), "scheduled-transfer-404")
+
+	apiTest.Run(t, webhook201(sd), "webhook-201")
+	apiTest.Run(t, webhook200(sd), "webhook-200")
+	apiTest.Run(t, webhook400(sd), "webhook-400")
+	apiTest.Run(t, webhook404(), "webhook-404")
+
+	apiTest.Run(t, accountEvents400(sd), "account-events-400")
+	apiTest.Run(t, accountEvents404(), "account-events-404")
+
+	apiTest.Run(t, reconciliation200(), "reconciliation-200")
+	apiTest.Run(t, reconciliation400(), "reconciliation-400")
+
+	apiTest.Run(t, openAPI200(), "openapi-200")
 }
 
 func userSeedData(db *dbtest.Database) (apptest.SeedData, error) {
@@ -56,6 +117,22 @@ func userSeedData(db *dbtest.Database) (apptest.SeedData, error) {
 		return apptest.SeedData{}, fmt.Errorf("seeding users : %w", err)
 	}
 
+	transfer, err := busDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+		SourceAccountID:      usrs[0].AccountID,
+		DestinationAccountID: usrs[1].AccountID,
+		Amount:               decimal.NewFromInt(1),
+	})
+	if err != nil {
+		return apptest.SeedData{}, fmt.Errorf("seeding transfer : %w", err)
+	}
+
+	// refresh the balances moved by the seeded transfer
+	for i, usr := range usrs {
+		if usrs[i], err = busDomain.TransferBus.GetBalance(ctx, usr.AccountID); err != nil {
+			return apptest.SeedData{}, fmt.Errorf("querying seeded user : %w", err)
+		}
+	}
+
 	tu1 := apptest.Account{
 		Account: dbtest.Account{
 			Account: usrs[0],
@@ -67,10 +144,101 @@ func userSeedData(db *dbtest.Database) (apptest.SeedData, error) {
 			Account: usrs[1],
 		},
 	}
+
+	// a euro account and a quote to fund it from the first account
+	eurAcc, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+		AccountID:      2000,
+		Currency:       currency.EUR,
+		InitialBalance: decimal.NewFromInt(100),
+	})
+	if err != nil {
+		return apptest.SeedData{}, fmt.Errorf("seeding euro account : %w", err)
+	}
+
+	quote, err := busDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+		SourceAccountID:      usrs[0].AccountID,
+		DestinationAccountID: eurAcc.AccountID,
+		Amount:               decimal.NewFromInt(10),
+	})
+	if err != nil {
+		return apptest.SeedData{}, fmt.Errorf("seeding quote : %w", err)
+	}
+
+	tu3 := apptest.Account{
+		Account: dbtest.Account{
+			Account: eurAcc,
+		},
+	}
+
+	// one account to close with a sweep and one to close empty
+	closable := make([]apptest.Account, 2)
+	for i, balance := range []int64{5, 0} {
+		acc, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+			AccountID:      int64(3000 + i),
+			InitialBalance: decimal.NewFromInt(balance),
+		})
+		if err != nil {
+			return apptest.SeedData{}, fmt.Errorf("seeding closable account : %w", err)
+		}
+		closable[i] = apptest.Account{Account: dbtest.Account{Account: acc}}
+	}
+
+	// an account carrying metadata to update and search for
+	tagged, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+		AccountID:      4000,
+		InitialBalance: decimal.NewFromInt(10),
+		OwnerRef:       "owner-4000",
+		Type:           transferbus.AccountTypeFee,
+		Name:           "fees",
+		Labels:         []string{"billing"},
+	})
+	if err != nil {
+		return apptest.SeedData{}, fmt.Errorf("seeding tagged account : %w", err)
+	}
+	tu4 := apptest.Account{Account: dbtest.Account{Account: tagged}}
+
+	// one hold to capture and one to void, reserved on the second account
+	holds := make([]transferbus.Hold, 2)
+	for i := range holds {
+		holds[i], err = busDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+			SourceAccountID:      usrs[1].AccountID,
+			DestinationAccountID: usrs[0].AccountID,
+			Amount:               decimal.NewFromInt(2),
+		})
+		if err != nil {
+			return apptest.SeedData{}, fmt.Errorf("seeding hold : %w", err)
+		}
+	}
+
+	// a monthly transfer that is not due during the tests
+	scheduled, err := busDomain.TransferBus.CreateScheduledTransfer(ctx, transferbus.NewScheduledTransfer{
+		SourceAccountID:      usrs[0].AccountID,
+		DestinationAccountID: usrs[1].AccountID,
+		Amount:               decimal.NewFromInt(5),
+		Recurrence:           "@monthly",
+		StartDate:            time.Now().AddDate(1, 0, 0),
+	})
+	if err != nil {
+		return apptest.SeedData{}, fmt.Errorf("seeding scheduled transfer : %w", err)
+	}
+
+	// a webhook that is never delivered during the tests
+	webhook, err := busDomain.TransferBus.CreateWebhookSubscription(ctx, transferbus.NewWebhookSubscription{
+		AccountID: usrs[0].AccountID,
+		URL:       "https://partner.example.com/seed",
+	})
+	if err != nil {
+		return apptest.SeedData{}, fmt.Errorf("seeding webhook : %w", err)
+	}
 	// -------------------------------------------------------------------------
 
 	sd := apptest.SeedData{
-		Accounts: []apptest.Account{tu1, tu2},
+		Accounts:  []apptest.Account{tu1, tu2, tu3, closable[0], closable[1], tu4},
+		Transfers: []transferbus.Transfer{transfer},
+		Quotes:    []transferbus.Quote{quote},
+		Holds:     holds,
+		Schedules: []transferbus.ScheduledTransfer{scheduled},
+		Webhooks:  []transferbus.WebhookSubscription{webhook},
 	}
 
 	return sd, nil
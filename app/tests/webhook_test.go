@@ -0,0 +1,248 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danipurwadi/internal-transfer-system/app/api/apptest"
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+// cmpWebhook compares the responses without the ids, secrets and dates set by
+// the service.
+func cmpWebhook(got any, exp any) string {
+	gotResp := got.(*transferapp.WebhookResponse)
+	if gotResp.SubscriptionID == "" || gotResp.CreatedDate == "" {
+		return "subscription id and created date should be set"
+	}
+
+	expResp := *exp.(*transferapp.WebhookResponse)
+	expResp.SubscriptionID = gotResp.SubscriptionID
+	expResp.CreatedDate = gotResp.CreatedDate
+	if expResp.Secret != "" {
+		if !strings.HasPrefix(gotResp.Secret, expResp.Secret) {
+			return fmt.Sprintf("secret %q should start with %q", gotResp.Secret, expResp.Secret)
+		}
+		expResp.Secret = gotResp.Secret
+	}
+	return cmp.Diff(gotResp, &expResp)
+}
+
+func webhook201(sd apptest.SeedData) []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "create",
+			URL:        "/webhooks",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusCreated,
+			Input: &transferapp.WebhookRequest{
+				AccountID: sd.Accounts[1].AccountID,
+				URL:       "https://partner.example.com/hooks",
+			},
+			GotResp: &transferapp.WebhookResponse{},
+			ExpResp: &transferapp.WebhookResponse{
+				AccountID: strconv.FormatInt(sd.Accounts[1].AccountID, 10),
+				URL:       "https://partner.example.com/hooks",
+				Status:    string(transferbus.WebhookStatusActive),
+				Secret:    "whsec_",
+			},
+			CmpFunc: cmpWebhook,
+		},
+	}
+
+	return table
+}
+
+func webhook200(sd apptest.SeedData) []apptest.Table {
+	sub := sd.Webhooks[0]
+	url := "/webhooks/" + sub.SubscriptionID.String()
+
+	exp := transferapp.WebhookResponse{
+		SubscriptionID: sub.SubscriptionID.String(),
+		AccountID:      strconv.FormatInt(sub.AccountID, 10),
+		URL:            sub.URL,
+		Status:         string(transferbus.WebhookStatusActive),
+	}
+
+	paused := exp
+	paused.Status = string(transferbus.WebhookStatusPaused)
+	status := string(transferbus.WebhookStatusPaused)
+
+	table := []apptest.Table{
+		{
+			Name:       "query",
+			URL:        url,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.WebhookResponse{},
+			ExpResp:    &exp,
+			CmpFunc:    cmpWebhook,
+		},
+		{
+			Name:       "account",
+			URL:        fmt.Sprintf("/accounts/%d/webhooks", sub.AccountID),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.WebhooksResponse{},
+			ExpResp:    &exp,
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*transferapp.WebhooksResponse)
+				if len(gotResp.Items) != 1 {
+					return fmt.Sprintf("expected 1 webhook, got %d", len(gotResp.Items))
+				}
+				return cmpWebhook(&gotResp.Items[0], exp)
+			},
+		},
+		{
+			Name:       "pause",
+			URL:        url,
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusOK,
+			Input: &transferapp.WebhookUpdateRequest{
+				Status: &status,
+			},
+			GotResp: &transferapp.WebhookResponse{},
+			ExpResp: &paused,
+			CmpFunc: cmpWebhook,
+		},
+		{
+			Name:       "deliveries",
+			URL:        url + "/deliveries?status=pending",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &transferapp.WebhookDeliveriesResponse{},
+			ExpResp:    sub.SubscriptionID.String(),
+			CmpFunc: func(got any, exp any) string {
+				// the transfers of the other tests are never delivered
+				for _, d := range got.(*transferapp.WebhookDeliveriesResponse).Items {
+					if d.SubscriptionID != exp || d.Status != string(transferbus.DeliveryStatusPending) || d.Attempts != 0 {
+						return fmt.Sprintf("unexpected delivery %+v", d)
+					}
+				}
+				return ""
+			},
+		},
+		{
+			Name:       "delete",
+			URL:        url,
+			Method:     http.MethodDelete,
+			StatusCode: http.StatusNoContent,
+		},
+	}
+
+	return table
+}
+
+func webhook400(sd apptest.SeedData) []apptest.Table {
+	loopback := "http://127.0.0.1:8080/hooks"
+
+	table := []apptest.Table{
+		{
+			Name:       "unsupportedscheme",
+			URL:        "/webhooks",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.WebhookRequest{
+				AccountID: sd.Accounts[1].AccountID,
+				URL:       "ftp://partner.example.com/hooks",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, transferbus.ErrInvalidWebhookURL.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "privateaddress",
+			URL:        "/webhooks",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.WebhookRequest{
+				AccountID: sd.Accounts[1].AccountID,
+				URL:       "http://169.254.169.254/latest/meta-data",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "%s: 169.254.169.254", transferbus.ErrWebhookAddress)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "loopback",
+			URL:        "/webhooks/" + sd.Webhooks[0].SubscriptionID.String(),
+			Method:     http.MethodPatch,
+			StatusCode: http.StatusBadRequest,
+			Input: &transferapp.WebhookUpdateRequest{
+				URL: &loopback,
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.InvalidArgument, "%s: 127.0.0.1", transferbus.ErrWebhookAddress)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidsubscriptionid",
+			URL:        "/webhooks/abc",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "invalid subscription id")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "invalidstatus",
+			URL:        "/webhooks/" + uuid.NewString() + "/deliveries?status=failed",
+			Method:     http.MethodGet,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.InvalidArgument, "status must be one of pending, delivered or dead")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func webhook404() []apptest.Table {
+	table := []apptest.Table{
+		{
+			Name:       "webhooknotfound",
+			URL:        "/webhooks/" + uuid.NewString(),
+			Method:     http.MethodDelete,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &customerror.Error{},
+			ExpResp:    toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrWebhookNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "accountnotfound",
+			URL:        "/webhooks",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			Input: &transferapp.WebhookRequest{
+				AccountID: 999999,
+				URL:       "https://partner.example.com/hooks",
+			},
+			GotResp: &customerror.Error{},
+			ExpResp: toErrorPtr(customerror.Newf(customerror.NotFound, transferbus.ErrAccNotFound.Error())),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
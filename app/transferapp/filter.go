@@ -0,0 +1,183 @@
+package transferapp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+
+	maxReconcileBatchSize = 10000
+)
+
+// parseTransactionFilter reads the pagination and filter query parameters of
+// the transaction history endpoint.
+func parseTransactionFilter(r *http.Request, accountID int64) (transferbus.TransactionFilter, error) {
+	values := r.URL.Query()
+
+	filter := transferbus.TransactionFilter{
+		AccountID: accountID,
+		Limit:     defaultPageLimit,
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		c, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || c < 1 {
+			return transferbus.TransactionFilter{}, fmt.Errorf("invalid cursor")
+		}
+		filter.Cursor = c
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > maxPageLimit {
+			return transferbus.TransactionFilter{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		filter.Limit = l
+	}
+
+	if startDate := values.Get("start_date"); startDate != "" {
+		t, err := time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			return transferbus.TransactionFilter{}, fmt.Errorf("start_date must be in RFC3339 format")
+		}
+		filter.StartDate = t
+	}
+
+	if endDate := values.Get("end_date"); endDate != "" {
+		t, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			return transferbus.TransactionFilter{}, fmt.Errorf("end_date must be in RFC3339 format")
+		}
+		filter.EndDate = t
+	}
+
+	if direction := values.Get("direction"); direction != "" {
+		switch d := transferbus.Direction(direction); d {
+		case transferbus.DirectionDebit, transferbus.DirectionCredit:
+			filter.Direction = d
+		default:
+			return transferbus.TransactionFilter{}, fmt.Errorf("direction must be either %s or %s", transferbus.DirectionDebit, transferbus.DirectionCredit)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseAccountFilter reads the pagination and filter query parameters of the
+// account search endpoint. The label parameter may be repeated.
+func parseAccountFilter(r *http.Request) (transferbus.AccountFilter, error) {
+	values := r.URL.Query()
+
+	filter := transferbus.AccountFilter{
+		OwnerRef: values.Get("owner"),
+		Labels:   values["label"],
+		Limit:    defaultPageLimit,
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		c, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || c == 0 {
+			return transferbus.AccountFilter{}, fmt.Errorf("invalid cursor")
+		}
+		filter.Cursor = c
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > maxPageLimit {
+			return transferbus.AccountFilter{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		filter.Limit = l
+	}
+
+	if accountType := values.Get("type"); accountType != "" {
+		switch t := transferbus.AccountType(accountType); t {
+		case transferbus.AccountTypeCustomer, transferbus.AccountTypeOperational, transferbus.AccountTypeFee, transferbus.AccountTypeSuspense:
+			filter.Type = t
+		default:
+			return transferbus.AccountFilter{}, fmt.Errorf("type must be one of %s, %s, %s or %s", transferbus.AccountTypeCustomer, transferbus.AccountTypeOperational, transferbus.AccountTypeFee, transferbus.AccountTypeSuspense)
+		}
+	}
+
+	for _, label := range filter.Labels {
+		if label == "" {
+			return transferbus.AccountFilter{}, fmt.Errorf("label must not be empty")
+		}
+	}
+
+	return filter, nil
+}
+
+// parseDeliveryFilter reads the pagination and filter query parameters of the
+// webhook delivery log endpoint.
+func parseDeliveryFilter(r *http.Request, subscriptionID uuid.UUID) (transferbus.DeliveryFilter, error) {
+	values := r.URL.Query()
+
+	filter := transferbus.DeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Limit:          defaultPageLimit,
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		c, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || c < 1 {
+			return transferbus.DeliveryFilter{}, fmt.Errorf("invalid cursor")
+		}
+		filter.Cursor = c
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > maxPageLimit {
+			return transferbus.DeliveryFilter{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		filter.Limit = l
+	}
+
+	if status := values.Get("status"); status != "" {
+		switch s := transferbus.DeliveryStatus(status); s {
+		case transferbus.DeliveryStatusPending, transferbus.DeliveryStatusDelivered, transferbus.DeliveryStatusDead:
+			filter.Status = s
+		default:
+			return transferbus.DeliveryFilter{}, fmt.Errorf("status must be one of %s, %s or %s", transferbus.DeliveryStatusPending, transferbus.DeliveryStatusDelivered, transferbus.DeliveryStatusDead)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseReconcileConfig reads the query parameters of the reconciliation
+// endpoint.
+func parseReconcileConfig(r *http.Request) (transferbus.ReconcileConfig, error) {
+	values := r.URL.Query()
+
+	cfg := transferbus.ReconcileConfig{
+		BatchSize: transferbus.DefaultReconcileBatchSize,
+	}
+
+	if batchSize := values.Get("batch_size"); batchSize != "" {
+		b, err := strconv.Atoi(batchSize)
+		if err != nil || b < 1 || b > maxReconcileBatchSize {
+			return transferbus.ReconcileConfig{}, fmt.Errorf("batch_size must be between 1 and %d", maxReconcileBatchSize)
+		}
+		cfg.BatchSize = b
+	}
+
+	if alert := values.Get("alert"); alert != "" {
+		a, err := strconv.ParseBool(alert)
+		if err != nil {
+			return transferbus.ReconcileConfig{}, fmt.Errorf("alert must be a boolean")
+		}
+		cfg.Alert = a
+	}
+
+	return cfg, nil
+}
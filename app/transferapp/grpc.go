@@ -0,0 +1,229 @@
+package transferapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/app/transferapp/transferpb"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcServer serves the TransferService of the gRPC API with the same business
+// logic, validation and errors as the HTTP API.
+type grpcServer struct {
+	transferpb.UnimplementedTransferServiceServer
+	transferbus *transferbus.Bus
+}
+
+// RegisterGRPC registers the gRPC services of the app to the server.
+func (a *App) RegisterGRPC(s grpc.ServiceRegistrar) {
+	transferpb.RegisterTransferServiceServer(s, &grpcServer{
+		transferbus: a.transferbus,
+	})
+}
+
+func (s *grpcServer) CreateAccount(ctx context.Context, req *transferpb.CreateAccountRequest) (*transferpb.Account, error) {
+	app := AccountCreationRequest{
+		AccountID:      req.GetAccountId(),
+		Currency:       req.GetCurrency(),
+		InitialBalance: req.GetInitialBalance(),
+		Owner:          req.GetOwner(),
+		Type:           req.GetType(),
+		Name:           req.GetName(),
+		Labels:         req.GetLabels(),
+	}
+	if err := app.Validate(); err != nil {
+		return nil, err
+	}
+
+	account, err := toBusAccCreation(app)
+	if err != nil {
+		return nil, customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	acc, err := s.transferbus.CreateAccount(ctx, account)
+	if err != nil {
+		return nil, accountCreationError(err)
+	}
+
+	return toPBAccount(acc), nil
+}
+
+func (s *grpcServer) GetBalance(ctx context.Context, req *transferpb.GetBalanceRequest) (*transferpb.Account, error) {
+	accID := req.GetAccountId()
+	if accID < 1 {
+		return nil, customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	acc, err := s.transferbus.GetBalance(ctx, accID)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return nil, customerror.New(customerror.NotFound, err)
+		}
+		return nil, customerror.Newf(customerror.Internal, "failed to get balance: accId[%d]: %s", accID, err)
+	}
+
+	return toPBAccount(acc), nil
+}
+
+func (s *grpcServer) CreateTransfer(ctx context.Context, req *transferpb.CreateTransferRequest) (*transferpb.Transfer, error) {
+	app := TransactionRequest{
+		SourceAccountID:      req.GetSourceAccountId(),
+		DestinationAccountID: req.GetDestinationAccountId(),
+		Amount:               req.GetAmount(),
+		Currency:             req.GetCurrency(),
+		QuoteID:              req.GetQuoteId(),
+		IdempotencyKey:       req.GetIdempotencyKey(),
+	}
+	if err := app.Validate(); err != nil {
+		return nil, err
+	}
+	t, err := toBusTransaction(app)
+	if err != nil {
+		return nil, customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	transfer, err := s.transferbus.CreateTransaction(ctx, t)
+	if err != nil {
+		return nil, transactionError(err)
+	}
+
+	return toPBTransfer(transfer), nil
+}
+
+func (s *grpcServer) ListTransactions(ctx context.Context, req *transferpb.ListTransactionsRequest) (*transferpb.ListTransactionsResponse, error) {
+	filter, err := toBusTransactionFilter(req)
+	if err != nil {
+		return nil, customerror.New(customerror.InvalidArgument, err)
+	}
+
+	legs, err := s.transferbus.QueryAccountTransactions(ctx, filter)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return nil, customerror.New(customerror.NotFound, err)
+		}
+		return nil, customerror.Newf(customerror.Internal, "failed to query transactions: accId[%d]: %s", filter.AccountID, err)
+	}
+
+	resp := transferpb.ListTransactionsResponse{
+		Items: toPBTransactions(legs),
+	}
+	if len(legs) == filter.Limit {
+		resp.NextCursor = legs[len(legs)-1].TransactionID
+	}
+	return &resp, nil
+}
+
+// =============================================================================
+
+// toBusTransactionFilter reads the pagination and filters of a transaction
+// history request, with the same bounds as parseTransactionFilter.
+func toBusTransactionFilter(req *transferpb.ListTransactionsRequest) (transferbus.TransactionFilter, error) {
+	if req.GetAccountId() < 1 {
+		return transferbus.TransactionFilter{}, fmt.Errorf("invalid account id")
+	}
+
+	filter := transferbus.TransactionFilter{
+		AccountID: req.GetAccountId(),
+		Limit:     defaultPageLimit,
+	}
+
+	if req.GetCursor() < 0 {
+		return transferbus.TransactionFilter{}, fmt.Errorf("invalid cursor")
+	}
+	filter.Cursor = req.GetCursor()
+
+	if limit := int(req.GetLimit()); limit != 0 {
+		if limit < 1 || limit > maxPageLimit {
+			return transferbus.TransactionFilter{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		filter.Limit = limit
+	}
+
+	if req.StartDate != nil {
+		if err := req.GetStartDate().CheckValid(); err != nil {
+			return transferbus.TransactionFilter{}, fmt.Errorf("invalid start_date")
+		}
+		filter.StartDate = req.GetStartDate().AsTime()
+	}
+
+	if req.EndDate != nil {
+		if err := req.GetEndDate().CheckValid(); err != nil {
+			return transferbus.TransactionFilter{}, fmt.Errorf("invalid end_date")
+		}
+		filter.EndDate = req.GetEndDate().AsTime()
+	}
+
+	if direction := req.GetDirection(); direction != "" {
+		switch d := transferbus.Direction(direction); d {
+		case transferbus.DirectionDebit, transferbus.DirectionCredit:
+			filter.Direction = d
+		default:
+			return transferbus.TransactionFilter{}, fmt.Errorf("direction must be either %s or %s", transferbus.DirectionDebit, transferbus.DirectionCredit)
+		}
+	}
+
+	return filter, nil
+}
+
+func toPBAccount(account transferbus.Account) *transferpb.Account {
+	resp := transferpb.Account{
+		AccountId:        account.AccountID,
+		Currency:         account.Currency.String(),
+		Status:           string(account.Status),
+		Type:             string(account.Type),
+		Owner:            account.OwnerRef,
+		Name:             account.Name,
+		Labels:           account.Labels,
+		Balance:          account.Balance.String(),
+		AvailableBalance: account.AvailableBalance().String(),
+		CreatedDate:      timestamppb.New(account.CreatedDate),
+		LastModifiedDate: timestamppb.New(account.LastModifiedDate),
+	}
+	if account.OverdraftLimit.IsPositive() {
+		resp.OverdraftLimit = account.OverdraftLimit.String()
+		resp.AvailableCredit = account.AvailableCredit().String()
+	}
+	return &resp
+}
+
+func toPBTransfer(transfer transferbus.Transfer) *transferpb.Transfer {
+	resp := transferpb.Transfer{
+		TransferId:           transfer.TransferID.String(),
+		EntryType:            string(transfer.EntryType),
+		SourceAccountId:      transfer.SourceAccountID,
+		DestinationAccountId: transfer.DestinationAccountID,
+		Amount:               transfer.Amount.String(),
+		Currency:             transfer.Currency.String(),
+		DestinationAmount:    transfer.DestinationAmount.String(),
+		DestinationCurrency:  transfer.DestinationCurrency.String(),
+		Rate:                 transfer.Rate.String(),
+		SourceBalance:        transfer.SourceBalance.String(),
+		CreatedDate:          timestamppb.New(transfer.CreatedDate),
+		Legs:                 toPBTransactions(transfer.Legs),
+	}
+	if transfer.QuoteID != uuid.Nil {
+		resp.QuoteId = transfer.QuoteID.String()
+	}
+	return &resp
+}
+
+func toPBTransactions(legs []transferbus.TransferLeg) []*transferpb.Transaction {
+	resp := make([]*transferpb.Transaction, len(legs))
+	for i, leg := range legs {
+		resp[i] = &transferpb.Transaction{
+			TransactionId: leg.TransactionID,
+			TransferId:    leg.TransferID.String(),
+			AccountId:     leg.AccountID,
+			Amount:        leg.Amount.String(),
+			Currency:      leg.Currency.String(),
+			CreatedDate:   timestamppb.New(leg.CreatedDate),
+		}
+	}
+	return resp
+}
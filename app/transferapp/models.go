@@ -1,29 +1,102 @@
 package transferapp
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
 	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
 	"github.com/danipurwadi/internal-transfer-system/foundation/validate"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
+// BalanceResponse shows the ledger balance of an account next to the part of
+// it that is available, which excludes funds reserved by holds. Balance is the
+// ledger balance. Accounts with an overdraft limit also show the limit and how
+// much can still be debited.
 type BalanceResponse struct {
-	AccountID string `json:"account_id"`
-	Balance   string `json:"balance"`
+	AccountID        string   `json:"account_id"`
+	Currency         string   `json:"currency"`
+	Status           string   `json:"status"`
+	Type             string   `json:"type"`
+	Owner            string   `json:"owner,omitempty"`
+	Name             string   `json:"name,omitempty"`
+	Labels           []string `json:"labels"`
+	Balance          string   `json:"balance"`
+	AvailableBalance string   `json:"available_balance"`
+	OverdraftLimit   string   `json:"overdraft_limit,omitempty"`
+	AvailableCredit  string   `json:"available_credit,omitempty"`
 }
 
 func fromBusAccBalance(account transferbus.Account) BalanceResponse {
-	return BalanceResponse{
-		AccountID: strconv.FormatInt(account.AccountID, 10),
-		Balance:   account.Balance.String(),
+	resp := BalanceResponse{
+		AccountID:        strconv.FormatInt(account.AccountID, 10),
+		Currency:         account.Currency.String(),
+		Status:           string(account.Status),
+		Type:             string(account.Type),
+		Owner:            account.OwnerRef,
+		Name:             account.Name,
+		Labels:           account.Labels,
+		Balance:          account.Balance.String(),
+		AvailableBalance: account.AvailableBalance().String(),
+	}
+	if account.OverdraftLimit.IsPositive() {
+		resp.OverdraftLimit = account.OverdraftLimit.String()
+		resp.AvailableCredit = account.AvailableCredit().String()
+	}
+	return resp
+}
+
+// AccountResponse is an account along with when it was opened and last
+// changed.
+type AccountResponse struct {
+	BalanceResponse
+	CreatedDate      string `json:"created_date"`
+	LastModifiedDate string `json:"last_modified_date"`
+}
+
+func fromBusAccount(account transferbus.Account) AccountResponse {
+	return AccountResponse{
+		BalanceResponse:  fromBusAccBalance(account),
+		CreatedDate:      account.CreatedDate.Format(time.RFC3339),
+		LastModifiedDate: account.LastModifiedDate.Format(time.RFC3339),
 	}
 }
 
+// AccountsResponse is a page of accounts. NextCursor is empty once the last
+// page has been reached.
+type AccountsResponse struct {
+	Items      []BalanceResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func fromBusAccounts(accounts []transferbus.Account, limit int) AccountsResponse {
+	resp := AccountsResponse{
+		Items: make([]BalanceResponse, len(accounts)),
+	}
+	for i, account := range accounts {
+		resp.Items[i] = fromBusAccBalance(account)
+	}
+	if len(accounts) == limit {
+		resp.NextCursor = strconv.FormatInt(accounts[len(accounts)-1].AccountID, 10)
+	}
+	return resp
+}
+
+// AccountCreationRequest opens an account. The service allocates the account
+// id when it is left out.
 type AccountCreationRequest struct {
-	AccountID      int64  `json:"account_id" validate:"required,min=1"`
-	InitialBalance string `json:"initial_balance" validate:"required"`
+	AccountID      int64    `json:"account_id,omitempty" validate:"omitempty,min=1"`
+	Currency       string   `json:"currency,omitempty" validate:"omitempty,len=3"`
+	InitialBalance string   `json:"initial_balance" validate:"required"`
+	Owner          string   `json:"owner,omitempty" validate:"omitempty,max=255"`
+	Type           string   `json:"type,omitempty" validate:"omitempty,oneof=customer operational fee suspense"`
+	Name           string   `json:"name,omitempty" validate:"omitempty,max=255"`
+	Labels         []string `json:"labels,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
 }
 
 // Validate checks if the data in the model is considered clean.
@@ -34,22 +107,188 @@ func (r AccountCreationRequest) Validate() error {
 	return nil
 }
 
+// AccountCloseRequest names the account receiving the remaining balance of a
+// closed account. It may be left out when the balance is zero.
+type AccountCloseRequest struct {
+	SweepAccountID int64 `json:"sweep_account_id,omitempty" validate:"omitempty,min=1"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r AccountCloseRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+// OverdraftLimitRequest sets how far the balance of an account may go below
+// zero. A zero limit removes the overdraft.
+type OverdraftLimitRequest struct {
+	OverdraftLimit string `json:"overdraft_limit" validate:"required"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r OverdraftLimitRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusOverdraftLimit(req OverdraftLimitRequest) (decimal.Decimal, error) {
+	limit, err := decimal.NewFromString(req.OverdraftLimit)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid overdraft_limit")
+	}
+	return limit, nil
+}
+
+// TransferLimitsRequest sets the limits on the outbound transfers of an
+// account or an account type. Limits left out or set to zero are not enforced.
+type TransferLimitsRequest struct {
+	MaxAmount     string `json:"max_amount,omitempty"`
+	DailyAmount   string `json:"daily_amount,omitempty"`
+	MonthlyAmount string `json:"monthly_amount,omitempty"`
+}
+
+func toBusTransferLimits(req TransferLimitsRequest) (transferbus.TransferLimits, error) {
+	var limits transferbus.TransferLimits
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{name: "max_amount", value: req.MaxAmount, dst: &limits.MaxAmount},
+		{name: "daily_amount", value: req.DailyAmount, dst: &limits.DailyAmount},
+		{name: "monthly_amount", value: req.MonthlyAmount, dst: &limits.MonthlyAmount},
+	} {
+		if field.value == "" {
+			continue
+		}
+		amount, err := decimal.NewFromString(field.value)
+		if err != nil {
+			return transferbus.TransferLimits{}, fmt.Errorf("invalid %s", field.name)
+		}
+		*field.dst = amount
+	}
+	return limits, nil
+}
+
+// AccountTypeLimitsRequest sets the limits on the outbound transfers of the
+// accounts of a type in one currency. Currency defaults to currency.Default.
+type AccountTypeLimitsRequest struct {
+	Currency string `json:"currency,omitempty"`
+	TransferLimitsRequest
+}
+
+func toBusAccountTypeLimits(req AccountTypeLimitsRequest) (currency.Currency, transferbus.TransferLimits, error) {
+	cur := currency.Default
+	if req.Currency != "" {
+		var err error
+		if cur, err = currency.Parse(req.Currency); err != nil {
+			return currency.Currency{}, transferbus.TransferLimits{}, err
+		}
+	}
+
+	limits, err := toBusTransferLimits(req.TransferLimitsRequest)
+	if err != nil {
+		return currency.Currency{}, transferbus.TransferLimits{}, err
+	}
+	return cur, limits, nil
+}
+
+// TransferLimitsResponse shows the limits on the outbound transfers of an
+// account or an account type. Zero limits are not enforced.
+type TransferLimitsResponse struct {
+	MaxAmount     string `json:"max_amount"`
+	DailyAmount   string `json:"daily_amount"`
+	MonthlyAmount string `json:"monthly_amount"`
+}
+
+func fromBusTransferLimits(limits transferbus.TransferLimits) TransferLimitsResponse {
+	return TransferLimitsResponse{
+		MaxAmount:     limits.MaxAmount.String(),
+		DailyAmount:   limits.DailyAmount.String(),
+		MonthlyAmount: limits.MonthlyAmount.String(),
+	}
+}
+
+func toBusAccountClosure(accountID int64, req AccountCloseRequest) transferbus.AccountClosure {
+	return transferbus.AccountClosure{
+		AccountID:      accountID,
+		SweepAccountID: req.SweepAccountID,
+	}
+}
+
 func toBusAccCreation(req AccountCreationRequest) (transferbus.NewAccount, error) {
 	decimalBalance, err := decimal.NewFromString(req.InitialBalance)
 	if err != nil {
 		return transferbus.NewAccount{}, err
 	}
 
+	cur := currency.Default
+	if req.Currency != "" {
+		if cur, err = currency.Parse(req.Currency); err != nil {
+			return transferbus.NewAccount{}, err
+		}
+	}
+
 	return transferbus.NewAccount{
 		AccountID:      req.AccountID,
+		Currency:       cur,
 		InitialBalance: decimalBalance,
+		OwnerRef:       req.Owner,
+		Type:           transferbus.AccountType(req.Type),
+		Name:           req.Name,
+		Labels:         req.Labels,
 	}, nil
 }
 
+// AccountUpdateRequest holds the metadata of an account to change. Fields left
+// out of the request are not changed, and an empty labels list clears them.
+type AccountUpdateRequest struct {
+	Owner  *string   `json:"owner,omitempty" validate:"omitempty,max=255"`
+	Type   *string   `json:"type,omitempty" validate:"omitempty,oneof=customer operational fee suspense"`
+	Name   *string   `json:"name,omitempty" validate:"omitempty,max=255"`
+	Labels *[]string `json:"labels,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r AccountUpdateRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusUpdateAccount(req AccountUpdateRequest) transferbus.UpdateAccount {
+	ua := transferbus.UpdateAccount{
+		OwnerRef: req.Owner,
+		Name:     req.Name,
+		Labels:   req.Labels,
+	}
+	if req.Type != nil {
+		t := transferbus.AccountType(*req.Type)
+		ua.Type = &t
+	}
+	return ua
+}
+
 type TransactionRequest struct {
 	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
 	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1"`
 	Amount               string `json:"amount" validate:"required"`
+	Currency             string `json:"currency,omitempty" validate:"omitempty,len=3"`
+	QuoteID              string `json:"quote_id,omitempty" validate:"omitempty,uuid"`
+	IdempotencyKey       string `json:"idempotency_key,omitempty" validate:"omitempty,max=255"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r TransactionRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
 }
 
 func toBusTransaction(req TransactionRequest) (transferbus.Transaction, error) {
@@ -57,9 +296,730 @@ func toBusTransaction(req TransactionRequest) (transferbus.Transaction, error) {
 	if err != nil {
 		return transferbus.Transaction{}, err
 	}
+
+	// the currency is optional and defaults to the one held by both accounts
+	var cur currency.Currency
+	if req.Currency != "" {
+		if cur, err = currency.Parse(req.Currency); err != nil {
+			return transferbus.Transaction{}, err
+		}
+	}
+
+	var quoteID uuid.UUID
+	if req.QuoteID != "" {
+		if quoteID, err = uuid.Parse(req.QuoteID); err != nil {
+			return transferbus.Transaction{}, fmt.Errorf("invalid quote id")
+		}
+	}
+
 	return transferbus.Transaction{
 		SourceAccountID:      req.SourceAccountID,
 		DestinationAccountID: req.DestinationAccountID,
 		Amount:               decimalAmount,
+		Currency:             cur,
+		QuoteID:              quoteID,
+		IdempotencyKey:       req.IdempotencyKey,
+	}, nil
+}
+
+// maxBatchSize caps the number of transactions of a batch, which all run in a
+// single database transaction.
+const maxBatchSize = 5000
+
+// BatchRequest posts many transactions at once. Mode is either atomic, the
+// default, or best_effort.
+type BatchRequest struct {
+	Mode         string               `json:"mode,omitempty"`
+	Transactions []TransactionRequest `json:"transactions"`
+}
+
+// Validate checks every transaction of the batch, whose tags are not checked
+// along with the batch itself.
+func (r BatchRequest) Validate() error {
+	for i, t := range r.Transactions {
+		if err := validate.Check(t); err != nil {
+			return customerror.Newf(customerror.FailedPrecondition, "transactions[%d]: validate: %s", i, err)
+		}
+	}
+	return nil
+}
+
+func toBusBatch(req BatchRequest) ([]transferbus.Transaction, transferbus.BatchMode, error) {
+	mode := transferbus.BatchModeAtomic
+	switch req.Mode {
+	case "", string(transferbus.BatchModeAtomic):
+	case string(transferbus.BatchModeBestEffort):
+		mode = transferbus.BatchModeBestEffort
+	default:
+		return nil, "", fmt.Errorf("mode must be %s or %s", transferbus.BatchModeAtomic, transferbus.BatchModeBestEffort)
+	}
+
+	if len(req.Transactions) == 0 || len(req.Transactions) > maxBatchSize {
+		return nil, "", fmt.Errorf("transactions must hold between 1 and %d items", maxBatchSize)
+	}
+
+	transactions := make([]transferbus.Transaction, len(req.Transactions))
+	for i, t := range req.Transactions {
+		transaction, err := toBusTransaction(t)
+		if err != nil {
+			return nil, "", fmt.Errorf("transactions[%d]: %w", i, err)
+		}
+		transactions[i] = transaction
+	}
+
+	return transactions, mode, nil
+}
+
+// BatchResponse reports the outcome of every transaction of a batch, in the
+// order they were requested.
+type BatchResponse struct {
+	Mode      string              `json:"mode"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []BatchItemResponse `json:"results"`
+}
+
+const (
+	batchItemSucceeded = "succeeded"
+	batchItemFailed    = "failed"
+)
+
+// BatchItemResponse holds either the transfer or the error of a transaction.
+type BatchItemResponse struct {
+	Index    int                `json:"index"`
+	Status   string             `json:"status"`
+	Transfer *TransferResponse  `json:"transfer,omitempty"`
+	Error    *customerror.Error `json:"error,omitempty"`
+}
+
+func fromBusBatchResults(results []transferbus.BatchResult, mode transferbus.BatchMode) BatchResponse {
+	resp := BatchResponse{
+		Mode:    string(mode),
+		Results: make([]BatchItemResponse, len(results)),
+	}
+
+	for i, result := range results {
+		item := BatchItemResponse{Index: i}
+		if result.Err != nil {
+			itemErr := transactionError(result.Err)
+			item.Status = batchItemFailed
+			item.Error = &itemErr
+			resp.Failed++
+		} else {
+			transfer := fromBusTransfer(result.Transfer)
+			item.Status = batchItemSucceeded
+			item.Transfer = &transfer
+			resp.Succeeded++
+		}
+		resp.Results[i] = item
+	}
+
+	return resp
+}
+
+type TransferResponse struct {
+	TransferID           string                `json:"transfer_id"`
+	EntryType            string                `json:"entry_type"`
+	SourceAccountID      string                `json:"source_account_id"`
+	DestinationAccountID string                `json:"destination_account_id"`
+	Amount               string                `json:"amount"`
+	Currency             string                `json:"currency"`
+	DestinationAmount    string                `json:"destination_amount"`
+	DestinationCurrency  string                `json:"destination_currency"`
+	Rate                 string                `json:"rate"`
+	QuoteID              string                `json:"quote_id,omitempty"`
+	ReversalOf           string                `json:"reversal_of,omitempty"`
+	CreatedDate          string                `json:"created_date"`
+	Legs                 []TransferLegResponse `json:"legs"`
+}
+
+// TransferCreatedResponse is a transfer just posted along with the balance its
+// source account was left with.
+type TransferCreatedResponse struct {
+	TransferResponse
+	SourceBalance string `json:"source_balance"`
+}
+
+func fromBusTransferCreated(transfer transferbus.Transfer) TransferCreatedResponse {
+	return TransferCreatedResponse{
+		TransferResponse: fromBusTransfer(transfer),
+		SourceBalance:    transfer.SourceBalance.String(),
+	}
+}
+
+func fromBusTransfer(transfer transferbus.Transfer) TransferResponse {
+	var quoteID string
+	if transfer.QuoteID != uuid.Nil {
+		quoteID = transfer.QuoteID.String()
+	}
+
+	var reversalOf string
+	if transfer.ReversalOf != uuid.Nil {
+		reversalOf = transfer.ReversalOf.String()
+	}
+
+	return TransferResponse{
+		TransferID:           transfer.TransferID.String(),
+		EntryType:            string(transfer.EntryType),
+		SourceAccountID:      strconv.FormatInt(transfer.SourceAccountID, 10),
+		DestinationAccountID: strconv.FormatInt(transfer.DestinationAccountID, 10),
+		Amount:               transfer.Amount.String(),
+		Currency:             transfer.Currency.String(),
+		DestinationAmount:    transfer.DestinationAmount.String(),
+		DestinationCurrency:  transfer.DestinationCurrency.String(),
+		Rate:                 transfer.Rate.String(),
+		QuoteID:              quoteID,
+		ReversalOf:           reversalOf,
+		CreatedDate:          transfer.CreatedDate.Format(time.RFC3339),
+		Legs:                 fromBusTransferLegs(transfer.Legs),
+	}
+}
+
+// ReversalRequest sends back part of a transfer. Without an amount whatever is
+// left of the transfer is reversed.
+type ReversalRequest struct {
+	Amount string `json:"amount,omitempty"`
+}
+
+func toBusReversal(req ReversalRequest, transferID uuid.UUID) (transferbus.Reversal, error) {
+	var amount decimal.Decimal
+	if req.Amount != "" {
+		var err error
+		if amount, err = decimal.NewFromString(req.Amount); err != nil {
+			return transferbus.Reversal{}, err
+		}
+	}
+
+	return transferbus.Reversal{
+		TransferID: transferID,
+		Amount:     amount,
+	}, nil
+}
+
+type TransferLegResponse struct {
+	TransactionID string `json:"transaction_id"`
+	TransferID    string `json:"transfer_id"`
+	AccountID     string `json:"account_id"`
+	Amount        string `json:"amount"`
+	Currency      string `json:"currency"`
+	CreatedDate   string `json:"created_date"`
+}
+
+func fromBusTransferLegs(legs []transferbus.TransferLeg) []TransferLegResponse {
+	resp := make([]TransferLegResponse, len(legs))
+	for i, leg := range legs {
+		resp[i] = TransferLegResponse{
+			TransactionID: strconv.FormatInt(leg.TransactionID, 10),
+			TransferID:    leg.TransferID.String(),
+			AccountID:     strconv.FormatInt(leg.AccountID, 10),
+			Amount:        leg.Amount.String(),
+			Currency:      leg.Currency.String(),
+			CreatedDate:   leg.CreatedDate.Format(time.RFC3339),
+		}
+	}
+	return resp
+}
+
+// TransactionHistoryResponse is a page of the transaction history of an
+// account. NextCursor is empty once the last page has been reached.
+type TransactionHistoryResponse struct {
+	Items      []TransferLegResponse `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+func fromBusTransactionHistory(legs []transferbus.TransferLeg, limit int) TransactionHistoryResponse {
+	resp := TransactionHistoryResponse{
+		Items: fromBusTransferLegs(legs),
+	}
+	if len(legs) == limit {
+		resp.NextCursor = strconv.FormatInt(legs[len(legs)-1].TransactionID, 10)
+	}
+	return resp
+}
+
+type ReconciliationResponse struct {
+	AccountsScanned int                    `json:"accounts_scanned"`
+	TotalBalances   map[string]string      `json:"total_balances"`
+	Balanced        bool                   `json:"balanced"`
+	Drifts          []AccountDriftResponse `json:"drifts"`
+	ReconciledDate  string                 `json:"reconciled_date"`
+}
+
+type AccountDriftResponse struct {
+	AccountID     string `json:"account_id"`
+	Currency      string `json:"currency"`
+	Balance       string `json:"balance"`
+	LedgerBalance string `json:"ledger_balance"`
+	Drift         string `json:"drift"`
+}
+
+func fromBusReconciliation(r transferbus.Reconciliation) ReconciliationResponse {
+	drifts := make([]AccountDriftResponse, len(r.Drifts))
+	for i, d := range r.Drifts {
+		drifts[i] = AccountDriftResponse{
+			AccountID:     strconv.FormatInt(d.AccountID, 10),
+			Currency:      d.Currency,
+			Balance:       d.Balance.String(),
+			LedgerBalance: d.LedgerBalance.String(),
+			Drift:         d.Drift.String(),
+		}
+	}
+
+	totals := make(map[string]string, len(r.TotalBalances))
+	for cur, total := range r.TotalBalances {
+		totals[cur] = total.String()
+	}
+
+	return ReconciliationResponse{
+		AccountsScanned: r.AccountsScanned,
+		TotalBalances:   totals,
+		Balanced:        r.Balanced(),
+		Drifts:          drifts,
+		ReconciledDate:  r.ReconciledDate.Format(time.RFC3339),
+	}
+}
+
+type QuoteRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
+	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1"`
+	Amount               string `json:"amount" validate:"required"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r QuoteRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusNewQuote(req QuoteRequest) (transferbus.NewQuote, error) {
+	decimalAmount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return transferbus.NewQuote{}, err
+	}
+
+	return transferbus.NewQuote{
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Amount:               decimalAmount,
+	}, nil
+}
+
+type QuoteResponse struct {
+	QuoteID              string `json:"quote_id"`
+	SourceAccountID      string `json:"source_account_id"`
+	DestinationAccountID string `json:"destination_account_id"`
+	SourceCurrency       string `json:"source_currency"`
+	DestinationCurrency  string `json:"destination_currency"`
+	SourceAmount         string `json:"source_amount"`
+	DestinationAmount    string `json:"destination_amount"`
+	Rate                 string `json:"rate"`
+	CreatedDate          string `json:"created_date"`
+	ExpiresDate          string `json:"expires_date"`
+}
+
+func fromBusQuote(quote transferbus.Quote) QuoteResponse {
+	return QuoteResponse{
+		QuoteID:              quote.QuoteID.String(),
+		SourceAccountID:      strconv.FormatInt(quote.SourceAccountID, 10),
+		DestinationAccountID: strconv.FormatInt(quote.DestinationAccountID, 10),
+		SourceCurrency:       quote.SourceCurrency.String(),
+		DestinationCurrency:  quote.DestinationCurrency.String(),
+		SourceAmount:         quote.SourceAmount.String(),
+		DestinationAmount:    quote.DestinationAmount.String(),
+		Rate:                 quote.Rate.String(),
+		CreatedDate:          quote.CreatedDate.Format(time.RFC3339),
+		ExpiresDate:          quote.ExpiresDate.Format(time.RFC3339),
+	}
+}
+
+type HoldRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
+	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1"`
+	Amount               string `json:"amount" validate:"required"`
+	Currency             string `json:"currency,omitempty" validate:"omitempty,len=3"`
+	ExpiresIn            string `json:"expires_in,omitempty"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r HoldRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusNewHold(req HoldRequest) (transferbus.NewHold, error) {
+	decimalAmount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return transferbus.NewHold{}, err
+	}
+
+	var cur currency.Currency
+	if req.Currency != "" {
+		if cur, err = currency.Parse(req.Currency); err != nil {
+			return transferbus.NewHold{}, err
+		}
+	}
+
+	// the expiry is a duration such as 15m and defaults to the one of the bus
+	var expiresIn time.Duration
+	if req.ExpiresIn != "" {
+		if expiresIn, err = time.ParseDuration(req.ExpiresIn); err != nil || expiresIn <= 0 {
+			return transferbus.NewHold{}, fmt.Errorf("expires_in must be a positive duration")
+		}
+	}
+
+	return transferbus.NewHold{
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Amount:               decimalAmount,
+		Currency:             cur,
+		ExpiresIn:            expiresIn,
+	}, nil
+}
+
+// CaptureRequest transfers part of a hold. Without an amount the whole hold is
+// captured.
+type CaptureRequest struct {
+	Amount string `json:"amount,omitempty"`
+}
+
+func toBusHoldCapture(req CaptureRequest, holdID uuid.UUID) (transferbus.HoldCapture, error) {
+	var amount decimal.Decimal
+	if req.Amount != "" {
+		var err error
+		if amount, err = decimal.NewFromString(req.Amount); err != nil {
+			return transferbus.HoldCapture{}, err
+		}
+	}
+
+	return transferbus.HoldCapture{
+		HoldID: holdID,
+		Amount: amount,
+	}, nil
+}
+
+type HoldResponse struct {
+	HoldID               string `json:"hold_id"`
+	SourceAccountID      string `json:"source_account_id"`
+	DestinationAccountID string `json:"destination_account_id"`
+	Currency             string `json:"currency"`
+	Amount               string `json:"amount"`
+	CapturedAmount       string `json:"captured_amount"`
+	Status               string `json:"status"`
+	TransferID           string `json:"transfer_id,omitempty"`
+	CreatedDate          string `json:"created_date"`
+	ExpiresDate          string `json:"expires_date"`
+}
+
+func fromBusHold(hold transferbus.Hold) HoldResponse {
+	var transferID string
+	if hold.TransferID != uuid.Nil {
+		transferID = hold.TransferID.String()
+	}
+
+	return HoldResponse{
+		HoldID:               hold.HoldID.String(),
+		SourceAccountID:      strconv.FormatInt(hold.SourceAccountID, 10),
+		DestinationAccountID: strconv.FormatInt(hold.DestinationAccountID, 10),
+		Currency:             hold.Currency.String(),
+		Amount:               hold.Amount.String(),
+		CapturedAmount:       hold.CapturedAmount.String(),
+		Status:               string(hold.Status),
+		TransferID:           transferID,
+		CreatedDate:          hold.CreatedDate.Format(time.RFC3339),
+		ExpiresDate:          hold.ExpiresDate.Format(time.RFC3339),
+	}
+}
+
+type ScheduledTransferRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
+	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1"`
+	Amount               string `json:"amount" validate:"required"`
+	Currency             string `json:"currency,omitempty" validate:"omitempty,len=3"`
+	Recurrence           string `json:"recurrence,omitempty" validate:"omitempty,max=255"`
+	StartDate            string `json:"start_date,omitempty"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r ScheduledTransferRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusNewScheduledTransfer(req ScheduledTransferRequest) (transferbus.NewScheduledTransfer, error) {
+	decimalAmount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return transferbus.NewScheduledTransfer{}, err
+	}
+
+	var cur currency.Currency
+	if req.Currency != "" {
+		if cur, err = currency.Parse(req.Currency); err != nil {
+			return transferbus.NewScheduledTransfer{}, err
+		}
+	}
+
+	var startDate time.Time
+	if req.StartDate != "" {
+		if startDate, err = time.Parse(time.RFC3339, req.StartDate); err != nil {
+			return transferbus.NewScheduledTransfer{}, fmt.Errorf("start_date must be an RFC3339 timestamp")
+		}
+	}
+
+	return transferbus.NewScheduledTransfer{
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Amount:               decimalAmount,
+		Currency:             cur,
+		Recurrence:           req.Recurrence,
+		StartDate:            startDate,
 	}, nil
 }
+
+// ScheduledTransferUpdateRequest changes a scheduled transfer. Fields left out
+// are not changed, and an empty recurrence makes the transfer a one off.
+type ScheduledTransferUpdateRequest struct {
+	Amount      *string `json:"amount,omitempty"`
+	Recurrence  *string `json:"recurrence,omitempty" validate:"omitempty,max=255"`
+	NextRunDate *string `json:"next_run_date,omitempty"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r ScheduledTransferUpdateRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusUpdateScheduledTransfer(req ScheduledTransferUpdateRequest) (transferbus.UpdateScheduledTransfer, error) {
+	us := transferbus.UpdateScheduledTransfer{
+		Recurrence: req.Recurrence,
+	}
+
+	if req.Amount != nil {
+		amount, err := decimal.NewFromString(*req.Amount)
+		if err != nil {
+			return transferbus.UpdateScheduledTransfer{}, fmt.Errorf("invalid amount")
+		}
+		us.Amount = &amount
+	}
+
+	if req.NextRunDate != nil {
+		nextRun, err := time.Parse(time.RFC3339, *req.NextRunDate)
+		if err != nil {
+			return transferbus.UpdateScheduledTransfer{}, fmt.Errorf("next_run_date must be an RFC3339 timestamp")
+		}
+		us.NextRunDate = &nextRun
+	}
+
+	return us, nil
+}
+
+type ScheduledTransferResponse struct {
+	ScheduleID           string `json:"schedule_id"`
+	SourceAccountID      string `json:"source_account_id"`
+	DestinationAccountID string `json:"destination_account_id"`
+	Currency             string `json:"currency"`
+	Amount               string `json:"amount"`
+	Recurrence           string `json:"recurrence,omitempty"`
+	Status               string `json:"status"`
+	NextRunDate          string `json:"next_run_date"`
+	RunCount             int    `json:"run_count"`
+	LastRunDate          string `json:"last_run_date,omitempty"`
+	LastTransferID       string `json:"last_transfer_id,omitempty"`
+	LastError            string `json:"last_error,omitempty"`
+	CreatedDate          string `json:"created_date"`
+}
+
+func fromBusScheduledTransfer(st transferbus.ScheduledTransfer) ScheduledTransferResponse {
+	var lastRunDate string
+	if !st.LastRunDate.IsZero() {
+		lastRunDate = st.LastRunDate.Format(time.RFC3339)
+	}
+
+	var lastTransferID string
+	if st.LastTransferID != uuid.Nil {
+		lastTransferID = st.LastTransferID.String()
+	}
+
+	return ScheduledTransferResponse{
+		ScheduleID:           st.ScheduleID.String(),
+		SourceAccountID:      strconv.FormatInt(st.SourceAccountID, 10),
+		DestinationAccountID: strconv.FormatInt(st.DestinationAccountID, 10),
+		Currency:             st.Currency.String(),
+		Amount:               st.Amount.String(),
+		Recurrence:           st.Recurrence,
+		Status:               string(st.Status),
+		NextRunDate:          st.NextRunDate.Format(time.RFC3339),
+		RunCount:             st.RunCount,
+		LastRunDate:          lastRunDate,
+		LastTransferID:       lastTransferID,
+		LastError:            st.LastError,
+		CreatedDate:          st.CreatedDate.Format(time.RFC3339),
+	}
+}
+
+// WebhookRequest subscribes a url to the events of an account.
+type WebhookRequest struct {
+	AccountID int64  `json:"account_id" validate:"required,min=1"`
+	URL       string `json:"url" validate:"required,url,max=2048"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r WebhookRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusNewWebhookSubscription(req WebhookRequest) transferbus.NewWebhookSubscription {
+	return transferbus.NewWebhookSubscription{
+		AccountID: req.AccountID,
+		URL:       req.URL,
+	}
+}
+
+// WebhookUpdateRequest changes the url of a webhook subscription or pauses and
+// resumes it. Fields left out are not changed.
+type WebhookUpdateRequest struct {
+	URL    *string `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=active paused"`
+}
+
+// Validate checks if the data in the model is considered clean.
+func (r WebhookUpdateRequest) Validate() error {
+	if err := validate.Check(r); err != nil {
+		return customerror.Newf(customerror.FailedPrecondition, "validate: %s", err)
+	}
+	return nil
+}
+
+func toBusUpdateWebhookSubscription(req WebhookUpdateRequest) transferbus.UpdateWebhookSubscription {
+	uw := transferbus.UpdateWebhookSubscription{
+		URL: req.URL,
+	}
+	if req.Status != nil {
+		status := transferbus.WebhookStatus(*req.Status)
+		uw.Status = &status
+	}
+	return uw
+}
+
+// WebhookResponse is a webhook subscription. Secret is only returned when the
+// subscription is created.
+type WebhookResponse struct {
+	SubscriptionID string `json:"subscription_id"`
+	AccountID      string `json:"account_id"`
+	URL            string `json:"url"`
+	Status         string `json:"status"`
+	Secret         string `json:"secret,omitempty"`
+	CreatedDate    string `json:"created_date"`
+}
+
+func fromBusWebhookSubscription(sub transferbus.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		SubscriptionID: sub.SubscriptionID.String(),
+		AccountID:      strconv.FormatInt(sub.AccountID, 10),
+		URL:            sub.URL,
+		Status:         string(sub.Status),
+		CreatedDate:    sub.CreatedDate.Format(time.RFC3339),
+	}
+}
+
+type WebhooksResponse struct {
+	Items []WebhookResponse `json:"items"`
+}
+
+func fromBusWebhookSubscriptions(subs []transferbus.WebhookSubscription) WebhooksResponse {
+	resp := WebhooksResponse{
+		Items: make([]WebhookResponse, len(subs)),
+	}
+	for i, sub := range subs {
+		resp.Items[i] = fromBusWebhookSubscription(sub)
+	}
+	return resp
+}
+
+type WebhookDeliveryResponse struct {
+	DeliveryID      string `json:"delivery_id"`
+	SubscriptionID  string `json:"subscription_id"`
+	EventID         string `json:"event_id"`
+	Status          string `json:"status"`
+	Attempts        int    `json:"attempts"`
+	NextAttemptDate string `json:"next_attempt_date,omitempty"`
+	LastAttemptDate string `json:"last_attempt_date,omitempty"`
+	LastStatusCode  int    `json:"last_status_code,omitempty"`
+	LastError       string `json:"last_error,omitempty"`
+	CreatedDate     string `json:"created_date"`
+}
+
+// WebhookDeliveriesResponse is a page of the delivery log of a webhook
+// subscription. NextCursor is empty once the last page has been reached.
+type WebhookDeliveriesResponse struct {
+	Items      []WebhookDeliveryResponse `json:"items"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+func fromBusWebhookDeliveries(deliveries []transferbus.WebhookDelivery, limit int) WebhookDeliveriesResponse {
+	resp := WebhookDeliveriesResponse{
+		Items: make([]WebhookDeliveryResponse, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		// only pending deliveries are attempted again
+		var nextAttemptDate string
+		if d.Status == transferbus.DeliveryStatusPending {
+			nextAttemptDate = d.NextAttemptDate.Format(time.RFC3339)
+		}
+
+		var lastAttemptDate string
+		if !d.LastAttemptDate.IsZero() {
+			lastAttemptDate = d.LastAttemptDate.Format(time.RFC3339)
+		}
+
+		resp.Items[i] = WebhookDeliveryResponse{
+			DeliveryID:      strconv.FormatInt(d.DeliveryID, 10),
+			SubscriptionID:  d.SubscriptionID.String(),
+			EventID:         strconv.FormatInt(d.EventID, 10),
+			Status:          string(d.Status),
+			Attempts:        d.Attempts,
+			NextAttemptDate: nextAttemptDate,
+			LastAttemptDate: lastAttemptDate,
+			LastStatusCode:  d.LastStatusCode,
+			LastError:       d.LastError,
+			CreatedDate:     d.CreatedDate.Format(time.RFC3339),
+		}
+	}
+	if len(deliveries) == limit {
+		resp.NextCursor = strconv.FormatInt(deliveries[len(deliveries)-1].DeliveryID, 10)
+	}
+	return resp
+}
+
+// AccountEventResponse is a change to the balance of an account, sent on the
+// event stream of the account. The event id of the stream is EventID.
+type AccountEventResponse struct {
+	EventID     string          `json:"event_id"`
+	Type        string          `json:"type"`
+	AccountID   string          `json:"account_id"`
+	Balance     string          `json:"balance"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedDate string          `json:"created_date"`
+}
+
+func fromBusEvent(event transferbus.Event) AccountEventResponse {
+	return AccountEventResponse{
+		EventID:     strconv.FormatInt(event.EventID, 10),
+		Type:        string(event.Type),
+		AccountID:   strconv.FormatInt(event.AccountID, 10),
+		Balance:     event.Balance.String(),
+		Payload:     event.Payload,
+		CreatedDate: event.CreatedDate.Format(time.RFC3339),
+	}
+}
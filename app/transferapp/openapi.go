@@ -0,0 +1,429 @@
+package transferapp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/danipurwadi/internal-transfer-system/foundation/openapi"
+	"github.com/danipurwadi/internal-transfer-system/foundation/web"
+)
+
+// HealthResponse reports that the service is up.
+type HealthResponse struct {
+	Status bool `json:"status"`
+}
+
+// operation describes a route of the API. Request and response are zero values
+// of the bodies, and a nil response is any JSON object. Every route may also
+// fail with a 500 Internal Server Error.
+type operation struct {
+	method      string
+	path        string
+	id          string
+	summary     string
+	params      []openapi.Parameter
+	request     any
+	status      int
+	response    any
+	contentType string
+	location    bool
+	errors      []int
+}
+
+// operations describes every route registered by Routes.
+func operations() []operation {
+	accountID := pathParam("account_id", "integer", "The id of the account.")
+	transferID := pathParam("transfer_id", "uuid", "The id of the transfer.")
+	holdID := pathParam("hold_id", "uuid", "The id of the hold.")
+	scheduleID := pathParam("schedule_id", "uuid", "The id of the scheduled transfer.")
+	subscriptionID := pathParam("subscription_id", "uuid", "The id of the webhook subscription.")
+
+	cursor := queryParam("cursor", &openapi.Schema{Type: "string"}, "The next_cursor returned by the previous page.")
+	limit := queryParam("limit", &openapi.Schema{Type: "integer", Minimum: float(1), Maximum: float(maxPageLimit)}, "The page size. Defaults to "+strconv.Itoa(defaultPageLimit)+".")
+
+	return []operation{
+		{
+			method: http.MethodGet, path: "/health", id: "health",
+			summary: "Reports that the service is up.",
+			status:  http.StatusOK, response: HealthResponse{},
+		},
+		{
+			method: http.MethodGet, path: "/openapi.json", id: "openAPI",
+			summary: "Returns this OpenAPI document.",
+			status:  http.StatusOK,
+		},
+		{
+			method: http.MethodPost, path: "/accounts", id: "createAccount",
+			summary: "Opens an account funded with its initial balance.",
+			request: AccountCreationRequest{},
+			status:  http.StatusCreated, response: AccountResponse{}, location: true,
+			errors: []int{http.StatusBadRequest, http.StatusConflict},
+		},
+		{
+			method: http.MethodGet, path: "/accounts", id: "queryAccounts",
+			summary: "Searches accounts by their metadata, ordered by account id.",
+			params: []openapi.Parameter{
+				queryParam("owner", &openapi.Schema{Type: "string"}, "Only include accounts of this owner."),
+				queryParam("type", &openapi.Schema{Type: "string", Enum: []string{"customer", "operational", "fee", "suspense"}}, "Only include accounts of this type."),
+				queryParam("label", &openapi.Schema{Type: "string", MinLength: integer(1)}, "Only include accounts carrying every given label. May be repeated."),
+				cursor,
+				limit,
+			},
+			status: http.StatusOK, response: AccountsResponse{},
+			errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: "/accounts/{account_id}", id: "getBalance",
+			summary: "Returns an account with its balances.",
+			params:  []openapi.Parameter{accountID},
+			status:  http.StatusOK, response: BalanceResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPatch, path: "/accounts/{account_id}", id: "updateAccount",
+			summary: "Updates the metadata of an account. Fields left out are not changed.",
+			params:  []openapi.Parameter{accountID},
+			request: AccountUpdateRequest{},
+			status:  http.StatusOK, response: BalanceResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/accounts/{account_action}", id: "changeAccountStatus",
+			summary: "Freezes, unfreezes or closes an account. The body is only read when closing.",
+			params: []openapi.Parameter{
+				{
+					Name: "account_action", In: "path", Required: true,
+					Description: "The account id followed by :freeze, :unfreeze or :close.",
+					Schema:      &openapi.Schema{Type: "string"},
+				},
+			},
+			request: AccountCloseRequest{},
+			status:  http.StatusOK, response: BalanceResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/accounts/{account_id}/transactions", id: "queryAccountTransactions",
+			summary: "Lists the debits and credits of an account, latest first.",
+			params: []openapi.Parameter{
+				accountID,
+				cursor,
+				limit,
+				queryParam("start_date", &openapi.Schema{Type: "string", Format: "date-time"}, "Only include transactions created at or after this time."),
+				queryParam("end_date", &openapi.Schema{Type: "string", Format: "date-time"}, "Only include transactions created before this time."),
+				queryParam("direction", &openapi.Schema{Type: "string", Enum: []string{"debit", "credit"}}, "Only include debits or credits."),
+			},
+			status: http.StatusOK, response: TransactionHistoryResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/accounts/{account_id}/events", id: "streamAccountEvents",
+			summary: "Streams the balance changes of an account as server-sent events, whose data is an AccountEventResponse.",
+			params: []openapi.Parameter{
+				accountID,
+				{
+					Name: lastEventIDHeader, In: "header",
+					Description: "Resumes the stream after this event.",
+					Schema:      &openapi.Schema{Type: "string"},
+				},
+				queryParam("last_event_id", &openapi.Schema{Type: "string"}, "Resumes the stream after this event, for clients unable to set headers."),
+			},
+			status: http.StatusOK, response: AccountEventResponse{}, contentType: "text/event-stream",
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/accounts/{account_id}/webhooks", id: "queryAccountWebhooks",
+			summary: "Lists the webhook subscriptions of an account in the order they were created.",
+			params:  []openapi.Parameter{accountID},
+			status:  http.StatusOK, response: WebhooksResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/transactions", id: "createTransaction",
+			summary: "Moves funds from one account to another.",
+			params: []openapi.Parameter{
+				{
+					Name: idempotencyKeyHeader, In: "header",
+					Description: "Retries with the same key are only posted once. Takes precedence over idempotency_key.",
+					Schema:      &openapi.Schema{Type: "string", MaxLength: integer(maxIdempotencyKeyLen)},
+				},
+			},
+			request: TransactionRequest{},
+			status:  http.StatusCreated, response: TransferCreatedResponse{}, location: true,
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests},
+		},
+		{
+			method: http.MethodPost, path: "/transactions:batch", id: "createTransactions",
+			summary: "Posts many transactions at once, either all or none (atomic) or each on its own (best_effort).",
+			request: BatchRequest{},
+			status:  http.StatusOK, response: BatchResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests},
+		},
+		{
+			method: http.MethodGet, path: "/transactions/{transfer_id}", id: "queryTransfer",
+			summary: "Returns a transfer with its legs.",
+			params:  []openapi.Parameter{transferID},
+			status:  http.StatusOK, response: TransferResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/transactions/{transfer_id}/reversal", id: "reverseTransfer",
+			summary: "Sends back a transfer, or part of it.",
+			params:  []openapi.Parameter{transferID},
+			request: ReversalRequest{},
+			status:  http.StatusCreated, response: TransferResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodPost, path: "/fx/quotes", id: "createQuote",
+			summary: "Locks the exchange rate of a cross-currency transfer for a short time.",
+			request: QuoteRequest{},
+			status:  http.StatusCreated, response: QuoteResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodPost, path: "/holds", id: "authorizeTransfer",
+			summary: "Reserves funds of the source account for a later capture.",
+			request: HoldRequest{},
+			status:  http.StatusCreated, response: HoldResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodGet, path: "/holds/{hold_id}", id: "queryHold",
+			summary: "Returns a hold.",
+			params:  []openapi.Parameter{holdID},
+			status:  http.StatusOK, response: HoldResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/holds/{hold_id}/capture", id: "captureHold",
+			summary: "Posts the transfer of a hold, for its whole amount or less.",
+			params:  []openapi.Parameter{holdID},
+			request: CaptureRequest{},
+			status:  http.StatusCreated, response: TransferResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests},
+		},
+		{
+			method: http.MethodPost, path: "/holds/{hold_id}/void", id: "voidHold",
+			summary: "Releases the funds reserved by a hold.",
+			params:  []openapi.Parameter{holdID},
+			status:  http.StatusOK, response: HoldResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodPost, path: "/scheduled-transfers", id: "createScheduledTransfer",
+			summary: "Schedules a transfer to run once or on a recurrence.",
+			request: ScheduledTransferRequest{},
+			status:  http.StatusCreated, response: ScheduledTransferResponse{}, location: true,
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/scheduled-transfers/{schedule_id}", id: "queryScheduledTransfer",
+			summary: "Returns a scheduled transfer with its run history.",
+			params:  []openapi.Parameter{scheduleID},
+			status:  http.StatusOK, response: ScheduledTransferResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPatch, path: "/scheduled-transfers/{schedule_id}", id: "updateScheduledTransfer",
+			summary: "Changes a scheduled transfer. Fields left out are not changed.",
+			params:  []openapi.Parameter{scheduleID},
+			request: ScheduledTransferUpdateRequest{},
+			status:  http.StatusOK, response: ScheduledTransferResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodDelete, path: "/scheduled-transfers/{schedule_id}", id: "cancelScheduledTransfer",
+			summary: "Cancels a scheduled transfer, which is kept with its run history.",
+			params:  []openapi.Parameter{scheduleID},
+			status:  http.StatusOK, response: ScheduledTransferResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: "/webhooks", id: "createWebhook",
+			summary: "Subscribes a url to the events of an account. The secret signing the deliveries is only returned here.",
+			request: WebhookRequest{},
+			status:  http.StatusCreated, response: WebhookResponse{}, location: true,
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/webhooks/{subscription_id}", id: "queryWebhook",
+			summary: "Returns a webhook subscription, without its secret.",
+			params:  []openapi.Parameter{subscriptionID},
+			status:  http.StatusOK, response: WebhookResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPatch, path: "/webhooks/{subscription_id}", id: "updateWebhook",
+			summary: "Changes the url of a subscription, or pauses and resumes it.",
+			params:  []openapi.Parameter{subscriptionID},
+			request: WebhookUpdateRequest{},
+			status:  http.StatusOK, response: WebhookResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodDelete, path: "/webhooks/{subscription_id}", id: "deleteWebhook",
+			summary: "Deletes a webhook subscription along with its deliveries.",
+			params:  []openapi.Parameter{subscriptionID},
+			status:  http.StatusNoContent,
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: "/webhooks/{subscription_id}/deliveries", id: "queryWebhookDeliveries",
+			summary: "Lists the deliveries of a webhook subscription, latest first.",
+			params: []openapi.Parameter{
+				subscriptionID,
+				cursor,
+				limit,
+				queryParam("status", &openapi.Schema{Type: "string", Enum: []string{"pending", "delivered", "dead"}}, "Only include deliveries with this status."),
+			},
+			status: http.StatusOK, response: WebhookDeliveriesResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPut, path: "/admin/accounts/{account_id}/overdraft-limit", id: "setOverdraftLimit",
+			summary: "Sets how far the balance of an account may go below zero.",
+			params:  []openapi.Parameter{accountID},
+			request: OverdraftLimitRequest{},
+			status:  http.StatusOK, response: BalanceResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPut, path: "/admin/accounts/{account_id}/limits", id: "setAccountLimits",
+			summary: "Sets the limits on the outbound transfers of an account.",
+			params:  []openapi.Parameter{accountID},
+			request: TransferLimitsRequest{},
+			status:  http.StatusOK, response: TransferLimitsResponse{},
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPut, path: "/admin/account-types/{account_type}/limits", id: "setAccountTypeLimits",
+			summary: "Sets the limits on the outbound transfers of every account of a type in a currency.",
+			params: []openapi.Parameter{
+				{
+					Name: "account_type", In: "path", Required: true,
+					Schema: &openapi.Schema{Type: "string", Enum: []string{"customer", "operational", "fee", "suspense"}},
+				},
+			},
+			request: AccountTypeLimitsRequest{},
+			status:  http.StatusOK, response: TransferLimitsResponse{},
+			errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: "/admin/reconciliation", id: "reconcile",
+			summary: "Compares the balance of every account against the sum of its postings.",
+			params: []openapi.Parameter{
+				queryParam("batch_size", &openapi.Schema{Type: "integer", Minimum: float(1), Maximum: float(maxReconcileBatchSize)}, "The number of accounts scanned at a time."),
+				queryParam("alert", &openapi.Schema{Type: "boolean"}, "Logs every finding at error level."),
+			},
+			status: http.StatusOK, response: ReconciliationResponse{},
+			errors: []int{http.StatusBadRequest},
+		},
+	}
+}
+
+// OpenAPI returns the OpenAPI document describing the routes of the app.
+func OpenAPI() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Internal Transfer System",
+		Description: "Accounts and transfers between them. Amounts and balances are decimal strings.",
+		Version:     "1.0.0",
+	})
+
+	errSchema := doc.Schema(customerror.Error{})
+	doc.Components.Schemas["Error"].Properties["code"].Enum = customerror.CodeNames()
+
+	for _, op := range operations() {
+		spec := openapi.Operation{
+			OperationID: op.id,
+			Summary:     op.summary,
+			Parameters:  op.params,
+			Responses:   make(map[string]openapi.Response),
+		}
+
+		if op.request != nil {
+			spec.RequestBody = &openapi.RequestBody{
+				Content: map[string]openapi.MediaType{
+					"application/json": {Schema: doc.Schema(op.request)},
+				},
+			}
+		}
+
+		resp := openapi.Response{
+			Description: http.StatusText(op.status),
+		}
+		if op.status != http.StatusNoContent {
+			contentType := op.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+
+			schema := &openapi.Schema{Type: "object"}
+			if op.response != nil {
+				schema = doc.Schema(op.response)
+			}
+			resp.Content = map[string]openapi.MediaType{
+				contentType: {Schema: schema},
+			}
+		}
+		if op.location {
+			resp.Headers = map[string]openapi.Header{
+				"Location": {Description: "The path of the created resource.", Schema: &openapi.Schema{Type: "string"}},
+			}
+		}
+		spec.Responses[strconv.Itoa(op.status)] = resp
+
+		for _, status := range append(op.errors, http.StatusInternalServerError) {
+			spec.Responses[strconv.Itoa(status)] = openapi.Response{
+				Description: http.StatusText(status),
+				Content: map[string]openapi.MediaType{
+					"application/json": {Schema: errSchema},
+				},
+			}
+		}
+
+		doc.Add(op.method, op.path, spec)
+	}
+
+	return doc
+}
+
+func (a *App) openAPI(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return web.Respond(ctx, w, a.openapi, http.StatusOK)
+}
+
+// =============================================================================
+
+// pathParam describes a path parameter holding an integer or a uuid.
+func pathParam(name string, kind string, description string) openapi.Parameter {
+	schema := openapi.Schema{Type: "integer", Format: "int64", Minimum: float(1)}
+	if kind == "uuid" {
+		schema = openapi.Schema{Type: "string", Format: "uuid"}
+	}
+
+	return openapi.Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Required:    true,
+		Schema:      &schema,
+	}
+}
+
+func queryParam(name string, schema *openapi.Schema, description string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      schema,
+	}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func integer(v int) *int {
+	return &v
+}
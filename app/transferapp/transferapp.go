@@ -5,35 +5,85 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
 	"github.com/danipurwadi/internal-transfer-system/foundation/customerror"
+	"github.com/danipurwadi/internal-transfer-system/foundation/openapi"
 	"github.com/danipurwadi/internal-transfer-system/foundation/web"
+	"github.com/google/uuid"
+)
+
+// The Idempotency-Key header lets callers safely retry a transaction. It takes
+// precedence over the idempotency_key field of the request body.
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+// Account event streams resume after the event named by the Last-Event-ID
+// header, or the last_event_id query parameter for clients unable to set
+// headers. Idle streams send a heartbeat so proxies keep them open.
+const (
+	lastEventIDHeader    = "Last-Event-ID"
+	streamBatchSize      = 100
+	streamHeartbeatEvery = 15 * time.Second
 )
 
 type App struct {
 	transferbus *transferbus.Bus
+	openapi     *openapi.Document
 }
 
 func NewApp(bus *transferbus.Bus) *App {
 	return &App{
 		transferbus: bus,
+		openapi:     OpenAPI(),
 	}
 }
 
 func (a *App) Routes(mux *web.Client) {
 	mux.Handle(http.MethodGet, "/health", a.health)
+	mux.Handle(http.MethodGet, "/openapi.json", a.openAPI)
 	mux.Handle(http.MethodPost, "/accounts", a.createAccount)
+	mux.Handle(http.MethodGet, "/accounts", a.queryAccounts)
 	mux.Handle(http.MethodGet, "/accounts/{account_id}", a.getBalance)
+	mux.Handle(http.MethodPatch, "/accounts/{account_id}", a.updateAccount)
+	mux.Handle(http.MethodPost, "/accounts/{account_action}", a.changeAccountStatus)
+	mux.Handle(http.MethodGet, "/accounts/{account_id}/transactions", a.queryAccountTransactions)
+	mux.HandleStream(http.MethodGet, "/accounts/{account_id}/events", a.streamAccountEvents)
+	mux.Handle(http.MethodGet, "/accounts/{account_id}/webhooks", a.queryAccountWebhooks)
 	mux.Handle(http.MethodPost, "/transactions", a.createTransaction)
+	mux.Handle(http.MethodPost, "/transactions:batch", a.createTransactions)
+	mux.Handle(http.MethodGet, "/transactions/{transfer_id}", a.queryTransfer)
+	mux.Handle(http.MethodPost, "/transactions/{transfer_id}/reversal", a.reverseTransfer)
+	mux.Handle(http.MethodPost, "/fx/quotes", a.createQuote)
+	mux.Handle(http.MethodPost, "/holds", a.authorizeTransfer)
+	mux.Handle(http.MethodGet, "/holds/{hold_id}", a.queryHold)
+	mux.Handle(http.MethodPost, "/holds/{hold_id}/capture", a.captureHold)
+	mux.Handle(http.MethodPost, "/holds/{hold_id}/void", a.voidHold)
+	mux.Handle(http.MethodPost, "/scheduled-transfers", a.createScheduledTransfer)
+	mux.Handle(http.MethodGet, "/scheduled-transfers/{schedule_id}", a.queryScheduledTransfer)
+	mux.Handle(http.MethodPatch, "/scheduled-transfers/{schedule_id}", a.updateScheduledTransfer)
+	mux.Handle(http.MethodDelete, "/scheduled-transfers/{schedule_id}", a.cancelScheduledTransfer)
+	mux.Handle(http.MethodPost, "/webhooks", a.createWebhook)
+	mux.Handle(http.MethodGet, "/webhooks/{subscription_id}", a.queryWebhook)
+	mux.Handle(http.MethodPatch, "/webhooks/{subscription_id}", a.updateWebhook)
+	mux.Handle(http.MethodDelete, "/webhooks/{subscription_id}", a.deleteWebhook)
+	mux.Handle(http.MethodGet, "/webhooks/{subscription_id}/deliveries", a.queryWebhookDeliveries)
+	mux.Handle(http.MethodPut, "/admin/accounts/{account_id}/overdraft-limit", a.setOverdraftLimit)
+	mux.Handle(http.MethodPut, "/admin/accounts/{account_id}/limits", a.setAccountLimits)
+	mux.Handle(http.MethodPut, "/admin/account-types/{account_type}/limits", a.setAccountTypeLimits)
+	mux.Handle(http.MethodGet, "/admin/reconciliation", a.reconcile)
 }
 
 func (a *App) health(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	status := struct {
-		Status bool `json:"status"`
-	}{
+	status := HealthResponse{
 		Status: true,
 	}
 
@@ -51,18 +101,31 @@ func (a *App) createAccount(ctx context.Context, w http.ResponseWriter, r *http.
 		return customerror.New(customerror.FailedPrecondition, err)
 	}
 
-	_, err = a.transferbus.CreateAccount(ctx, account)
+	acc, err := a.transferbus.CreateAccount(ctx, account)
 	if err != nil {
-		if errors.Is(err, transferbus.ErrAccAlreadyExist) {
-			return customerror.New(customerror.AlreadyExists, err)
-		}
-		if errors.Is(err, transferbus.ErrNegativeBalance) {
-			return customerror.New(customerror.InvalidArgument, err)
-		}
-		return customerror.New(customerror.Internal, err)
+		return accountCreationError(err)
 	}
 
-	return web.Respond(ctx, w, nil, http.StatusCreated)
+	w.Header().Set("Location", fmt.Sprintf("/accounts/%d", acc.AccountID))
+	return web.Respond(ctx, w, fromBusAccount(acc), http.StatusCreated)
+}
+
+// accountCreationError maps the errors of opening an account to the error
+// returned to the client.
+func accountCreationError(err error) customerror.Error {
+	if errors.Is(err, transferbus.ErrAccAlreadyExist) {
+		return customerror.New(customerror.AlreadyExists, err)
+	}
+	if errors.Is(err, transferbus.ErrNegativeBalance) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrAccIDReserved) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrTxAborted) {
+		return customerror.New(customerror.Aborted, err)
+	}
+	return customerror.New(customerror.Internal, err)
 }
 
 func (a *App) getBalance(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -87,6 +150,108 @@ func (a *App) getBalance(ctx context.Context, w http.ResponseWriter, r *http.Req
 	return web.Respond(ctx, w, fromBusAccBalance(balance), http.StatusOK)
 }
 
+func (a *App) updateAccount(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accID, err := strconv.ParseInt(r.PathValue("account_id"), 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	var req AccountUpdateRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	account, err := a.transferbus.UpdateAccount(ctx, accID, toBusUpdateAccount(req))
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to update account: accId[%d]: %s", accID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusAccBalance(account), http.StatusOK)
+}
+
+func (a *App) queryAccounts(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseAccountFilter(r)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	accounts, err := a.transferbus.QueryAccounts(ctx, filter)
+	if err != nil {
+		return customerror.Newf(customerror.Internal, "failed to query accounts: %s", err)
+	}
+
+	return web.Respond(ctx, w, fromBusAccounts(accounts, filter.Limit), http.StatusOK)
+}
+
+// changeAccountStatus serves POST /accounts/{account_id}:freeze, :unfreeze and
+// :close. The action is part of the last path segment, so it is split off the
+// account id here.
+func (a *App) changeAccountStatus(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accountID, action, ok := strings.Cut(r.PathValue("account_action"), ":")
+	if !ok {
+		return customerror.Newf(customerror.NotFound, "unknown account action")
+	}
+
+	accID, err := strconv.ParseInt(accountID, 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	var account transferbus.Account
+	switch action {
+	case "freeze":
+		account, err = a.transferbus.FreezeAccount(ctx, accID)
+	case "unfreeze":
+		account, err = a.transferbus.UnfreezeAccount(ctx, accID)
+	case "close":
+		var req AccountCloseRequest
+		if err := web.Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if err := req.Validate(); err != nil {
+			return err
+		}
+		account, err = a.transferbus.CloseAccount(ctx, toBusAccountClosure(accID, req))
+	default:
+		return customerror.Newf(customerror.NotFound, "unknown account action %q", action)
+	}
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrAccFrozen) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccNotEmpty) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccHasHolds) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrSameAccount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrCurrencyMismatch) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrSystemAccount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.New(customerror.Internal, err)
+	}
+
+	return web.Respond(ctx, w, fromBusAccBalance(account), http.StatusOK)
+}
+
 func (a *App) createTransaction(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	var req TransactionRequest
 
@@ -94,12 +259,218 @@ func (a *App) createTransaction(ctx context.Context, w http.ResponseWriter, r *h
 		return customerror.New(customerror.FailedPrecondition, err)
 	}
 
+	if key := r.Header.Get(idempotencyKeyHeader); key != "" {
+		req.IdempotencyKey = key
+	}
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
+		return customerror.Newf(customerror.InvalidArgument, "idempotency key must not exceed %d characters", maxIdempotencyKeyLen)
+	}
+
 	t, err := toBusTransaction(req)
 	if err != nil {
 		return customerror.New(customerror.FailedPrecondition, err)
 	}
 
-	err = a.transferbus.CreateTransaction(ctx, t)
+	transfer, err := a.transferbus.CreateTransaction(ctx, t)
+	if err != nil {
+		return transactionError(err)
+	}
+
+	w.Header().Set("Location", "/transactions/"+transfer.TransferID.String())
+	return web.Respond(ctx, w, fromBusTransferCreated(transfer), http.StatusCreated)
+}
+
+// transactionError maps the errors of a transaction to the error returned to
+// the client.
+func transactionError(err error) customerror.Error {
+	if errors.Is(err, transferbus.ErrAccNotFound) {
+		return customerror.New(customerror.NotFound, err)
+	}
+	if errors.Is(err, transferbus.ErrInsufficientFunds) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrSameAccount) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrNegativeBalance) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrZeroAmount) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrIdempotencyKeyReused) {
+		return customerror.New(customerror.AlreadyExists, err)
+	}
+	if errors.Is(err, transferbus.ErrCurrencyMismatch) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrQuoteNotFound) {
+		return customerror.New(customerror.NotFound, err)
+	}
+	if errors.Is(err, transferbus.ErrQuoteExpired) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrQuoteUsed) {
+		return customerror.New(customerror.AlreadyExists, err)
+	}
+	if errors.Is(err, transferbus.ErrQuoteMismatch) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrAccFrozen) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrAccClosed) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrLimitExceeded) {
+		return customerror.New(customerror.ResourceExhausted, err)
+	}
+	if errors.Is(err, transferbus.ErrTxAborted) {
+		return customerror.New(customerror.Aborted, err)
+	}
+	return customerror.New(customerror.Internal, err)
+}
+
+func (a *App) createTransactions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req BatchRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	transactions, mode, err := toBusBatch(req)
+	if err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	results, err := a.transferbus.CreateTransactions(ctx, transactions, mode)
+	if err != nil {
+		var batchErr *transferbus.BatchError
+		if errors.As(err, &batchErr) {
+			itemErr := transactionError(batchErr.Err)
+			return customerror.Newf(itemErr.Code, "transactions[%d]: %s", batchErr.Index, itemErr.Message)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.New(customerror.Internal, err)
+	}
+
+	return web.Respond(ctx, w, fromBusBatchResults(results, mode), http.StatusOK)
+}
+
+func (a *App) reverseTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	transferID, err := uuid.Parse(r.PathValue("transfer_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid transfer id"))
+	}
+
+	// the body is optional, a reversal without one sends back the whole transfer
+	var req ReversalRequest
+	if err := web.Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	reversal, err := toBusReversal(req, transferID)
+	if err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	transfer, err := a.transferbus.ReverseTransfer(ctx, reversal)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrTransferNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrNegativeBalance) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrReversalExceeded) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrZeroAmount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrNotReversible) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrInsufficientFunds) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccFrozen) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.New(customerror.Internal, err)
+	}
+
+	return web.Respond(ctx, w, fromBusTransfer(transfer), http.StatusCreated)
+}
+
+func (a *App) createQuote(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req QuoteRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	nq, err := toBusNewQuote(req)
+	if err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	quote, err := a.transferbus.CreateQuote(ctx, nq)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrSameAccount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrNegativeBalance) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, fxbus.ErrInvalidAmount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrZeroAmount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, fxbus.ErrSameCurrency) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, fxbus.ErrRateNotFound) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccFrozen) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.New(customerror.Internal, err)
+	}
+
+	return web.Respond(ctx, w, fromBusQuote(quote), http.StatusCreated)
+}
+
+func (a *App) authorizeTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req HoldRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	nh, err := toBusNewHold(req)
+	if err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	hold, err := a.transferbus.AuthorizeTransfer(ctx, nh)
 	if err != nil {
 		if errors.Is(err, transferbus.ErrAccNotFound) {
 			return customerror.New(customerror.NotFound, err)
@@ -113,8 +484,592 @@ func (a *App) createTransaction(ctx context.Context, w http.ResponseWriter, r *h
 		if errors.Is(err, transferbus.ErrNegativeBalance) {
 			return customerror.New(customerror.InvalidArgument, err)
 		}
+		if errors.Is(err, transferbus.ErrZeroAmount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrCurrencyMismatch) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccFrozen) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
 		return customerror.New(customerror.Internal, err)
 	}
 
-	return web.Respond(ctx, w, nil, http.StatusCreated)
+	return web.Respond(ctx, w, fromBusHold(hold), http.StatusCreated)
+}
+
+func (a *App) queryHold(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	holdID, err := uuid.Parse(r.PathValue("hold_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid hold id"))
+	}
+
+	hold, err := a.transferbus.QueryHold(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrHoldNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to query hold: holdId[%s]: %s", holdID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusHold(hold), http.StatusOK)
+}
+
+func (a *App) captureHold(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	holdID, err := uuid.Parse(r.PathValue("hold_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid hold id"))
+	}
+
+	// the body is optional, a capture without one transfers the whole hold
+	var req CaptureRequest
+	if err := web.Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	capture, err := toBusHoldCapture(req, holdID)
+	if err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	transfer, err := a.transferbus.Capture(ctx, capture)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrHoldNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrNegativeBalance) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrCaptureExceeded) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrZeroAmount) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrHoldClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrHoldExpired) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccFrozen) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrAccClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrInsufficientFunds) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrLimitExceeded) {
+			return customerror.New(customerror.ResourceExhausted, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.New(customerror.Internal, err)
+	}
+
+	return web.Respond(ctx, w, fromBusTransfer(transfer), http.StatusCreated)
+}
+
+func (a *App) voidHold(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	holdID, err := uuid.Parse(r.PathValue("hold_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid hold id"))
+	}
+
+	hold, err := a.transferbus.Void(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrHoldNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrHoldClosed) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.New(customerror.Internal, err)
+	}
+
+	return web.Respond(ctx, w, fromBusHold(hold), http.StatusOK)
+}
+
+func (a *App) createScheduledTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req ScheduledTransferRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	ns, err := toBusNewScheduledTransfer(req)
+	if err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	st, err := a.transferbus.CreateScheduledTransfer(ctx, ns)
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	w.Header().Set("Location", "/scheduled-transfers/"+st.ScheduleID.String())
+	return web.Respond(ctx, w, fromBusScheduledTransfer(st), http.StatusCreated)
+}
+
+func (a *App) queryScheduledTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	scheduleID, err := uuid.Parse(r.PathValue("schedule_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid schedule id"))
+	}
+
+	st, err := a.transferbus.QueryScheduledTransfer(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrScheduleNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to query scheduled transfer: scheduleId[%s]: %s", scheduleID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusScheduledTransfer(st), http.StatusOK)
+}
+
+func (a *App) updateScheduledTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	scheduleID, err := uuid.Parse(r.PathValue("schedule_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid schedule id"))
+	}
+
+	var req ScheduledTransferUpdateRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	us, err := toBusUpdateScheduledTransfer(req)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	st, err := a.transferbus.UpdateScheduledTransfer(ctx, scheduleID, us)
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return web.Respond(ctx, w, fromBusScheduledTransfer(st), http.StatusOK)
+}
+
+func (a *App) cancelScheduledTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	scheduleID, err := uuid.Parse(r.PathValue("schedule_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid schedule id"))
+	}
+
+	st, err := a.transferbus.CancelScheduledTransfer(ctx, scheduleID)
+	if err != nil {
+		return scheduleError(err)
+	}
+
+	return web.Respond(ctx, w, fromBusScheduledTransfer(st), http.StatusOK)
+}
+
+// scheduleError maps the errors of a scheduled transfer to the error returned
+// to the client.
+func scheduleError(err error) customerror.Error {
+	if errors.Is(err, transferbus.ErrScheduleNotFound) {
+		return customerror.New(customerror.NotFound, err)
+	}
+	if errors.Is(err, transferbus.ErrAccNotFound) {
+		return customerror.New(customerror.NotFound, err)
+	}
+	if errors.Is(err, transferbus.ErrScheduleClosed) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrCurrencyMismatch) {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+	if errors.Is(err, transferbus.ErrInvalidRecurrence) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrSameAccount) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrNegativeBalance) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrZeroAmount) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrTxAborted) {
+		return customerror.New(customerror.Aborted, err)
+	}
+	return customerror.New(customerror.Internal, err)
+}
+
+func (a *App) createWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req WebhookRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	sub, err := a.transferbus.CreateWebhookSubscription(ctx, toBusNewWebhookSubscription(req))
+	if err != nil {
+		return webhookError(err)
+	}
+
+	// the secret is shown once, so the subscriber can verify its deliveries
+	resp := fromBusWebhookSubscription(sub)
+	resp.Secret = sub.Secret
+
+	w.Header().Set("Location", "/webhooks/"+sub.SubscriptionID.String())
+	return web.Respond(ctx, w, resp, http.StatusCreated)
+}
+
+func (a *App) queryWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	subscriptionID, err := uuid.Parse(r.PathValue("subscription_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid subscription id"))
+	}
+
+	sub, err := a.transferbus.QueryWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		return webhookError(err)
+	}
+
+	return web.Respond(ctx, w, fromBusWebhookSubscription(sub), http.StatusOK)
+}
+
+func (a *App) queryAccountWebhooks(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accID, err := strconv.ParseInt(r.PathValue("account_id"), 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	subs, err := a.transferbus.QueryAccountWebhookSubscriptions(ctx, accID)
+	if err != nil {
+		return webhookError(err)
+	}
+
+	return web.Respond(ctx, w, fromBusWebhookSubscriptions(subs), http.StatusOK)
+}
+
+func (a *App) updateWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	subscriptionID, err := uuid.Parse(r.PathValue("subscription_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid subscription id"))
+	}
+
+	var req WebhookUpdateRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	sub, err := a.transferbus.UpdateWebhookSubscription(ctx, subscriptionID, toBusUpdateWebhookSubscription(req))
+	if err != nil {
+		return webhookError(err)
+	}
+
+	return web.Respond(ctx, w, fromBusWebhookSubscription(sub), http.StatusOK)
+}
+
+func (a *App) deleteWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	subscriptionID, err := uuid.Parse(r.PathValue("subscription_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid subscription id"))
+	}
+
+	if err := a.transferbus.DeleteWebhookSubscription(ctx, subscriptionID); err != nil {
+		return webhookError(err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+func (a *App) queryWebhookDeliveries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	subscriptionID, err := uuid.Parse(r.PathValue("subscription_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid subscription id"))
+	}
+
+	filter, err := parseDeliveryFilter(r, subscriptionID)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	deliveries, err := a.transferbus.QueryWebhookDeliveries(ctx, filter)
+	if err != nil {
+		return webhookError(err)
+	}
+
+	return web.Respond(ctx, w, fromBusWebhookDeliveries(deliveries, filter.Limit), http.StatusOK)
+}
+
+// webhookError maps the errors of a webhook subscription to the error returned
+// to the client.
+func webhookError(err error) customerror.Error {
+	if errors.Is(err, transferbus.ErrWebhookNotFound) {
+		return customerror.New(customerror.NotFound, err)
+	}
+	if errors.Is(err, transferbus.ErrAccNotFound) {
+		return customerror.New(customerror.NotFound, err)
+	}
+	if errors.Is(err, transferbus.ErrInvalidWebhookURL) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrInvalidWebhookStatus) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrWebhookAddress) {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+	if errors.Is(err, transferbus.ErrTxAborted) {
+		return customerror.New(customerror.Aborted, err)
+	}
+	return customerror.New(customerror.Internal, err)
+}
+
+func (a *App) queryTransfer(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	transferID, err := uuid.Parse(r.PathValue("transfer_id"))
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid transfer id"))
+	}
+
+	transfer, err := a.transferbus.QueryTransfer(ctx, transferID)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrTransferNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to query transfer: transferId[%s]: %s", transferID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusTransfer(transfer), http.StatusOK)
+}
+
+func (a *App) queryAccountTransactions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accID, err := strconv.ParseInt(r.PathValue("account_id"), 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	filter, err := parseTransactionFilter(r, accID)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	legs, err := a.transferbus.QueryAccountTransactions(ctx, filter)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to query transactions: accId[%d]: %s", accID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusTransactionHistory(legs, filter.Limit), http.StatusOK)
+}
+
+// streamAccountEvents streams the balance changes of an account as server-sent
+// events. A new stream starts with the latest change, so the current balance
+// is sent first, while a resumed stream sends every change it missed.
+func (a *App) streamAccountEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accID, err := strconv.ParseInt(r.PathValue("account_id"), 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	lastEventID := r.Header.Get(lastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var afterEventID int64
+	if lastEventID != "" {
+		afterEventID, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || afterEventID < 0 {
+			return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid last event id"))
+		}
+	}
+
+	// subscribe before reading any event so none committed meanwhile is missed
+	notify, unsubscribe := a.transferbus.SubscribeAccountEvents(accID)
+	defer unsubscribe()
+
+	if _, err := a.transferbus.GetBalance(ctx, accID); err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to get balance: accId[%d]: %s", accID, err)
+	}
+
+	if lastEventID == "" {
+		last, err := a.transferbus.QueryLastAccountEvent(ctx, accID)
+		switch {
+		case err == nil:
+			afterEventID = last.EventID - 1
+		case !errors.Is(err, transferbus.ErrEventNotFound):
+			return customerror.Newf(customerror.Internal, "failed to query events: accId[%d]: %s", accID, err)
+		}
+	}
+
+	stream, err := web.NewStream(ctx, w)
+	if err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatEvery)
+	defer heartbeat.Stop()
+
+	// the response has started, so errors end the stream instead of being
+	// returned, and clients reconnect with the last event id they received
+	for {
+		events, err := a.transferbus.QueryAccountEvents(ctx, accID, afterEventID, streamBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				stream.Send("", "error", customerror.Newf(customerror.Internal, "failed to query events: accId[%d]", accID))
+			}
+			return nil
+		}
+
+		for _, event := range events {
+			id := strconv.FormatInt(event.EventID, 10)
+			if err := stream.Send(id, string(event.Type), fromBusEvent(event)); err != nil {
+				return nil
+			}
+			afterEventID = event.EventID
+		}
+
+		if len(events) == streamBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		case <-heartbeat.C:
+			if err := stream.Comment("heartbeat"); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+func (a *App) setOverdraftLimit(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accID, err := strconv.ParseInt(r.PathValue("account_id"), 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	var req OverdraftLimitRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	limit, err := toBusOverdraftLimit(req)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	account, err := a.transferbus.SetOverdraftLimit(ctx, accID, limit)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrNegativeOverdraft) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrOverdraftInUse) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrOverdraftHeld) {
+			return customerror.New(customerror.FailedPrecondition, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to set overdraft limit: accId[%d]: %s", accID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusAccBalance(account), http.StatusOK)
+}
+
+func (a *App) setAccountLimits(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accID, err := strconv.ParseInt(r.PathValue("account_id"), 10, 0)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, fmt.Errorf("invalid account id"))
+	}
+
+	var req TransferLimitsRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	limits, err := toBusTransferLimits(req)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	limits, err = a.transferbus.SetAccountLimits(ctx, accID, limits)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrAccNotFound) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrNegativeLimit) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to set account limits: accId[%d]: %s", accID, err)
+	}
+
+	return web.Respond(ctx, w, fromBusTransferLimits(limits), http.StatusOK)
+}
+
+func (a *App) setAccountTypeLimits(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	accountType := transferbus.AccountType(r.PathValue("account_type"))
+
+	var req AccountTypeLimitsRequest
+	if err := web.Decode(r, &req); err != nil {
+		return customerror.New(customerror.FailedPrecondition, err)
+	}
+
+	cur, limits, err := toBusAccountTypeLimits(req)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	limits, err = a.transferbus.SetAccountTypeLimits(ctx, accountType, cur, limits)
+	if err != nil {
+		if errors.Is(err, transferbus.ErrUnknownAccountType) {
+			return customerror.New(customerror.NotFound, err)
+		}
+		if errors.Is(err, transferbus.ErrNegativeLimit) {
+			return customerror.New(customerror.InvalidArgument, err)
+		}
+		if errors.Is(err, transferbus.ErrTxAborted) {
+			return customerror.New(customerror.Aborted, err)
+		}
+		return customerror.Newf(customerror.Internal, "failed to set account type limits: type[%s] currency[%s]: %s", accountType, cur, err)
+	}
+
+	return web.Respond(ctx, w, fromBusTransferLimits(limits), http.StatusOK)
+}
+
+func (a *App) reconcile(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	cfg, err := parseReconcileConfig(r)
+	if err != nil {
+		return customerror.New(customerror.InvalidArgument, err)
+	}
+
+	result, err := a.transferbus.Reconcile(ctx, cfg)
+	if err != nil {
+		return customerror.Newf(customerror.Internal, "failed to reconcile balances: %s", err)
+	}
+
+	return web.Respond(ctx, w, fromBusReconciliation(result), http.StatusOK)
 }
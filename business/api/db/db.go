@@ -55,22 +55,43 @@ func buildConnectionString(config Config) string {
 }
 
 func Migrate(config Config) error {
-	sourceDriver, err := iofs.New(migrationFiles, "migration")
+	m, err := newMigrate(config)
 	if err != nil {
-		return fmt.Errorf("error creating migration source driver: %w", err)
+		return err
 	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("error with DB migration: %w", err)
+	}
+	return nil
+}
 
-	dbURL := buildConnectionString(config)
-	m, err := migrate.NewWithSourceInstance("iofs", sourceDriver, dbURL)
+// MigrateTo migrates the database up or down to the given version, so tests
+// can seed the rows of an older schema before migrating further.
+func MigrateTo(config Config, version uint) error {
+	m, err := newMigrate(config)
 	if err != nil {
-		return fmt.Errorf("error building db migration: %w", err)
+		return err
 	}
-	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+	if err = m.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return fmt.Errorf("error with DB migration: %w", err)
 	}
 	return nil
 }
 
+func newMigrate(config Config) (*migrate.Migrate, error) {
+	sourceDriver, err := iofs.New(migrationFiles, "migration")
+	if err != nil {
+		return nil, fmt.Errorf("error creating migration source driver: %w", err)
+	}
+
+	dbURL := buildConnectionString(config)
+	m, err := migrate.NewWithSourceInstance("iofs", sourceDriver, dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("error building db migration: %w", err)
+	}
+	return m, nil
+}
+
 func InitDatabase(ctx context.Context, config Config, newDBName string) error {
 	connString := buildConnectionString(config)
 	// 2. Establish a single, temporary connection
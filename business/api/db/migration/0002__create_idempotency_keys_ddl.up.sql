@@ -0,0 +1,6 @@
+CREATE TABLE
+    IF NOT EXISTS idempotency_keys (
+        idempotency_key TEXT PRIMARY KEY,
+        request_hash TEXT NOT NULL,
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
@@ -0,0 +1,15 @@
+-- Every row of transactions is one leg of a transfer. Existing rows each get a
+-- transfer of their own since they were recorded without a link.
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS transaction_id BIGSERIAL PRIMARY KEY,
+ADD COLUMN IF NOT EXISTS transfer_id UUID NOT NULL DEFAULT gen_random_uuid ();
+
+ALTER TABLE transactions
+ALTER COLUMN transfer_id
+DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS transactions_transfer_id_idx ON transactions (transfer_id);
+
+-- Keys recorded before transfers had an identifier have no transfer to return.
+ALTER TABLE idempotency_keys
+ADD COLUMN IF NOT EXISTS transfer_id UUID;
@@ -0,0 +1,2 @@
+-- Supports paging through the history of an account from the latest transaction.
+CREATE INDEX IF NOT EXISTS transactions_account_id_transaction_id_idx ON transactions (account_id, transaction_id DESC);
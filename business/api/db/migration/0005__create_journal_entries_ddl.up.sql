@@ -0,0 +1,198 @@
+-- System accounts use negative ids, which clients can never pick. They are
+-- allowed to go negative since they fund the customer accounts.
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS balance_must_be_non_negative,
+ADD CONSTRAINT balance_must_be_non_negative CHECK (
+    account_id < 0
+    OR balance >= 0
+);
+
+-- The equity account funds the initial balance of every account.
+INSERT INTO
+    accounts (account_id, balance)
+VALUES
+    (-1, 0) ON CONFLICT (account_id) DO NOTHING;
+
+-- Every transfer is a journal entry whose postings are the rows of transactions.
+CREATE TABLE
+    IF NOT EXISTS journal_entries (
+        transfer_id UUID PRIMARY KEY,
+        entry_type TEXT NOT NULL,
+        source_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        destination_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        amount NUMERIC(19, 5) NOT NULL,
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+-- Transfers recorded before transactions had a transfer_id were given one
+-- transfer per leg. Their debit was always written right before the credit of
+-- the same amount, so the n-th unlinked debit of an amount is paired with the
+-- n-th unlinked credit of that amount. Transfers of the same amount posted
+-- concurrently may swap credits, which leaves every balance unchanged. The
+-- first row of every account is its initial balance and is never paired.
+WITH
+    unlinked AS (
+        SELECT
+            t.transaction_id,
+            t.transfer_id,
+            t.account_id,
+            t.amount
+        FROM
+            transactions t
+        WHERE
+            NOT EXISTS (
+                SELECT
+                    1
+                FROM
+                    transactions o
+                WHERE
+                    o.transfer_id = t.transfer_id
+                    AND o.transaction_id <> t.transaction_id
+            )
+            AND t.transaction_id <> (
+                SELECT
+                    MIN(f.transaction_id)
+                FROM
+                    transactions f
+                WHERE
+                    f.account_id = t.account_id
+            )
+    ),
+    debits AS (
+        SELECT
+            transaction_id,
+            transfer_id,
+            account_id,
+            - amount AS amount,
+            ROW_NUMBER() OVER (
+                PARTITION BY
+                    amount
+                ORDER BY
+                    transaction_id
+            ) AS n
+        FROM
+            unlinked
+        WHERE
+            amount < 0
+    ),
+    credits AS (
+        SELECT
+            transaction_id,
+            account_id,
+            amount,
+            ROW_NUMBER() OVER (
+                PARTITION BY
+                    amount
+                ORDER BY
+                    transaction_id
+            ) AS n
+        FROM
+            unlinked
+        WHERE
+            amount > 0
+    )
+UPDATE transactions t
+SET
+    transfer_id = d.transfer_id
+FROM
+    debits d
+    JOIN credits c ON c.amount = d.amount
+    AND c.n = d.n
+WHERE
+    t.transaction_id = c.transaction_id
+    AND c.transaction_id > d.transaction_id
+    AND c.account_id <> d.account_id;
+
+-- Initial balances are the first row of their account and transfers a debit
+-- paired with a credit. Legs left unpaired are recorded as legacy entries
+-- against the equity account, so the ledger still balances.
+INSERT INTO
+    journal_entries (
+        transfer_id,
+        entry_type,
+        source_account_id,
+        destination_account_id,
+        amount,
+        created_date
+    )
+SELECT
+    t.transfer_id,
+    CASE
+        WHEN COUNT(*) = 2 THEN 'transfer'
+        WHEN MIN(t.transaction_id) = (
+            SELECT
+                MIN(f.transaction_id)
+            FROM
+                transactions f
+            WHERE
+                f.account_id = MIN(t.account_id)
+        ) THEN 'opening_balance'
+        ELSE 'legacy'
+    END,
+    CASE
+        WHEN COUNT(*) = 2 THEN (ARRAY_AGG (t.account_id ORDER BY t.amount)) [1]
+        WHEN MIN(t.amount) < 0 THEN MIN(t.account_id)
+        ELSE -1
+    END,
+    CASE
+        WHEN COUNT(*) = 2 THEN (ARRAY_AGG (t.account_id ORDER BY t.amount DESC)) [1]
+        WHEN MIN(t.amount) < 0 THEN -1
+        ELSE MIN(t.account_id)
+    END,
+    MAX(ABS(t.amount)),
+    MIN(t.created_date)
+FROM
+    transactions t
+GROUP BY
+    t.transfer_id ON CONFLICT (transfer_id) DO NOTHING;
+
+-- Balance the initial balances and legacy entries against the equity account.
+INSERT INTO
+    transactions (transfer_id, account_id, amount, created_date)
+SELECT
+    transfer_id,
+    -1,
+    - SUM(amount),
+    MIN(created_date)
+FROM
+    transactions
+GROUP BY
+    transfer_id
+HAVING
+    SUM(amount) <> 0;
+
+UPDATE accounts
+SET
+    balance = (
+        SELECT
+            COALESCE(SUM(amount), 0)
+        FROM
+            transactions
+        WHERE
+            account_id = -1
+    )
+WHERE
+    account_id = -1;
+
+ALTER TABLE transactions
+DROP CONSTRAINT IF EXISTS transactions_transfer_id_fkey,
+ADD CONSTRAINT transactions_transfer_id_fkey FOREIGN KEY (transfer_id) REFERENCES journal_entries (transfer_id) ON DELETE RESTRICT;
+
+-- The postings of a journal entry must sum to zero once its transaction commits.
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced () RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM transactions WHERE transfer_id = NEW.transfer_id) <> 0 THEN
+        RAISE EXCEPTION 'journal entry % does not balance', NEW.transfer_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS journal_entry_must_balance ON transactions;
+
+CREATE CONSTRAINT TRIGGER journal_entry_must_balance
+AFTER INSERT
+OR
+UPDATE ON transactions DEFERRABLE INITIALLY DEFERRED FOR EACH ROW
+EXECUTE FUNCTION check_journal_entry_balanced ();
@@ -0,0 +1,84 @@
+-- Every account holds a single ISO-4217 currency. Existing accounts were all
+-- denominated in USD.
+ALTER TABLE accounts
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD',
+DROP CONSTRAINT IF EXISTS currency_must_be_iso_4217,
+ADD CONSTRAINT currency_must_be_iso_4217 CHECK (currency ~ '^[A-Z]{3}$');
+
+-- The amount of a journal entry is denominated in the currency of its source.
+ALTER TABLE journal_entries
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE journal_entries
+ALTER COLUMN currency
+DROP DEFAULT;
+
+-- System accounts are allocated downwards from the equity account -1.
+CREATE SEQUENCE IF NOT EXISTS system_account_id_seq INCREMENT BY -1 MAXVALUE -2 START
+WITH
+    -2;
+
+-- Every currency has its own set of system accounts, looked up by purpose.
+CREATE TABLE
+    IF NOT EXISTS system_accounts (
+        purpose TEXT NOT NULL,
+        currency CHAR(3) NOT NULL,
+        account_id BIGINT NOT NULL UNIQUE REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        PRIMARY KEY (purpose, currency)
+    );
+
+INSERT INTO
+    system_accounts (purpose, currency, account_id)
+VALUES
+    ('equity', 'USD', -1) ON CONFLICT (purpose, currency) DO NOTHING;
+
+WITH
+    created AS (
+        INSERT INTO
+            accounts (account_id, currency, balance)
+        SELECT
+            nextval('system_account_id_seq'),
+            c.code,
+            0
+        FROM
+            UNNEST(ARRAY['EUR', 'GBP', 'IDR', 'JPY', 'KWD', 'SGD']) AS c (code)
+        WHERE
+            NOT EXISTS (
+                SELECT
+                    1
+                FROM
+                    system_accounts s
+                WHERE
+                    s.purpose = 'equity'
+                    AND s.currency = c.code
+            )
+        RETURNING
+            account_id,
+            currency
+    )
+INSERT INTO
+    system_accounts (purpose, currency, account_id)
+SELECT
+    'equity',
+    currency,
+    account_id
+FROM
+    created;
+
+-- Postings of a journal entry must sum to zero in every currency it touches.
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced () RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM transactions t
+        JOIN accounts a ON a.account_id = t.account_id
+        WHERE t.transfer_id = NEW.transfer_id
+        GROUP BY a.currency
+        HAVING SUM(t.amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'journal entry % does not balance', NEW.transfer_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,90 @@
+-- A quote locks the rate of a cross-currency transfer between two accounts
+-- until it expires. It is used by at most one transfer.
+CREATE TABLE
+    IF NOT EXISTS fx_quotes (
+        quote_id UUID PRIMARY KEY,
+        source_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        destination_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        source_currency CHAR(3) NOT NULL,
+        destination_currency CHAR(3) NOT NULL,
+        source_amount NUMERIC(19, 5) NOT NULL,
+        destination_amount NUMERIC(19, 5) NOT NULL,
+        rate NUMERIC(24, 10) NOT NULL,
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        expires_date TIMESTAMPTZ NOT NULL,
+        transfer_id UUID
+    );
+
+-- Every posting is denominated in the currency of its account.
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS currency CHAR(3);
+
+UPDATE transactions t
+SET
+    currency = a.currency
+FROM
+    accounts a
+WHERE
+    a.account_id = t.account_id
+    AND t.currency IS NULL;
+
+ALTER TABLE transactions
+ALTER COLUMN currency
+SET NOT NULL;
+
+-- Journal entries record the amount credited to the destination and the rate
+-- it was converted at, which is 1 unless the transfer used a quote.
+ALTER TABLE journal_entries
+ADD COLUMN IF NOT EXISTS destination_amount NUMERIC(19, 5),
+ADD COLUMN IF NOT EXISTS destination_currency CHAR(3),
+ADD COLUMN IF NOT EXISTS rate NUMERIC(24, 10) NOT NULL DEFAULT 1,
+ADD COLUMN IF NOT EXISTS quote_id UUID REFERENCES fx_quotes (quote_id) ON DELETE RESTRICT;
+
+UPDATE journal_entries
+SET
+    destination_amount = amount,
+    destination_currency = currency
+WHERE
+    destination_amount IS NULL;
+
+ALTER TABLE journal_entries
+ALTER COLUMN destination_amount
+SET NOT NULL,
+ALTER COLUMN destination_currency
+SET NOT NULL,
+ALTER COLUMN rate
+DROP DEFAULT;
+
+-- Cross-currency transfers pass through the fx account of each currency.
+WITH
+    created AS (
+        INSERT INTO
+            accounts (account_id, currency, balance)
+        SELECT
+            nextval('system_account_id_seq'),
+            c.code,
+            0
+        FROM
+            UNNEST(ARRAY['EUR', 'GBP', 'IDR', 'JPY', 'KWD', 'SGD', 'USD']) AS c (code)
+        WHERE
+            NOT EXISTS (
+                SELECT
+                    1
+                FROM
+                    system_accounts s
+                WHERE
+                    s.purpose = 'fx'
+                    AND s.currency = c.code
+            )
+        RETURNING
+            account_id,
+            currency
+    )
+INSERT INTO
+    system_accounts (purpose, currency, account_id)
+SELECT
+    'fx',
+    currency,
+    account_id
+FROM
+    created;
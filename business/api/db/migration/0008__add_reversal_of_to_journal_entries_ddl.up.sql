@@ -0,0 +1,8 @@
+-- A reversal is a journal entry sending back all or part of an earlier
+-- transfer, which it references.
+ALTER TABLE journal_entries
+ADD COLUMN IF NOT EXISTS reversal_of UUID REFERENCES journal_entries (transfer_id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS journal_entries_reversal_of_idx ON journal_entries (reversal_of)
+WHERE
+    reversal_of IS NOT NULL;
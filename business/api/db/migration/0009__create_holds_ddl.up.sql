@@ -0,0 +1,29 @@
+-- Funds reserved by authorized holds stay part of the ledger balance but are
+-- no longer available to be debited.
+ALTER TABLE accounts
+ADD COLUMN IF NOT EXISTS held_balance NUMERIC(19, 5) NOT NULL DEFAULT 0;
+
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS held_balance_must_be_non_negative,
+ADD CONSTRAINT held_balance_must_be_non_negative CHECK (held_balance >= 0);
+
+-- A hold reserves an amount on the source account until it is captured into a
+-- transfer, voided or expires.
+CREATE TABLE
+    IF NOT EXISTS holds (
+        hold_id UUID PRIMARY KEY,
+        source_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        destination_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        currency CHAR(3) NOT NULL,
+        amount NUMERIC(19, 5) NOT NULL,
+        captured_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        status TEXT NOT NULL,
+        transfer_id UUID REFERENCES journal_entries (transfer_id) ON DELETE RESTRICT,
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        expires_date TIMESTAMPTZ NOT NULL,
+        last_modified_date TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS holds_expires_date_idx ON holds (expires_date)
+WHERE
+    status = 'authorized';
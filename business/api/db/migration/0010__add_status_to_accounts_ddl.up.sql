@@ -0,0 +1,8 @@
+-- Frozen and closed accounts can neither send nor receive funds. Closed is
+-- final.
+ALTER TABLE accounts
+ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS status_must_be_known,
+ADD CONSTRAINT status_must_be_known CHECK (status IN ('active', 'frozen', 'closed'));
@@ -0,0 +1,23 @@
+-- Descriptive metadata of an account. None of it affects how funds move.
+ALTER TABLE accounts
+ADD COLUMN IF NOT EXISTS owner_ref TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS account_type TEXT NOT NULL DEFAULT 'customer',
+ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS account_type_must_be_known,
+ADD CONSTRAINT account_type_must_be_known CHECK (account_type IN ('customer', 'operational', 'fee', 'suspense'));
+
+-- system accounts are run by the ledger itself
+UPDATE accounts
+SET
+    account_type = 'operational'
+WHERE
+    account_id < 0;
+
+CREATE INDEX IF NOT EXISTS accounts_owner_ref_idx ON accounts (owner_ref);
+
+CREATE INDEX IF NOT EXISTS accounts_account_type_idx ON accounts (account_type);
+
+CREATE INDEX IF NOT EXISTS accounts_labels_idx ON accounts USING GIN (labels);
@@ -0,0 +1,7 @@
+-- Account ids allocated by the service. They start far above the ids clients
+-- have picked so far, and clients may not pick ids from this range.
+CREATE SEQUENCE IF NOT EXISTS accounts_account_id_seq AS BIGINT START WITH 1000000000000 MINVALUE 1000000000000;
+
+-- Accounts clients already opened inside the range must not be handed out
+-- again, so allocation starts after the highest existing id.
+SELECT setval('accounts_account_id_seq', GREATEST(1000000000000, (SELECT MAX(account_id) FROM accounts) + 1), false);
@@ -0,0 +1,15 @@
+-- Accounts may go negative down to their overdraft limit. System accounts keep
+-- being allowed to go negative without one.
+ALTER TABLE accounts
+ADD COLUMN IF NOT EXISTS overdraft_limit NUMERIC(19, 5) NOT NULL DEFAULT 0;
+
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS overdraft_limit_must_be_non_negative,
+ADD CONSTRAINT overdraft_limit_must_be_non_negative CHECK (overdraft_limit >= 0);
+
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS balance_must_be_non_negative,
+ADD CONSTRAINT balance_must_be_non_negative CHECK (
+    account_id < 0
+    OR balance + overdraft_limit >= 0
+);
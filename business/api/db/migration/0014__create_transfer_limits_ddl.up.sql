@@ -0,0 +1,36 @@
+-- Limits on the outbound transfers of an account, in the currency of the
+-- account. A limit of zero is not enforced. Limits set on an account replace
+-- the limits of its account type.
+CREATE TABLE
+    IF NOT EXISTS account_limits (
+        account_id BIGINT PRIMARY KEY REFERENCES accounts (account_id) ON DELETE CASCADE,
+        max_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        daily_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        monthly_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        last_modified_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        CONSTRAINT account_limits_must_be_non_negative CHECK (
+            max_amount >= 0
+            AND daily_amount >= 0
+            AND monthly_amount >= 0
+        )
+    );
+
+CREATE TABLE
+    IF NOT EXISTS account_type_limits (
+        account_type TEXT PRIMARY KEY,
+        max_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        daily_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        monthly_amount NUMERIC(19, 5) NOT NULL DEFAULT 0,
+        last_modified_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        CONSTRAINT account_type_must_be_known CHECK (account_type IN ('customer', 'operational', 'fee', 'suspense')),
+        CONSTRAINT account_type_limits_must_be_non_negative CHECK (
+            max_amount >= 0
+            AND daily_amount >= 0
+            AND monthly_amount >= 0
+        )
+    );
+
+-- rolling sums read the recent debits of an account
+CREATE INDEX IF NOT EXISTS transactions_account_id_created_date_idx ON transactions (account_id, created_date)
+WHERE
+    amount < 0;
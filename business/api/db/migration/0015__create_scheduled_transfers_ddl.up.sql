@@ -0,0 +1,26 @@
+-- A scheduled transfer moves amount from the source to the destination account
+-- at next_run_date. Transfers with a recurrence, a cron expression evaluated in
+-- UTC, are scheduled again after every run until they are cancelled.
+CREATE TABLE
+    IF NOT EXISTS scheduled_transfers (
+        schedule_id UUID PRIMARY KEY,
+        source_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        destination_account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        currency CHAR(3) NOT NULL,
+        amount NUMERIC(19, 5) NOT NULL,
+        recurrence TEXT NOT NULL DEFAULT '',
+        status TEXT NOT NULL,
+        next_run_date TIMESTAMPTZ NOT NULL,
+        run_count INTEGER NOT NULL DEFAULT 0,
+        last_run_date TIMESTAMPTZ,
+        last_transfer_id UUID REFERENCES journal_entries (transfer_id) ON DELETE RESTRICT,
+        last_error TEXT NOT NULL DEFAULT '',
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        last_modified_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        CONSTRAINT scheduled_amount_must_be_positive CHECK (amount > 0)
+    );
+
+-- the scheduler claims the due transfers in the order they are due
+CREATE INDEX IF NOT EXISTS scheduled_transfers_next_run_date_idx ON scheduled_transfers (next_run_date)
+WHERE
+    status = 'scheduled';
@@ -0,0 +1,18 @@
+-- Events are written in the same transaction as the change they describe and
+-- relayed to the publisher afterwards, so no committed change goes unpublished.
+-- account_id is the account the event belongs to, which consumers partition
+-- on to process the events of an account in order.
+CREATE TABLE
+    IF NOT EXISTS outbox_events (
+        event_id BIGSERIAL PRIMARY KEY,
+        event_type TEXT NOT NULL,
+        account_id BIGINT NOT NULL,
+        payload JSONB NOT NULL,
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        published_date TIMESTAMPTZ
+    );
+
+-- the relay reads the events left to publish in the order they were written
+CREATE INDEX IF NOT EXISTS outbox_events_unpublished_idx ON outbox_events (event_id)
+WHERE
+    published_date IS NULL;
@@ -0,0 +1,39 @@
+-- A webhook subscription sends the events of an account to url, signed with
+-- secret. Paused subscriptions keep their pending deliveries until resumed.
+CREATE TABLE
+    IF NOT EXISTS webhook_subscriptions (
+        subscription_id UUID PRIMARY KEY,
+        account_id BIGINT NOT NULL REFERENCES accounts (account_id) ON DELETE RESTRICT,
+        url TEXT NOT NULL,
+        secret TEXT NOT NULL,
+        status TEXT NOT NULL,
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW (),
+        last_modified_date TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_account_id_idx ON webhook_subscriptions (account_id);
+
+-- A webhook delivery sends an outbox event to a subscription. Deliveries are
+-- written together with their event, retried with backoff until next_attempt_date
+-- and dead once every attempt has failed.
+CREATE TABLE
+    IF NOT EXISTS webhook_deliveries (
+        delivery_id BIGSERIAL PRIMARY KEY,
+        subscription_id UUID NOT NULL REFERENCES webhook_subscriptions (subscription_id) ON DELETE CASCADE,
+        event_id BIGINT NOT NULL REFERENCES outbox_events (event_id) ON DELETE RESTRICT,
+        status TEXT NOT NULL,
+        attempts INTEGER NOT NULL DEFAULT 0,
+        next_attempt_date TIMESTAMPTZ NOT NULL,
+        last_attempt_date TIMESTAMPTZ,
+        last_status_code INTEGER NOT NULL DEFAULT 0,
+        last_error TEXT NOT NULL DEFAULT '',
+        created_date TIMESTAMPTZ NOT NULL DEFAULT NOW ()
+    );
+
+-- the worker claims the pending deliveries in the order they are due
+CREATE INDEX IF NOT EXISTS webhook_deliveries_next_attempt_date_idx ON webhook_deliveries (next_attempt_date)
+WHERE
+    status = 'pending';
+
+-- the delivery log of a subscription is read newest first
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id, delivery_id);
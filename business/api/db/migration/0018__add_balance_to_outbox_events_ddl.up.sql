@@ -0,0 +1,7 @@
+-- balance is the balance of the account right after the change the event
+-- describes, so streams and consumers do not have to read it back.
+ALTER TABLE outbox_events
+ADD COLUMN IF NOT EXISTS balance NUMERIC(19, 5) NOT NULL DEFAULT 0;
+
+-- account event streams read the events of an account in order
+CREATE INDEX IF NOT EXISTS outbox_events_account_id_idx ON outbox_events (account_id, event_id);
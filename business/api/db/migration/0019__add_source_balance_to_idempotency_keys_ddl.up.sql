@@ -0,0 +1,5 @@
+-- source_balance is the balance the source account was left with by the
+-- transfer of the key, so a replay returns the response it was first given.
+-- Keys recorded before it was kept have none.
+ALTER TABLE idempotency_keys
+ADD COLUMN IF NOT EXISTS source_balance NUMERIC(19, 5);
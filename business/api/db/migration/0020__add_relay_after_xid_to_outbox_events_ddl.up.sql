@@ -0,0 +1,7 @@
+-- Event ids are taken before the writing transaction commits, so an event can
+-- commit after events with a higher id. relay_after_xid is the first
+-- transaction id not yet assigned when the event was written. Once every
+-- transaction below it has finished, no event with a lower id can still
+-- commit, and the relay can publish the event without leaving one behind.
+ALTER TABLE outbox_events
+ADD COLUMN IF NOT EXISTS relay_after_xid BIGINT NOT NULL DEFAULT (pg_snapshot_xmax (pg_current_snapshot ())::TEXT::BIGINT);
@@ -0,0 +1,14 @@
+-- Limits are amounts in the currency of the account, so the limits of an
+-- account type are set per currency. Limits set before currencies were kept
+-- apart are taken to be in USD, the default currency.
+ALTER TABLE account_type_limits
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD',
+ADD CONSTRAINT account_type_limits_currency_must_be_iso_4217 CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE account_type_limits
+ALTER COLUMN currency
+DROP DEFAULT;
+
+ALTER TABLE account_type_limits
+DROP CONSTRAINT account_type_limits_pkey,
+ADD PRIMARY KEY (account_type, currency);
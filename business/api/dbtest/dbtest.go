@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/danipurwadi/internal-transfer-system/business/api/db"
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
 	"github.com/danipurwadi/internal-transfer-system/foundation/docker"
@@ -52,8 +53,9 @@ type BusDomain struct {
 }
 
 func newBusDomains(db *pgxpool.Pool, log *logger.Logger) BusDomain {
-	dbClient := transferdb.NewTxQueries(db)
-	transferBus := transferbus.New(dbClient, log)
+	dbClient := transferdb.NewTxQueries(db, transferdb.DefaultRetryConfig)
+	fxBus := fxbus.New(fxbus.TestRates(), time.Minute)
+	transferBus := transferbus.New(dbClient, fxBus, transferbus.DefaultIsolation, log)
 
 	return BusDomain{
 		TransferBus: transferBus,
@@ -68,12 +70,31 @@ type Database struct {
 	Log       *logger.Logger
 	BusDomain BusDomain
 	Teardown  func()
+
+	config db.Config
 }
 
 // NewDatabase creates a test database inside a Docker container. It creates the
 // required table structure but the database is otherwise empty. It returns
 // the database to use as well as a function to call at the end of the test.
 func NewDatabase(t *testing.T, c *docker.Container, testName string) *Database {
+	return newDatabase(t, c, testName, db.Migrate)
+}
+
+// NewDatabaseAt is like NewDatabase but only migrates the table structure up to
+// the given version. Tests seed the rows of that schema and then call Migrate.
+func NewDatabaseAt(t *testing.T, c *docker.Container, testName string, version uint) *Database {
+	return newDatabase(t, c, testName, func(config db.Config) error {
+		return db.MigrateTo(config, version)
+	})
+}
+
+// Migrate migrates the database to the latest version.
+func (d *Database) Migrate() error {
+	return db.Migrate(d.config)
+}
+
+func newDatabase(t *testing.T, c *docker.Container, testName string, migrate func(db.Config) error) *Database {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -111,7 +132,9 @@ func NewDatabase(t *testing.T, c *docker.Container, testName string) *Database {
 	}
 
 	testDB := db.New(dbConfig)
-	db.Migrate(dbConfig)
+	if err := migrate(dbConfig); err != nil {
+		t.Fatalf("migrating database %s: %v", dbName, err)
+	}
 
 	// -------------------------------------------------------------------------
 
@@ -137,6 +160,7 @@ func NewDatabase(t *testing.T, c *docker.Container, testName string) *Database {
 		Log:       log,
 		BusDomain: newBusDomains(testDB, log),
 		Teardown:  teardown,
+		config:    dbConfig,
 	}
 }
 
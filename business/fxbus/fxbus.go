@@ -0,0 +1,74 @@
+// Package fxbus provides foreign exchange rates and prices quotes for transfers
+// between accounts holding different currencies.
+package fxbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/shopspring/decimal"
+)
+
+// RateScale is the number of decimal places rates are kept with.
+const RateScale = 10
+
+var (
+	ErrRateNotFound  = errors.New("exchange rate not found")
+	ErrSameCurrency  = errors.New("source and destination currencies must differ")
+	ErrInvalidAmount = errors.New("quote amount must be positive")
+)
+
+// RateProvider returns the number of units of the to currency one unit of the
+// from currency buys.
+type RateProvider interface {
+	Rate(ctx context.Context, from currency.Currency, to currency.Currency) (decimal.Decimal, error)
+}
+
+// Bus prices quotes using the rates of its provider.
+type Bus struct {
+	rates RateProvider
+	ttl   time.Duration
+}
+
+// New constructs a Bus whose quotes are valid for the given ttl.
+func New(rates RateProvider, ttl time.Duration) *Bus {
+	return &Bus{
+		rates: rates,
+		ttl:   ttl,
+	}
+}
+
+// Price converts the amount from one currency to another at the current rate.
+// The source amount is rounded to the precision of its currency before it is
+// converted and the destination amount is rounded to the precision of the
+// destination currency.
+func (b *Bus) Price(ctx context.Context, from currency.Currency, to currency.Currency, amount decimal.Decimal) (Price, error) {
+	if from.Equal(to) {
+		return Price{}, ErrSameCurrency
+	}
+
+	amount = from.Round(amount)
+	if !amount.IsPositive() {
+		return Price{}, ErrInvalidAmount
+	}
+
+	rate, err := b.rates.Rate(ctx, from, to)
+	if err != nil {
+		return Price{}, fmt.Errorf("rate: %s/%s: %w", from, to, err)
+	}
+
+	now := time.Now()
+
+	return Price{
+		SourceCurrency:      from,
+		DestinationCurrency: to,
+		SourceAmount:        amount,
+		DestinationAmount:   to.Round(amount.Mul(rate)),
+		Rate:                rate,
+		CreatedDate:         now,
+		ExpiresDate:         now.Add(b.ttl),
+	}, nil
+}
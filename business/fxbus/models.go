@@ -0,0 +1,20 @@
+package fxbus
+
+import (
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/shopspring/decimal"
+)
+
+// Price is the conversion of an amount between two currencies, valid until
+// ExpiresDate.
+type Price struct {
+	SourceCurrency      currency.Currency
+	DestinationCurrency currency.Currency
+	SourceAmount        decimal.Decimal
+	DestinationAmount   decimal.Decimal
+	Rate                decimal.Decimal
+	CreatedDate         time.Time
+	ExpiresDate         time.Time
+}
@@ -0,0 +1,141 @@
+package fxbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/shopspring/decimal"
+)
+
+// StaticRates provides fixed rates, each expressed as the number of units of a
+// currency one unit of the base currency buys. Cross rates are derived through
+// the base currency.
+type StaticRates struct {
+	base  currency.Currency
+	rates map[currency.Currency]decimal.Decimal
+}
+
+var _ RateProvider = (*StaticRates)(nil)
+
+// NewStaticRates constructs a StaticRates quoting every currency against base.
+func NewStaticRates(base currency.Currency, rates map[currency.Currency]decimal.Decimal) *StaticRates {
+	rs := make(map[currency.Currency]decimal.Decimal, len(rates)+1)
+	for cur, rate := range rates {
+		rs[cur] = rate
+	}
+	rs[base] = decimal.NewFromInt(1)
+
+	return &StaticRates{
+		base:  base,
+		rates: rs,
+	}
+}
+
+// Rate implements the RateProvider interface.
+func (s *StaticRates) Rate(ctx context.Context, from currency.Currency, to currency.Currency) (decimal.Decimal, error) {
+	fromRate, exists := s.rates[from]
+	if !exists || !fromRate.IsPositive() {
+		return decimal.Decimal{}, ErrRateNotFound
+	}
+
+	toRate, exists := s.rates[to]
+	if !exists || !toRate.IsPositive() {
+		return decimal.Decimal{}, ErrRateNotFound
+	}
+
+	return toRate.DivRound(fromRate, RateScale), nil
+}
+
+// =============================================================================
+
+// ratesFile is the format of the file read by FileRates.
+//
+//	{"base": "USD", "rates": {"EUR": "0.92", "JPY": "151.3"}}
+type ratesFile struct {
+	Base  string                     `json:"base"`
+	Rates map[string]decimal.Decimal `json:"rates"`
+}
+
+// FileRates provides the rates stored in a JSON file, which is read again
+// whenever it is modified. It is meant for local use.
+type FileRates struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	rates   *StaticRates
+}
+
+var _ RateProvider = (*FileRates)(nil)
+
+// NewFileRates constructs a FileRates reading the file at path.
+func NewFileRates(path string) (*FileRates, error) {
+	f := FileRates{
+		path: path,
+	}
+
+	if _, err := f.load(); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// Rate implements the RateProvider interface.
+func (f *FileRates) Rate(ctx context.Context, from currency.Currency, to currency.Currency) (decimal.Decimal, error) {
+	rates, err := f.load()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return rates.Rate(ctx, from, to)
+}
+
+// load returns the rates of the file, reading it again when it was modified
+// since it was last read.
+func (f *FileRates) load() (*StaticRates, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("stat rates file: %w", err)
+	}
+	if f.rates != nil && info.ModTime().Equal(f.modTime) {
+		return f.rates, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("read rates file: %w", err)
+	}
+
+	var rf ratesFile
+	if err := json.Unmarshal(data, &rf); err != nil {
+		return nil, fmt.Errorf("decode rates file: %w", err)
+	}
+
+	base, err := currency.Parse(rf.Base)
+	if err != nil {
+		return nil, fmt.Errorf("parse base currency: %w", err)
+	}
+
+	rates := make(map[currency.Currency]decimal.Decimal, len(rf.Rates))
+	for code, rate := range rf.Rates {
+		cur, err := currency.Parse(code)
+		if err != nil {
+			return nil, fmt.Errorf("parse currency: %w", err)
+		}
+		rates[cur] = rate
+	}
+
+	f.rates = NewStaticRates(base, rates)
+	f.modTime = info.ModTime()
+
+	return f.rates, nil
+}
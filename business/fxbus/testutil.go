@@ -0,0 +1,19 @@
+package fxbus
+
+import (
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/shopspring/decimal"
+)
+
+// TestRates is a helper method for testing. It quotes every currency against
+// USD at fixed rates.
+func TestRates() *StaticRates {
+	return NewStaticRates(currency.USD, map[currency.Currency]decimal.Decimal{
+		currency.EUR: decimal.RequireFromString("0.9"),
+		currency.GBP: decimal.RequireFromString("0.8"),
+		currency.IDR: decimal.RequireFromString("16000"),
+		currency.JPY: decimal.RequireFromString("150"),
+		currency.KWD: decimal.RequireFromString("0.3"),
+		currency.SGD: decimal.RequireFromString("1.35"),
+	})
+}
@@ -0,0 +1,286 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func accountLifecycle(db *dbtest.Database) []unittest.Table {
+	// newAccounts opens accounts with 100 each
+	newAccounts := func(ctx context.Context, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	transfer := func(ctx context.Context, srcID int64, dstID int64) error {
+		_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(10),
+		})
+		return err
+	}
+
+	type accountResult struct {
+		Status  transferbus.AccountStatus
+		Balance decimal.Decimal
+	}
+
+	cmpAccount := func(got any, exp any) string {
+		gotResp, exists := got.(transferbus.Account)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(accountResult{Status: gotResp.Status, Balance: gotResp.Balance}, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "freeze",
+			ExpResp: accountResult{Status: transferbus.AccountStatusFrozen, Balance: decimal.NewFromInt(100)},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12000); err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.FreezeAccount(ctx, 12000)
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpAccount,
+		},
+		{
+			Name:    "frozensource",
+			ExpResp: transferbus.ErrAccFrozen,
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12001, 12002); err != nil {
+					return err
+				}
+				if _, err := db.BusDomain.TransferBus.FreezeAccount(ctx, 12001); err != nil {
+					return err
+				}
+
+				return transfer(ctx, 12001, 12002)
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "frozendestination",
+			ExpResp: transferbus.ErrAccFrozen,
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12003, 12004); err != nil {
+					return err
+				}
+				if _, err := db.BusDomain.TransferBus.FreezeAccount(ctx, 12004); err != nil {
+					return err
+				}
+
+				return transfer(ctx, 12003, 12004)
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "unfreeze",
+			ExpResp: accountResult{Status: transferbus.AccountStatusActive, Balance: decimal.NewFromInt(90)},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12005, 12006); err != nil {
+					return err
+				}
+				if _, err := db.BusDomain.TransferBus.FreezeAccount(ctx, 12005); err != nil {
+					return err
+				}
+				if _, err := db.BusDomain.TransferBus.UnfreezeAccount(ctx, 12005); err != nil {
+					return err
+				}
+				if err := transfer(ctx, 12005, 12006); err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.GetBalance(ctx, 12005)
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpAccount,
+		},
+		{
+			Name:    "closenotempty",
+			ExpResp: transferbus.ErrAccNotEmpty,
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12007); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.CloseAccount(ctx, transferbus.AccountClosure{AccountID: 12007})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "closewithsweep",
+			ExpResp: []accountResult{{Status: transferbus.AccountStatusClosed, Balance: decimal.Zero}, {Status: transferbus.AccountStatusActive, Balance: decimal.NewFromInt(200)}},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12008, 12009); err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.CloseAccount(ctx, transferbus.AccountClosure{
+					AccountID:      12008,
+					SweepAccountID: 12009,
+				}); err != nil {
+					return err
+				}
+
+				var got []accountResult
+				for _, id := range []int64{12008, 12009} {
+					acc, err := db.BusDomain.TransferBus.GetBalance(ctx, id)
+					if err != nil {
+						return err
+					}
+					got = append(got, accountResult{Status: acc.Status, Balance: acc.Balance})
+				}
+				return got
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "closedstaysclosed",
+			ExpResp: transferbus.ErrAccClosed,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.UnfreezeAccount(ctx, 12008)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "closedrejectstransfers",
+			ExpResp: transferbus.ErrAccClosed,
+			ExcFunc: func(ctx context.Context) any {
+				return transfer(ctx, 12009, 12008)
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "closefrozen",
+			ExpResp: transferbus.ErrAccFrozen,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CloseAccount(ctx, transferbus.AccountClosure{AccountID: 12000})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "closewithholds",
+			ExpResp: transferbus.ErrAccHasHolds,
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12010, 12011); err != nil {
+					return err
+				}
+				if _, err := db.BusDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+					SourceAccountID:      12010,
+					DestinationAccountID: 12011,
+					Amount:               decimal.NewFromInt(10),
+				}); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.CloseAccount(ctx, transferbus.AccountClosure{
+					AccountID:      12010,
+					SweepAccountID: 12011,
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "captureintofrozen",
+			ExpResp: transferbus.ErrAccFrozen,
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12012, 12013); err != nil {
+					return err
+				}
+				hold, err := db.BusDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+					SourceAccountID:      12012,
+					DestinationAccountID: 12013,
+					Amount:               decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+				if _, err := db.BusDomain.TransferBus.FreezeAccount(ctx, 12013); err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.Capture(ctx, transferbus.HoldCapture{HoldID: hold.HoldID})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "closeabovelimit",
+			ExpResp: accountResult{Status: transferbus.AccountStatusClosed, Balance: decimal.Zero},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 12014, 12015); err != nil {
+					return err
+				}
+
+				// the sweep is not a transfer, so the limits cannot keep the account open
+				if _, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 12014, transferbus.TransferLimits{
+					MaxAmount: decimal.NewFromInt(50),
+				}); err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.CloseAccount(ctx, transferbus.AccountClosure{
+					AccountID:      12014,
+					SweepAccountID: 12015,
+				})
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpAccount,
+		},
+		{
+			Name:    "freezesystemaccount",
+			ExpResp: transferbus.ErrSystemAccount,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.FreezeAccount(ctx, transferbus.EquityAccountID)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "closesystemaccount",
+			ExpResp: transferbus.ErrSystemAccount,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CloseAccount(ctx, transferbus.AccountClosure{AccountID: transferbus.EquityAccountID})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+	}
+
+	return table
+}
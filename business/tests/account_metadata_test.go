@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func accountMetadata(db *dbtest.Database) []unittest.Table {
+	type metadata struct {
+		OwnerRef string
+		Type     transferbus.AccountType
+		Name     string
+		Labels   []string
+	}
+
+	cmpMetadata := func(got any, exp any) string {
+		gotResp, exists := got.(transferbus.Account)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(metadata{
+			OwnerRef: gotResp.OwnerRef,
+			Type:     gotResp.Type,
+			Name:     gotResp.Name,
+			Labels:   gotResp.Labels,
+		}, exp)
+	}
+
+	accountIDs := func(got any, exp any) string {
+		gotResp, exists := got.([]transferbus.Account)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		ids := make([]int64, len(gotResp))
+		for i, acc := range gotResp {
+			ids[i] = acc.AccountID
+		}
+		return cmp.Diff(ids, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "defaults",
+			ExpResp: metadata{Type: transferbus.AccountTypeCustomer, Labels: []string{}},
+			ExcFunc: func(ctx context.Context) any {
+				acc, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+					AccountID:      13000,
+					InitialBalance: decimal.NewFromInt(100),
+				})
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpMetadata,
+		},
+		{
+			Name: "create",
+			ExpResp: metadata{
+				OwnerRef: "owner-13001",
+				Type:     transferbus.AccountTypeSuspense,
+				Name:     "unmatched payments",
+				Labels:   []string{"ops", "eu"},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				acc, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+					AccountID:      13001,
+					InitialBalance: decimal.Zero,
+					OwnerRef:       "owner-13001",
+					Type:           transferbus.AccountTypeSuspense,
+					Name:           "unmatched payments",
+					Labels:         []string{"ops", "eu"},
+				})
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpMetadata,
+		},
+		{
+			Name: "partialupdate",
+			ExpResp: metadata{
+				OwnerRef: "owner-13001",
+				Type:     transferbus.AccountTypeSuspense,
+				Name:     "suspense eu",
+				Labels:   []string{"ops", "eu"},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				name := "suspense eu"
+				acc, err := db.BusDomain.TransferBus.UpdateAccount(ctx, 13001, transferbus.UpdateAccount{
+					Name: &name,
+				})
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpMetadata,
+		},
+		{
+			Name:    "clearlabels",
+			ExpResp: metadata{Type: transferbus.AccountTypeFee, Labels: []string{}},
+			ExcFunc: func(ctx context.Context) any {
+				accountType := transferbus.AccountTypeFee
+				acc, err := db.BusDomain.TransferBus.UpdateAccount(ctx, 13000, transferbus.UpdateAccount{
+					Type:   &accountType,
+					Labels: &[]string{},
+				})
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpMetadata,
+		},
+		{
+			Name:    "updatenotfound",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				name := "missing"
+				_, err := db.BusDomain.TransferBus.UpdateAccount(ctx, 13999, transferbus.UpdateAccount{
+					Name: &name,
+				})
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(fmt.Sprint(got), fmt.Sprint(exp))
+			},
+		},
+		{
+			Name:    "updatesystemaccount",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				// -1 is the equity account seeded by the migrations
+				accType := transferbus.AccountTypeCustomer
+				_, err := db.BusDomain.TransferBus.UpdateAccount(ctx, -1, transferbus.UpdateAccount{
+					Type: &accType,
+				})
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(fmt.Sprint(got), fmt.Sprint(exp))
+			},
+		},
+		{
+			Name:    "searchbylabels",
+			ExpResp: []int64{13002, 13004},
+			ExcFunc: func(ctx context.Context) any {
+				for id, labels := range map[int64][]string{
+					13002: {"vip", "eu"},
+					13003: {"vip"},
+					13004: {"eu", "vip", "legacy"},
+				} {
+					_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+						AccountID:      id,
+						InitialBalance: decimal.Zero,
+						OwnerRef:       "owner-1300x",
+						Labels:         labels,
+					})
+					if err != nil {
+						return err
+					}
+				}
+
+				accs, err := db.BusDomain.TransferBus.QueryAccounts(ctx, transferbus.AccountFilter{
+					OwnerRef: "owner-1300x",
+					Labels:   []string{"eu", "vip"},
+					Limit:    10,
+				})
+				if err != nil {
+					return err
+				}
+				return accs
+			},
+			CmpFunc: accountIDs,
+		},
+		{
+			Name:    "searchpage",
+			ExpResp: []int64{13003},
+			ExcFunc: func(ctx context.Context) any {
+				accs, err := db.BusDomain.TransferBus.QueryAccounts(ctx, transferbus.AccountFilter{
+					OwnerRef: "owner-1300x",
+					Cursor:   13002,
+					Limit:    1,
+				})
+				if err != nil {
+					return err
+				}
+				return accs
+			},
+			CmpFunc: accountIDs,
+		},
+		{
+			Name:    "searchsystemaccounts",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				accs, err := db.BusDomain.TransferBus.QueryAccounts(ctx, transferbus.AccountFilter{
+					Type:  transferbus.AccountTypeOperational,
+					Limit: 10,
+				})
+				if err != nil {
+					return err
+				}
+
+				// system accounts are seeded by the migrations with negative ids
+				return len(accs) > 0 && accs[0].AccountID < 0
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func batch(db *dbtest.Database) []unittest.Table {
+	// newAccounts opens accounts with 100 each
+	newAccounts := func(ctx context.Context, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	transaction := func(srcID int64, dstID int64, amount int64) transferbus.Transaction {
+		return transferbus.Transaction{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(amount),
+		}
+	}
+
+	type batchResult struct {
+		Failed   []int
+		Errors   []error
+		Balances []decimal.Decimal
+	}
+
+	run := func(ctx context.Context, mode transferbus.BatchMode, transactions []transferbus.Transaction, accountIDs ...int64) any {
+		if err := newAccounts(ctx, accountIDs...); err != nil {
+			return err
+		}
+
+		var got batchResult
+
+		results, err := db.BusDomain.TransferBus.CreateTransactions(ctx, transactions, mode)
+		var batchErr *transferbus.BatchError
+		switch {
+		case errors.As(err, &batchErr):
+			got.Failed = append(got.Failed, batchErr.Index)
+			got.Errors = append(got.Errors, batchErr.Err)
+		case err != nil:
+			return err
+		}
+
+		for i, result := range results {
+			if result.Err != nil {
+				got.Failed = append(got.Failed, i)
+				got.Errors = append(got.Errors, result.Err)
+			}
+		}
+
+		for _, id := range accountIDs {
+			acc, err := db.BusDomain.TransferBus.GetBalance(ctx, id)
+			if err != nil {
+				return err
+			}
+			got.Balances = append(got.Balances, acc.Balance)
+		}
+
+		return got
+	}
+
+	cmpBatch := func(got any, exp any) string {
+		gotResp, exists := got.(batchResult)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		expResp := exp.(batchResult)
+		if len(gotResp.Errors) != len(expResp.Errors) {
+			return fmt.Sprintf("expected errors %v, got %v", expResp.Errors, gotResp.Errors)
+		}
+		for i := range gotResp.Errors {
+			if !errors.Is(gotResp.Errors[i], expResp.Errors[i]) {
+				return fmt.Sprintf("expected errors %v, got %v", expResp.Errors, gotResp.Errors)
+			}
+		}
+
+		// the errors may be wrapped, so they were matched above
+		gotResp.Errors, expResp.Errors = nil, nil
+		return cmp.Diff(gotResp, expResp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "atomic",
+			ExpResp: batchResult{
+				Balances: []decimal.Decimal{decimal.NewFromInt(70), decimal.NewFromInt(110), decimal.NewFromInt(120)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, transferbus.BatchModeAtomic, []transferbus.Transaction{
+					transaction(10000, 10001, 20),
+					transaction(10000, 10002, 10),
+					transaction(10001, 10002, 10),
+				}, 10000, 10001, 10002)
+			},
+			CmpFunc: cmpBatch,
+		},
+		{
+			Name: "atomicfailure",
+			ExpResp: batchResult{
+				Failed:   []int{1},
+				Errors:   []error{transferbus.ErrInsufficientFunds},
+				Balances: []decimal.Decimal{decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, transferbus.BatchModeAtomic, []transferbus.Transaction{
+					transaction(10003, 10004, 20),
+					transaction(10003, 10005, 1000),
+					transaction(10004, 10005, 10),
+				}, 10003, 10004, 10005)
+			},
+			CmpFunc: cmpBatch,
+		},
+		{
+			Name: "besteffort",
+			ExpResp: batchResult{
+				Failed:   []int{1, 2},
+				Errors:   []error{transferbus.ErrInsufficientFunds, transferbus.ErrAccNotFound},
+				Balances: []decimal.Decimal{decimal.NewFromInt(80), decimal.NewFromInt(110), decimal.NewFromInt(110)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, transferbus.BatchModeBestEffort, []transferbus.Transaction{
+					transaction(10006, 10007, 20),
+					transaction(10006, 10008, 1000),
+					transaction(10006, 12345, 10),
+					transaction(10007, 10008, 10),
+				}, 10006, 10007, 10008)
+			},
+			CmpFunc: cmpBatch,
+		},
+	}
+
+	return table
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
@@ -27,7 +28,7 @@ func Test_Concurrent_Transfers(t *testing.T) {
 
 	// 1. SETUP: Create two accounts with a known starting balance.
 	initialBalance := decimal.NewFromInt(10000)
-	transferAmount := decimal.NewFromFloat(10.12345)
+	transferAmount := decimal.NewFromFloat(10.12)
 
 	acc1, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
 		AccountID:      1,
@@ -53,7 +54,7 @@ func Test_Concurrent_Transfers(t *testing.T) {
 	for i := 0; i < numConcurrentTransfers; i++ {
 		go func() {
 			defer wg.Done()
-			err := busDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			_, err := busDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
 				SourceAccountID:      acc1.AccountID,
 				DestinationAccountID: acc2.AccountID,
 				Amount:               transferAmount,
@@ -99,3 +100,181 @@ func Test_Concurrent_Transfers(t *testing.T) {
 	t.Logf("Final balance for account 1 is correct: %s", finalAcc1.Balance)
 	t.Logf("Final balance for account 2 is correct: %s", finalAcc2.Balance)
 }
+
+// Test_Concurrent_Bidirectional_Transfers fires transfers in both directions
+// between the same two accounts to check that they never deadlock.
+func Test_Concurrent_Bidirectional_Transfers(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.NewDatabase(t, c, "Test_Concurrent_Bidirectional_Transfers")
+	defer db.Teardown()
+
+	busDomain := db.BusDomain
+	ctx := context.Background()
+
+	// 1. SETUP: Create two accounts with the same starting balance.
+	initialBalance := decimal.NewFromInt(10000)
+	transferAmount := decimal.NewFromFloat(10.12)
+
+	acc1, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+		AccountID:      1,
+		InitialBalance: initialBalance,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create account 1: %v", err)
+	}
+
+	acc2, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+		AccountID:      2,
+		InitialBalance: initialBalance,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create account 2: %v", err)
+	}
+
+	// 2. EXECUTE: Launch the same number of transfers in each direction.
+	const numTransfersPerDirection = 50
+	var wg sync.WaitGroup
+	wg.Add(2 * numTransfersPerDirection)
+
+	errs := make(chan error, 2*numTransfersPerDirection)
+	transfer := func(src, dst int64) {
+		defer wg.Done()
+		_, err := busDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      src,
+			DestinationAccountID: dst,
+			Amount:               transferAmount,
+		})
+		if err != nil {
+			errs <- err
+		}
+	}
+
+	for i := 0; i < numTransfersPerDirection; i++ {
+		go transfer(acc1.AccountID, acc2.AccountID)
+		go transfer(acc2.AccountID, acc1.AccountID)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	// 3. VERIFY: Every transfer succeeded and the balances are back where they started.
+	for err := range errs {
+		t.Errorf("Transaction failed: %v", err)
+	}
+
+	for _, accountID := range []int64{acc1.AccountID, acc2.AccountID} {
+		acc, err := busDomain.TransferBus.GetBalance(ctx, accountID)
+		if err != nil {
+			t.Fatalf("Failed to get final balance for account %d: %v", accountID, err)
+		}
+
+		if !acc.Balance.Equal(initialBalance) {
+			t.Errorf("Account %d final balance is incorrect. Got %s, Expected %s", accountID, acc.Balance, initialBalance)
+		}
+	}
+}
+
+// Test_Concurrent_Hold_Expiry expires holds on two accounts while transfers run
+// in both directions between them to check that expiry never deadlocks against
+// the transfers.
+func Test_Concurrent_Hold_Expiry(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.NewDatabase(t, c, "Test_Concurrent_Hold_Expiry")
+	defer db.Teardown()
+
+	busDomain := db.BusDomain
+	ctx := context.Background()
+
+	// 1. SETUP: Create two accounts and authorize short-lived holds on both of them.
+	initialBalance := decimal.NewFromInt(10000)
+	transferAmount := decimal.NewFromFloat(10.12)
+
+	acc1, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+		AccountID:      1,
+		InitialBalance: initialBalance,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create account 1: %v", err)
+	}
+
+	acc2, err := busDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+		AccountID:      2,
+		InitialBalance: initialBalance,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create account 2: %v", err)
+	}
+
+	const numHoldsPerAccount = 50
+	for i := 0; i < numHoldsPerAccount; i++ {
+		for _, accounts := range [][2]int64{{acc1.AccountID, acc2.AccountID}, {acc2.AccountID, acc1.AccountID}} {
+			_, err := busDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+				SourceAccountID:      accounts[0],
+				DestinationAccountID: accounts[1],
+				Amount:               decimal.NewFromInt(1),
+				ExpiresIn:            time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("Failed to authorize hold on account %d: %v", accounts[0], err)
+			}
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// 2. EXECUTE: Expire the holds while transfers run in both directions.
+	const numTransfersPerDirection = 50
+	const numExpiryRuns = 5
+	var wg sync.WaitGroup
+	wg.Add(2*numTransfersPerDirection + numExpiryRuns)
+
+	errs := make(chan error, 2*numTransfersPerDirection+numExpiryRuns)
+	transfer := func(src, dst int64) {
+		defer wg.Done()
+		_, err := busDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      src,
+			DestinationAccountID: dst,
+			Amount:               transferAmount,
+		})
+		if err != nil {
+			errs <- err
+		}
+	}
+
+	for i := 0; i < numExpiryRuns; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := busDomain.TransferBus.ExpireHolds(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	for i := 0; i < numTransfersPerDirection; i++ {
+		go transfer(acc1.AccountID, acc2.AccountID)
+		go transfer(acc2.AccountID, acc1.AccountID)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	// 3. VERIFY: Every call succeeded, every hold was released and the
+	// balances are back where they started.
+	for err := range errs {
+		t.Errorf("Concurrent call failed: %v", err)
+	}
+
+	for _, accountID := range []int64{acc1.AccountID, acc2.AccountID} {
+		acc, err := busDomain.TransferBus.GetBalance(ctx, accountID)
+		if err != nil {
+			t.Fatalf("Failed to get final balance for account %d: %v", accountID, err)
+		}
+
+		if !acc.Balance.Equal(initialBalance) {
+			t.Errorf("Account %d final balance is incorrect. Got %s, Expected %s", accountID, acc.Balance, initialBalance)
+		}
+		if !acc.AvailableBalance().Equal(initialBalance) {
+			t.Errorf("Account %d still has funds on hold. Got available %s, Expected %s", accountID, acc.AvailableBalance(), initialBalance)
+		}
+	}
+}
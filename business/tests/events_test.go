@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+// failingPublisher fails to publish every event.
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(ctx context.Context, event transferbus.Event) error {
+	return errors.New("broker unavailable")
+}
+
+func events(db *dbtest.Database) []unittest.Table {
+	// newAccounts opens accounts with 100 each
+	newAccounts := func(ctx context.Context, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// eventTypes returns the types of the events published for the account
+	eventTypes := func(publisher *transferbus.MemoryPublisher, accountID int64) []transferbus.EventType {
+		var types []transferbus.EventType
+		for _, event := range publisher.Events() {
+			if event.AccountID == accountID {
+				types = append(types, event.Type)
+			}
+		}
+		return types
+	}
+
+	// relay relays events until the publisher holds the given number of events
+	// of the account. Events are held back while transactions of the tests
+	// running in parallel are in flight, so a single relay may miss them.
+	relay := func(ctx context.Context, publisher *transferbus.MemoryPublisher, accountID int64, n int) error {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if _, err := db.BusDomain.TransferBus.RelayEvents(ctx, publisher); err != nil {
+				return err
+			}
+			if len(eventTypes(publisher, accountID)) >= n || time.Now().After(deadline) {
+				return nil
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	cmpTypes := func(got any, exp any) string {
+		gotResp, exists := got.([]transferbus.EventType)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "ordered",
+			ExpResp: []transferbus.EventType{
+				transferbus.EventAccountCreated,
+				transferbus.EventTransferPosted,
+				transferbus.EventTransferPosted,
+				transferbus.EventTransferReversed,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 17000, 17001); err != nil {
+					return err
+				}
+
+				transfer, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      17000,
+					DestinationAccountID: 17001,
+					Amount:               decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: transfer.TransferID,
+				}); err != nil {
+					return err
+				}
+
+				publisher := &transferbus.MemoryPublisher{}
+				if err := relay(ctx, publisher, 17001, 4); err != nil {
+					return err
+				}
+
+				return eventTypes(publisher, 17001)
+			},
+			CmpFunc: cmpTypes,
+		},
+		{
+			Name: "republished",
+			ExpResp: []transferbus.EventType{
+				transferbus.EventAccountCreated,
+				transferbus.EventTransferPosted,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 17002); err != nil {
+					return err
+				}
+
+				published, err := db.BusDomain.TransferBus.RelayEvents(ctx, failingPublisher{})
+				if err == nil || published != 0 {
+					return fmt.Errorf("published %d events with a failing publisher: %v", published, err)
+				}
+
+				// the events the publisher failed on are published by the next relay
+				publisher := &transferbus.MemoryPublisher{}
+				if err := relay(ctx, publisher, 17002, 2); err != nil {
+					return err
+				}
+
+				return eventTypes(publisher, 17002)
+			},
+			CmpFunc: cmpTypes,
+		},
+		{
+			Name: "heldback",
+			ExpResp: []transferbus.EventType{
+				transferbus.EventAccountCreated,
+				transferbus.EventTransferPosted,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				// a transaction with an id is running while the account is opened,
+				// and could still commit an event ahead of its events
+				tx, err := db.DB.Begin(ctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback(ctx)
+
+				if _, err := tx.Exec(ctx, "SELECT pg_current_xact_id()"); err != nil {
+					return err
+				}
+
+				if err := newAccounts(ctx, 17003); err != nil {
+					return err
+				}
+
+				publisher := &transferbus.MemoryPublisher{}
+				if _, err := db.BusDomain.TransferBus.RelayEvents(ctx, publisher); err != nil {
+					return err
+				}
+				if types := eventTypes(publisher, 17003); len(types) != 0 {
+					return fmt.Errorf("published %v while an earlier transaction was running", types)
+				}
+
+				if err := tx.Rollback(ctx); err != nil {
+					return err
+				}
+
+				if err := relay(ctx, publisher, 17003, 2); err != nil {
+					return err
+				}
+
+				return eventTypes(publisher, 17003)
+			},
+			CmpFunc: cmpTypes,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,376 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func Test_FX_Rates(t *testing.T) {
+	t.Parallel()
+
+	unittest.Run(t, fxRates(t.TempDir()), "fx-rates")
+}
+
+func fxRates(dir string) []unittest.Table {
+	type price struct {
+		DestinationAmount decimal.Decimal
+		Rate              decimal.Decimal
+	}
+
+	cmpPrice := func(got any, exp any) string {
+		gotResp, exists := got.(fxbus.Price)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(price{
+			DestinationAmount: gotResp.DestinationAmount,
+			Rate:              gotResp.Rate,
+		}, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "crossrate",
+			ExpResp: price{
+				DestinationAmount: decimal.NewFromInt(1667),
+				Rate:              decimal.RequireFromString("166.6666666667"),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				fx := fxbus.New(fxbus.TestRates(), time.Minute)
+
+				// yen has no minor unit
+				resp, err := fx.Price(ctx, currency.EUR, currency.JPY, decimal.NewFromInt(10))
+				if err != nil {
+					return err
+				}
+				return resp
+			},
+			CmpFunc: cmpPrice,
+		},
+		{
+			Name: "file",
+			ExpResp: price{
+				DestinationAmount: decimal.RequireFromString("18.4"),
+				Rate:              decimal.RequireFromString("0.92"),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				path := filepath.Join(dir, "rates.json")
+				data := `{"base": "USD", "rates": {"EUR": "0.92"}}`
+				if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+					return err
+				}
+
+				rates, err := fxbus.NewFileRates(path)
+				if err != nil {
+					return err
+				}
+
+				resp, err := fxbus.New(rates, time.Minute).Price(ctx, currency.USD, currency.EUR, decimal.NewFromInt(20))
+				if err != nil {
+					return err
+				}
+				return resp
+			},
+			CmpFunc: cmpPrice,
+		},
+		{
+			Name:    "ratenotfound",
+			ExpResp: fxbus.ErrRateNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				rates := fxbus.NewStaticRates(currency.USD, nil)
+
+				_, err := fxbus.New(rates, time.Minute).Price(ctx, currency.USD, currency.EUR, decimal.NewFromInt(20))
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "samecurrency",
+			ExpResp: fxbus.ErrSameCurrency,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := fxbus.New(fxbus.TestRates(), time.Minute).Price(ctx, currency.USD, currency.USD, decimal.NewFromInt(20))
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+	}
+
+	return table
+}
+
+func fxTransfer(db *dbtest.Database, sd dbtest.SeedData) []unittest.Table {
+	usdAcc := sd.Accounts[0].Account
+	amount := decimal.NewFromInt(10)
+
+	newEURAccount := func(ctx context.Context, accountID int64) (transferbus.Account, error) {
+		return db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+			AccountID: accountID,
+			Currency:  currency.EUR,
+		})
+	}
+
+	type fxResult struct {
+		EntryType         transferbus.EntryType
+		Amount            decimal.Decimal
+		DestinationAmount decimal.Decimal
+		Rate              decimal.Decimal
+		SourceDelta       decimal.Decimal
+		DestinationDelta  decimal.Decimal
+		LegCount          int
+		LegSums           map[string]decimal.Decimal
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "fxtransfer",
+			ExpResp: fxResult{
+				EntryType:         transferbus.EntryTypeFXTransfer,
+				Amount:            amount,
+				DestinationAmount: decimal.NewFromInt(9),
+				Rate:              decimal.RequireFromString("0.9"),
+				SourceDelta:       amount.Neg(),
+				DestinationDelta:  decimal.NewFromInt(9),
+				LegCount:          4,
+				LegSums: map[string]decimal.Decimal{
+					"USD": decimal.Zero,
+					"EUR": decimal.Zero,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				eurAcc, err := newEURAccount(ctx, 7000)
+				if err != nil {
+					return err
+				}
+
+				srcBefore, err := db.BusDomain.TransferBus.GetBalance(ctx, usdAcc.AccountID)
+				if err != nil {
+					return err
+				}
+
+				quote, err := db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+				})
+				if err != nil {
+					return err
+				}
+
+				transfer, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+					QuoteID:              quote.QuoteID,
+				})
+				if err != nil {
+					return err
+				}
+
+				srcAfter, err := db.BusDomain.TransferBus.GetBalance(ctx, usdAcc.AccountID)
+				if err != nil {
+					return err
+				}
+
+				dstAfter, err := db.BusDomain.TransferBus.GetBalance(ctx, eurAcc.AccountID)
+				if err != nil {
+					return err
+				}
+
+				sums := make(map[string]decimal.Decimal)
+				for _, leg := range transfer.Legs {
+					sums[leg.Currency.String()] = sums[leg.Currency.String()].Add(leg.Amount)
+				}
+
+				return fxResult{
+					EntryType:         transfer.EntryType,
+					Amount:            transfer.Amount,
+					DestinationAmount: transfer.DestinationAmount,
+					Rate:              transfer.Rate,
+					SourceDelta:       srcAfter.Balance.Sub(srcBefore.Balance),
+					DestinationDelta:  dstAfter.Balance.Sub(eurAcc.Balance),
+					LegCount:          len(transfer.Legs),
+					LegSums:           sums,
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(fxResult)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				return cmp.Diff(gotResp, exp)
+			},
+		},
+		{
+			Name:    "quoteused",
+			ExpResp: transferbus.ErrQuoteUsed,
+			ExcFunc: func(ctx context.Context) any {
+				eurAcc, err := newEURAccount(ctx, 7001)
+				if err != nil {
+					return err
+				}
+
+				quote, err := db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+				})
+				if err != nil {
+					return err
+				}
+
+				r := transferbus.Transaction{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+					QuoteID:              quote.QuoteID,
+				}
+				if _, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r); err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "quotemismatch",
+			ExpResp: transferbus.ErrQuoteMismatch,
+			ExcFunc: func(ctx context.Context) any {
+				eurAcc, err := newEURAccount(ctx, 7002)
+				if err != nil {
+					return err
+				}
+
+				quote, err := db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount.Add(decimal.NewFromInt(1)),
+					QuoteID:              quote.QuoteID,
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "quoteexpired",
+			ExpResp: transferbus.ErrQuoteExpired,
+			ExcFunc: func(ctx context.Context) any {
+				eurAcc, err := newEURAccount(ctx, 7003)
+				if err != nil {
+					return err
+				}
+
+				quote, err := db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+				})
+				if err != nil {
+					return err
+				}
+
+				const q = `UPDATE fx_quotes SET expires_date = NOW() - INTERVAL '1 second' WHERE quote_id = $1`
+				if _, err := db.DB.Exec(ctx, q, quote.QuoteID); err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: eurAcc.AccountID,
+					Amount:               amount,
+					QuoteID:              quote.QuoteID,
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "quotenotfound",
+			ExpResp: transferbus.ErrQuoteNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: sd.Accounts[1].AccountID,
+					Amount:               amount,
+					QuoteID:              uuid.New(),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "zerodestinationamount",
+			ExpResp: transferbus.ErrZeroAmount,
+			ExcFunc: func(ctx context.Context) any {
+				idrAcc, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+					AccountID:      7004,
+					Currency:       currency.IDR,
+					InitialBalance: decimal.NewFromInt(100),
+				})
+				if err != nil {
+					return err
+				}
+
+				// 0.01 IDR is worth far less than a cent
+				_, err = db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      idrAcc.AccountID,
+					DestinationAccountID: usdAcc.AccountID,
+					Amount:               decimal.RequireFromString("0.01"),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "samecurrency",
+			ExpResp: fxbus.ErrSameCurrency,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      usdAcc.AccountID,
+					DestinationAccountID: sd.Accounts[1].AccountID,
+					Amount:               amount,
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+	}
+
+	return table
+}
+
+// cmpErrorIs compares an error returned by a table against the expected one,
+// allowing it to be wrapped.
+func cmpErrorIs(got any, exp any) string {
+	gotErr, exists := got.(error)
+	if !exists || !errors.Is(gotErr, exp.(error)) {
+		return fmt.Sprintf("expected %v, got %v", exp, got)
+	}
+	return ""
+}
@@ -0,0 +1,266 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func holds(db *dbtest.Database) []unittest.Table {
+	// authorize opens two accounts with 100 each and holds amount on the first
+	authorize := func(ctx context.Context, srcID int64, dstID int64, amount int64, expiresIn time.Duration) (transferbus.Hold, error) {
+		for _, id := range []int64{srcID, dstID} {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return transferbus.Hold{}, err
+			}
+		}
+
+		return db.BusDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(amount),
+			ExpiresIn:            expiresIn,
+		})
+	}
+
+	type holdResult struct {
+		Status           transferbus.HoldStatus
+		CapturedAmount   decimal.Decimal
+		Balance          decimal.Decimal
+		AvailableBalance decimal.Decimal
+		DestinationDelta decimal.Decimal
+	}
+
+	// result reads the hold back along with the balances it moved
+	result := func(ctx context.Context, hold transferbus.Hold) any {
+		hold, err := db.BusDomain.TransferBus.QueryHold(ctx, hold.HoldID)
+		if err != nil {
+			return err
+		}
+
+		src, err := db.BusDomain.TransferBus.GetBalance(ctx, hold.SourceAccountID)
+		if err != nil {
+			return err
+		}
+
+		dst, err := db.BusDomain.TransferBus.GetBalance(ctx, hold.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+
+		return holdResult{
+			Status:           hold.Status,
+			CapturedAmount:   hold.CapturedAmount,
+			Balance:          src.Balance,
+			AvailableBalance: src.AvailableBalance(),
+			DestinationDelta: dst.Balance.Sub(decimal.NewFromInt(100)),
+		}
+	}
+
+	cmpHold := func(got any, exp any) string {
+		gotResp, exists := got.(holdResult)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "authorize",
+			ExpResp: holdResult{
+				Status:           transferbus.HoldStatusAuthorized,
+				CapturedAmount:   decimal.Zero,
+				Balance:          decimal.NewFromInt(100),
+				AvailableBalance: decimal.NewFromInt(70),
+				DestinationDelta: decimal.Zero,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9000, 9001, 30, 0)
+				if err != nil {
+					return err
+				}
+
+				return result(ctx, hold)
+			},
+			CmpFunc: cmpHold,
+		},
+		{
+			Name:    "heldfunds",
+			ExpResp: transferbus.ErrInsufficientFunds,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := authorize(ctx, 9002, 9003, 80, 0); err != nil {
+					return err
+				}
+
+				// the ledger balance covers the debit but the available balance does not
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      9002,
+					DestinationAccountID: 9003,
+					Amount:               decimal.NewFromInt(30),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name: "partialcapture",
+			ExpResp: holdResult{
+				Status:           transferbus.HoldStatusCaptured,
+				CapturedAmount:   decimal.NewFromInt(20),
+				Balance:          decimal.NewFromInt(80),
+				AvailableBalance: decimal.NewFromInt(80),
+				DestinationDelta: decimal.NewFromInt(20),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9004, 9005, 50, 0)
+				if err != nil {
+					return err
+				}
+
+				transfer, err := db.BusDomain.TransferBus.Capture(ctx, transferbus.HoldCapture{
+					HoldID: hold.HoldID,
+					Amount: decimal.NewFromInt(20),
+				})
+				if err != nil {
+					return err
+				}
+				if transfer.EntryType != transferbus.EntryTypeCapture {
+					return fmt.Errorf("entry type %s, expected %s", transfer.EntryType, transferbus.EntryTypeCapture)
+				}
+
+				return result(ctx, hold)
+			},
+			CmpFunc: cmpHold,
+		},
+		{
+			Name:    "capturedtwice",
+			ExpResp: transferbus.ErrHoldClosed,
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9006, 9007, 50, 0)
+				if err != nil {
+					return err
+				}
+
+				c := transferbus.HoldCapture{HoldID: hold.HoldID}
+				if _, err := db.BusDomain.TransferBus.Capture(ctx, c); err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.Capture(ctx, c)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "captureexceeded",
+			ExpResp: transferbus.ErrCaptureExceeded,
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9008, 9009, 50, 0)
+				if err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.Capture(ctx, transferbus.HoldCapture{
+					HoldID: hold.HoldID,
+					Amount: decimal.NewFromInt(51),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name: "void",
+			ExpResp: holdResult{
+				Status:           transferbus.HoldStatusVoided,
+				CapturedAmount:   decimal.Zero,
+				Balance:          decimal.NewFromInt(100),
+				AvailableBalance: decimal.NewFromInt(100),
+				DestinationDelta: decimal.Zero,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9010, 9011, 50, 0)
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.Void(ctx, hold.HoldID); err != nil {
+					return err
+				}
+
+				return result(ctx, hold)
+			},
+			CmpFunc: cmpHold,
+		},
+		{
+			Name: "expired",
+			ExpResp: holdResult{
+				Status:           transferbus.HoldStatusExpired,
+				CapturedAmount:   decimal.Zero,
+				Balance:          decimal.NewFromInt(100),
+				AvailableBalance: decimal.NewFromInt(100),
+				DestinationDelta: decimal.Zero,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9012, 9013, 50, time.Millisecond)
+				if err != nil {
+					return err
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				_, err = db.BusDomain.TransferBus.Capture(ctx, transferbus.HoldCapture{HoldID: hold.HoldID})
+				if !errors.Is(err, transferbus.ErrHoldExpired) {
+					return fmt.Errorf("expected %v, got %v", transferbus.ErrHoldExpired, err)
+				}
+
+				if _, err := db.BusDomain.TransferBus.ExpireHolds(ctx); err != nil {
+					return err
+				}
+
+				return result(ctx, hold)
+			},
+			CmpFunc: cmpHold,
+		},
+		{
+			Name:    "zeroamount",
+			ExpResp: transferbus.ErrZeroAmount,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := authorize(ctx, 9014, 9015, 0, 0)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "capturezeroamount",
+			ExpResp: transferbus.ErrZeroAmount,
+			ExcFunc: func(ctx context.Context) any {
+				hold, err := authorize(ctx, 9016, 9017, 10, 0)
+				if err != nil {
+					return err
+				}
+
+				// 0.001 rounds to nothing at the two decimals of USD
+				_, err = db.BusDomain.TransferBus.Capture(ctx, transferbus.HoldCapture{
+					HoldID: hold.HoldID,
+					Amount: decimal.RequireFromString("0.001"),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+func isolation(db *dbtest.Database) []unittest.Table {
+	// a single attempt surfaces serialization failures instead of retrying them
+	singleAttempt := transferdb.NewTxQueries(db.DB, transferdb.RetryConfig{MaxAttempts: 1})
+	retrying := transferdb.NewTxQueries(db.DB, transferdb.DefaultRetryConfig)
+
+	newAccounts := func(ctx context.Context, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	type skewResult struct {
+		Failed    int
+		Exhausted int
+	}
+
+	// writeSkew runs two transactions that both read the two accounts and then
+	// each write the account the other one read. The writes add nothing, so
+	// the balances stay in line with the ledger.
+	writeSkew := func(ctx context.Context, store *transferdb.TxQueries, opts pgx.TxOptions, accA int64, accB int64) any {
+		if err := newAccounts(ctx, accA, accB); err != nil {
+			return err
+		}
+
+		// both transactions read before either writes, only on their first attempt
+		var reads sync.WaitGroup
+		reads.Add(2)
+		var firstAttempt [2]sync.Once
+
+		run := func(i int, writeAccountID int64) error {
+			return store.InTx(ctx, opts, func(dbtx transferdb.TxQuerier) error {
+				if _, err := dbtx.GetAccounts(ctx, []int64{accA, accB}); err != nil {
+					return err
+				}
+				firstAttempt[i].Do(func() {
+					reads.Done()
+					reads.Wait()
+				})
+
+				_, err := dbtx.CreditAccount(ctx, transferdbgen.CreditAccountParams{
+					Amount:    decimal.Zero,
+					AccountID: writeAccountID,
+				})
+				return err
+			})
+		}
+
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs[0] = run(0, accB)
+		}()
+		go func() {
+			defer wg.Done()
+			errs[1] = run(1, accA)
+		}()
+		wg.Wait()
+
+		var got skewResult
+		for _, err := range errs {
+			if err != nil {
+				got.Failed++
+			}
+			if errors.Is(err, transferdb.ErrRetriesExhausted) {
+				got.Exhausted++
+			}
+		}
+		return got
+	}
+
+	cmpResult := func(got any, exp any) string {
+		return cmp.Diff(got, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "read-committed-allows-write-skew",
+			ExpResp: skewResult{},
+			ExcFunc: func(ctx context.Context) any {
+				return writeSkew(ctx, singleAttempt, pgx.TxOptions{IsoLevel: pgx.ReadCommitted}, 11000, 11001)
+			},
+			CmpFunc: cmpResult,
+		},
+		{
+			Name:    "serializable-aborts-write-skew",
+			ExpResp: skewResult{Failed: 1, Exhausted: 1},
+			ExcFunc: func(ctx context.Context) any {
+				return writeSkew(ctx, singleAttempt, pgx.TxOptions{IsoLevel: pgx.Serializable}, 11002, 11003)
+			},
+			CmpFunc: cmpResult,
+		},
+		{
+			Name:    "serializable-retries-write-skew",
+			ExpResp: skewResult{},
+			ExcFunc: func(ctx context.Context) any {
+				return writeSkew(ctx, retrying, pgx.TxOptions{IsoLevel: pgx.Serializable}, 11004, 11005)
+			},
+			CmpFunc: cmpResult,
+		},
+		{
+			Name:    "read-only-rejects-writes",
+			ExpResp: "25006",
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 11006); err != nil {
+					return err
+				}
+
+				err := retrying.InTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, func(dbtx transferdb.TxQuerier) error {
+					_, err := dbtx.CreditAccount(ctx, transferdbgen.CreditAccountParams{
+						Amount:    decimal.Zero,
+						AccountID: 11006,
+					})
+					return err
+				})
+
+				var pgError *pgconn.PgError
+				if !errors.As(err, &pgError) {
+					return err
+				}
+				return pgError.Code
+			},
+			CmpFunc: cmpResult,
+		},
+		{
+			Name:    "parse-levels",
+			ExpResp: []pgx.TxIsoLevel{pgx.Serializable, pgx.RepeatableRead, pgx.ReadCommitted, pgx.RepeatableRead},
+			ExcFunc: func(ctx context.Context) any {
+				var levels []pgx.TxIsoLevel
+				for _, level := range []string{"serializable", "repeatable-read", "read_committed", "Repeatable Read"} {
+					iso, err := transferdb.ParseIsoLevel(level)
+					if err != nil {
+						return err
+					}
+					levels = append(levels, iso)
+				}
+				return levels
+			},
+			CmpFunc: cmpResult,
+		},
+		{
+			Name:    "parse-unknown-level",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := transferdb.ParseIsoLevel("snapshot")
+				return err != nil
+			},
+			CmpFunc: cmpResult,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,284 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func limits(db *dbtest.Database) []unittest.Table {
+	// newAccounts opens accounts of the type with 1000 each
+	newAccounts := func(ctx context.Context, accountType transferbus.AccountType, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(1000),
+				Type:           accountType,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	transfer := func(ctx context.Context, srcID int64, dstID int64, amount int64) error {
+		_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(amount),
+		})
+		return err
+	}
+
+	cmpError := func(got any, exp any) string {
+		return cmp.Diff(fmt.Sprint(got), fmt.Sprint(exp))
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "maxamount",
+			ExpResp: "transfer limit exceeded: single transfer limit 50",
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, transferbus.AccountTypeCustomer, 15000, 15001); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15000, transferbus.TransferLimits{
+					MaxAmount: decimal.NewFromInt(50),
+				})
+				if err != nil {
+					return err
+				}
+
+				if err := transfer(ctx, 15000, 15001, 50); err != nil {
+					return err
+				}
+				return transfer(ctx, 15000, 15001, 51)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "daily",
+			ExpResp: "transfer limit exceeded: daily limit 100, 40 left",
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, transferbus.AccountTypeCustomer, 15002, 15003); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15002, transferbus.TransferLimits{
+					DailyAmount:   decimal.NewFromInt(100),
+					MonthlyAmount: decimal.NewFromInt(500),
+				})
+				if err != nil {
+					return err
+				}
+
+				if err := transfer(ctx, 15002, 15003, 60); err != nil {
+					return err
+				}
+				return transfer(ctx, 15002, 15003, 41)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "incoming",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				// limits only cap what leaves the account
+				return transfer(ctx, 15003, 15002, 500)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "accounttype",
+			ExpResp: "transfer limit exceeded: single transfer limit 10",
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, transferbus.AccountTypeSuspense, 15004, 15005); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.SetAccountTypeLimits(ctx, transferbus.AccountTypeSuspense, currency.USD, transferbus.TransferLimits{
+					MaxAmount: decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+
+				return transfer(ctx, 15004, 15005, 11)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "accounttypecurrency",
+			ExpResp: "transfer limit exceeded: single transfer limit 1000",
+			ExcFunc: func(ctx context.Context) any {
+				for _, id := range []int64{15006, 15007} {
+					_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+						AccountID:      id,
+						Currency:       currency.JPY,
+						InitialBalance: decimal.NewFromInt(10000),
+						Type:           transferbus.AccountTypeSuspense,
+					})
+					if err != nil {
+						return err
+					}
+				}
+
+				// the USD limit of the type does not cap amounts in yen
+				if err := transfer(ctx, 15006, 15007, 11); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.SetAccountTypeLimits(ctx, transferbus.AccountTypeSuspense, currency.JPY, transferbus.TransferLimits{
+					MaxAmount: decimal.NewFromInt(1000),
+				})
+				if err != nil {
+					return err
+				}
+
+				if err := transfer(ctx, 15006, 15007, 1000); err != nil {
+					return err
+				}
+				exceeded := transfer(ctx, 15006, 15007, 1001)
+
+				if _, err := db.BusDomain.TransferBus.SetAccountTypeLimits(ctx, transferbus.AccountTypeSuspense, currency.JPY, transferbus.TransferLimits{}); err != nil {
+					return err
+				}
+				return exceeded
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "accountoverridestype",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15004, transferbus.TransferLimits{
+					MaxAmount: decimal.NewFromInt(100),
+				})
+				if err != nil {
+					return err
+				}
+
+				if err := transfer(ctx, 15004, 15005, 11); err != nil {
+					return err
+				}
+
+				// lift the type limit again for the accounts of the other tests
+				_, err = db.BusDomain.TransferBus.SetAccountTypeLimits(ctx, transferbus.AccountTypeSuspense, currency.USD, transferbus.TransferLimits{})
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "negative",
+			ExpResp: transferbus.ErrNegativeLimit,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15000, transferbus.TransferLimits{
+					DailyAmount: decimal.NewFromInt(-1),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "accountnotfound",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15999, transferbus.TransferLimits{})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "unknowntype",
+			ExpResp: transferbus.ErrUnknownAccountType,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.SetAccountTypeLimits(ctx, "savings", currency.USD, transferbus.TransferLimits{})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "capture",
+			ExpResp: "transfer limit exceeded: single transfer limit 50",
+			ExcFunc: func(ctx context.Context) any {
+				// holds reserve funds without spending them, the capture does
+				hold, err := db.BusDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+					SourceAccountID:      15000,
+					DestinationAccountID: 15001,
+					Amount:               decimal.NewFromInt(51),
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.Capture(ctx, transferbus.HoldCapture{HoldID: hold.HoldID})
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "frozen",
+			ExpResp: "account[15010]: account is frozen",
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, transferbus.AccountTypeCustomer, 15010, 15011); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15010, transferbus.TransferLimits{
+					MaxAmount: decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.FreezeAccount(ctx, 15010); err != nil {
+					return err
+				}
+				return transfer(ctx, 15010, 15011, 11)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "reversal",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, transferbus.AccountTypeCustomer, 15012, 15013); err != nil {
+					return err
+				}
+
+				_, err := db.BusDomain.TransferBus.SetAccountLimits(ctx, 15013, transferbus.TransferLimits{
+					DailyAmount: decimal.NewFromInt(100),
+				})
+				if err != nil {
+					return err
+				}
+
+				sent, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      15012,
+					DestinationAccountID: 15013,
+					Amount:               decimal.NewFromInt(60),
+				})
+				if err != nil {
+					return err
+				}
+
+				// sending back funds received does not spend the daily limit
+				if _, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{TransferID: sent.TransferID}); err != nil {
+					return err
+				}
+				return transfer(ctx, 15013, 15012, 100)
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"testing"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// legacyVersion is the last migration before transactions were linked into
+// transfers, whose rows are seeded the way the first release wrote them.
+const legacyVersion = 2
+
+func Test_Migration(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDatabaseAt(t, c, "Test_Migration", legacyVersion)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		db.Teardown()
+	}()
+
+	if err := legacySeedData(db); err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrating error: %s", err)
+	}
+
+	unittest.Run(t, legacyMigration(db), "legacy-migration")
+}
+
+// legacySeedData records initial balances as a single credit and transfers as
+// a debit followed by a credit, without linking the rows. Accounts 1 and 2 both
+// send 30, and account 3 holds a credit nothing was debited for.
+func legacySeedData(db *dbtest.Database) error {
+	ctx := context.Background()
+
+	const q = `
+	INSERT INTO accounts (account_id, balance) VALUES (1, 60), (2, 50), (3, 45);
+
+	INSERT INTO transactions (account_id, amount, created_date)
+	SELECT account_id, amount, TIMESTAMPTZ '2024-01-01 00:00:00+00' + n * INTERVAL '1 second'
+	FROM (VALUES
+		(0, 1, 100), (1, 2, 50), (2, 3, 0),
+		(3, 1, -30), (4, 2, 30),
+		(5, 2, -30), (6, 3, 30),
+		(7, 1, -10), (8, 3, 10),
+		(9, 3, 5)
+	) AS rows (n, account_id, amount)
+	ORDER BY n;`
+
+	if _, err := db.DB.Exec(ctx, q); err != nil {
+		return fmt.Errorf("seeding legacy rows: %w", err)
+	}
+	return nil
+}
+
+func legacyMigration(db *dbtest.Database) []unittest.Table {
+	type entry struct {
+		EntryType   string
+		Source      int64
+		Destination int64
+		Amount      string
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "journalentries",
+			ExpResp: []entry{
+				{EntryType: "opening_balance", Source: -1, Destination: 1, Amount: "100"},
+				{EntryType: "opening_balance", Source: -1, Destination: 2, Amount: "50"},
+				{EntryType: "opening_balance", Source: -1, Destination: 3, Amount: "0"},
+				{EntryType: "transfer", Source: 1, Destination: 2, Amount: "30"},
+				{EntryType: "transfer", Source: 2, Destination: 3, Amount: "30"},
+				{EntryType: "transfer", Source: 1, Destination: 3, Amount: "10"},
+				{EntryType: "legacy", Source: -1, Destination: 3, Amount: "5"},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				rows, err := db.DB.Query(ctx, `
+					SELECT entry_type, source_account_id, destination_account_id, amount
+					FROM journal_entries
+					ORDER BY created_date`)
+				if err != nil {
+					return err
+				}
+				defer rows.Close()
+
+				var entries []entry
+				for rows.Next() {
+					var e entry
+					var amount decimal.Decimal
+					if err := rows.Scan(&e.EntryType, &e.Source, &e.Destination, &amount); err != nil {
+						return err
+					}
+					e.Amount = amount.String()
+					entries = append(entries, e)
+				}
+				if err := rows.Err(); err != nil {
+					return err
+				}
+				return entries
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "balanced",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				result, err := db.BusDomain.TransferBus.Reconcile(ctx, transferbus.ReconcileConfig{})
+				if err != nil {
+					return err
+				}
+				return result.Balanced()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reversible",
+			ExpResp: decimal.NewFromInt(90),
+			ExcFunc: func(ctx context.Context) any {
+				var transferID uuid.UUID
+				err := db.DB.QueryRow(ctx, `
+					SELECT transfer_id
+					FROM journal_entries
+					WHERE entry_type = 'transfer' AND source_account_id = 1 AND destination_account_id = 2`).Scan(&transferID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{TransferID: transferID}); err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.GetBalance(ctx, 1)
+				if err != nil {
+					return err
+				}
+				return acc.Balance
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(decimal.Decimal)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+				return cmp.Diff(gotResp.String(), exp.(decimal.Decimal).String())
+			},
+		},
+	}
+
+	return table
+}
+
+// preSequenceVersion is the last migration before the service allocated
+// account ids itself.
+const preSequenceVersion = 11
+
+func Test_AccountIDSequenceMigration(t *testing.T) {
+	t.Parallel()
+	db := dbtest.NewDatabaseAt(t, c, "Test_AccountIDSequenceMigration", preSequenceVersion)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		db.Teardown()
+	}()
+
+	// a client picked an id from the range the service allocates from
+	const q = `INSERT INTO accounts (account_id, balance) VALUES (1000000000005, 0);`
+	if _, err := db.DB.Exec(context.Background(), q); err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrating error: %s", err)
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "allocatedafterexisting",
+			ExpResp: int64(1000000000006),
+			ExcFunc: func(ctx context.Context) any {
+				acc, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{})
+				if err != nil {
+					return err
+				}
+				return acc.AccountID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	unittest.Run(t, table, "account-id-sequence")
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func overdraft(db *dbtest.Database) []unittest.Table {
+	// newAccounts opens accounts with 100 each
+	newAccounts := func(ctx context.Context, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	transfer := func(ctx context.Context, srcID int64, dstID int64, amount int64) error {
+		_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(amount),
+		})
+		return err
+	}
+
+	setLimit := func(ctx context.Context, accountID int64, limit int64) (transferbus.Account, error) {
+		return db.BusDomain.TransferBus.SetOverdraftLimit(ctx, accountID, decimal.NewFromInt(limit))
+	}
+
+	type accountResult struct {
+		Balance         decimal.Decimal
+		OverdraftLimit  decimal.Decimal
+		AvailableCredit decimal.Decimal
+	}
+
+	cmpAccount := func(got any, exp any) string {
+		gotResp, exists := got.(transferbus.Account)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(accountResult{
+			Balance:         gotResp.Balance,
+			OverdraftLimit:  gotResp.OverdraftLimit,
+			AvailableCredit: gotResp.AvailableCredit(),
+		}, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "setlimit",
+			ExpResp: accountResult{
+				Balance:         decimal.NewFromInt(100),
+				OverdraftLimit:  decimal.NewFromInt(50),
+				AvailableCredit: decimal.NewFromInt(150),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 14000, 14001); err != nil {
+					return err
+				}
+
+				acc, err := setLimit(ctx, 14000, 50)
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpAccount,
+		},
+		{
+			Name: "debitintooverdraft",
+			ExpResp: accountResult{
+				Balance:         decimal.NewFromInt(-30),
+				OverdraftLimit:  decimal.NewFromInt(50),
+				AvailableCredit: decimal.NewFromInt(20),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := transfer(ctx, 14000, 14001, 130); err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.GetBalance(ctx, 14000)
+				if err != nil {
+					return err
+				}
+				return acc
+			},
+			CmpFunc: cmpAccount,
+		},
+		{
+			Name:    "beyondoverdraft",
+			ExpResp: "insufficient funds: account[14000]: available credit 20",
+			ExcFunc: func(ctx context.Context) any {
+				return transfer(ctx, 14000, 14001, 21)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(fmt.Sprint(got), exp)
+			},
+		},
+		{
+			Name:    "limitinuse",
+			ExpResp: transferbus.ErrOverdraftInUse,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := setLimit(ctx, 14000, 29)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "negativelimit",
+			ExpResp: transferbus.ErrNegativeOverdraft,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := setLimit(ctx, 14000, -1)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "notfound",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := setLimit(ctx, 14999, 10)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "nolimit",
+			ExpResp: "insufficient funds: account[14001]: available credit 230",
+			ExcFunc: func(ctx context.Context) any {
+				return transfer(ctx, 14001, 14000, 231)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(fmt.Sprint(got), exp)
+			},
+		},
+		{
+			Name:    "limitheld",
+			ExpResp: transferbus.ErrOverdraftHeld,
+			ExcFunc: func(ctx context.Context) any {
+				// the hold leaves 5 of the overdraft available
+				_, err := db.BusDomain.TransferBus.AuthorizeTransfer(ctx, transferbus.NewHold{
+					SourceAccountID:      14000,
+					DestinationAccountID: 14001,
+					Amount:               decimal.NewFromInt(15),
+					ExpiresIn:            time.Hour,
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = setLimit(ctx, 14000, 40)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+	}
+
+	return table
+}
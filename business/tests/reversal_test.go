@@ -0,0 +1,342 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func reversal(db *dbtest.Database) []unittest.Table {
+	newAccount := func(ctx context.Context, accountID int64, cur currency.Currency, balance int64) (transferbus.Account, error) {
+		return db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+			AccountID:      accountID,
+			Currency:       cur,
+			InitialBalance: decimal.NewFromInt(balance),
+		})
+	}
+
+	// transfer moves amount between two new accounts opened with 100 each
+	transfer := func(ctx context.Context, srcID int64, dstID int64, amount int64) (transferbus.Transfer, error) {
+		for _, id := range []int64{srcID, dstID} {
+			if _, err := newAccount(ctx, id, currency.USD, 100); err != nil {
+				return transferbus.Transfer{}, err
+			}
+		}
+
+		return db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(amount),
+		})
+	}
+
+	balances := func(ctx context.Context, accountIDs ...int64) ([]decimal.Decimal, error) {
+		result := make([]decimal.Decimal, len(accountIDs))
+		for i, id := range accountIDs {
+			acc, err := db.BusDomain.TransferBus.GetBalance(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = acc.Balance
+		}
+		return result, nil
+	}
+
+	type reversalResult struct {
+		EntryType         transferbus.EntryType
+		SourceAccountID   int64
+		Amounts           []decimal.Decimal
+		DestinationAmount decimal.Decimal
+		Balances          []decimal.Decimal
+	}
+
+	cmpReversal := func(got any, exp any) string {
+		gotResp, exists := got.(reversalResult)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "partial",
+			ExpResp: reversalResult{
+				EntryType:         transferbus.EntryTypeReversal,
+				SourceAccountID:   8001,
+				Amounts:           []decimal.Decimal{decimal.NewFromInt(10)},
+				DestinationAmount: decimal.NewFromInt(10),
+				Balances:          []decimal.Decimal{decimal.NewFromInt(80), decimal.NewFromInt(120)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				original, err := transfer(ctx, 8000, 8001, 30)
+				if err != nil {
+					return err
+				}
+
+				rev, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+					Amount:     decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+				if rev.ReversalOf != original.TransferID {
+					return fmt.Errorf("reversal of %s, expected %s", rev.ReversalOf, original.TransferID)
+				}
+
+				bals, err := balances(ctx, 8000, 8001)
+				if err != nil {
+					return err
+				}
+
+				return reversalResult{
+					EntryType:         rev.EntryType,
+					SourceAccountID:   rev.SourceAccountID,
+					Amounts:           []decimal.Decimal{rev.Amount},
+					DestinationAmount: rev.DestinationAmount,
+					Balances:          bals,
+				}
+			},
+			CmpFunc: cmpReversal,
+		},
+		{
+			Name: "remainder",
+			ExpResp: reversalResult{
+				EntryType:         transferbus.EntryTypeReversal,
+				SourceAccountID:   8003,
+				Amounts:           []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(20)},
+				DestinationAmount: decimal.NewFromInt(20),
+				Balances:          []decimal.Decimal{decimal.NewFromInt(100), decimal.NewFromInt(100)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				original, err := transfer(ctx, 8002, 8003, 30)
+				if err != nil {
+					return err
+				}
+
+				first, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+					Amount:     decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+
+				// a zero amount reverses what is left of the transfer
+				rest, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+				})
+				if err != nil {
+					return err
+				}
+
+				bals, err := balances(ctx, 8002, 8003)
+				if err != nil {
+					return err
+				}
+
+				return reversalResult{
+					EntryType:         rest.EntryType,
+					SourceAccountID:   rest.SourceAccountID,
+					Amounts:           []decimal.Decimal{first.Amount, rest.Amount},
+					DestinationAmount: rest.DestinationAmount,
+					Balances:          bals,
+				}
+			},
+			CmpFunc: cmpReversal,
+		},
+		{
+			Name:    "exceeded",
+			ExpResp: transferbus.ErrReversalExceeded,
+			ExcFunc: func(ctx context.Context) any {
+				original, err := transfer(ctx, 8004, 8005, 10)
+				if err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+					Amount:     decimal.NewFromInt(11),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "zeroamount",
+			ExpResp: transferbus.ErrZeroAmount,
+			ExcFunc: func(ctx context.Context) any {
+				original, err := transfer(ctx, 8020, 8021, 10)
+				if err != nil {
+					return err
+				}
+
+				// 0.001 rounds to nothing at the two decimals of USD
+				_, err = db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+					Amount:     decimal.RequireFromString("0.001"),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "fullyreversed",
+			ExpResp: transferbus.ErrReversalExceeded,
+			ExcFunc: func(ctx context.Context) any {
+				original, err := transfer(ctx, 8006, 8007, 10)
+				if err != nil {
+					return err
+				}
+
+				r := transferbus.Reversal{TransferID: original.TransferID}
+				if _, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, r); err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.ReverseTransfer(ctx, r)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "fundsspent",
+			ExpResp: transferbus.ErrInsufficientFunds,
+			ExcFunc: func(ctx context.Context) any {
+				original, err := transfer(ctx, 8008, 8009, 50)
+				if err != nil {
+					return err
+				}
+
+				// the destination spends everything it holds
+				_, err = db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      8009,
+					DestinationAccountID: 8008,
+					Amount:               decimal.NewFromInt(150),
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "notreversible",
+			ExpResp: transferbus.ErrNotReversible,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := newAccount(ctx, 8010, currency.USD, 100); err != nil {
+					return err
+				}
+
+				legs, err := db.BusDomain.TransferBus.QueryAccountTransactions(ctx, transferbus.TransactionFilter{
+					AccountID: 8010,
+					Limit:     1,
+				})
+				if err != nil {
+					return err
+				}
+
+				// the only leg of a new account is its opening balance
+				_, err = db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: legs[0].TransferID,
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "transfernotfound",
+			ExpResp: transferbus.ErrTransferNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: uuid.New(),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name: "fxtransfer",
+			ExpResp: reversalResult{
+				EntryType:         transferbus.EntryTypeReversal,
+				SourceAccountID:   8012,
+				Amounts:           []decimal.Decimal{decimal.RequireFromString("3.6"), decimal.RequireFromString("5.4")},
+				DestinationAmount: decimal.NewFromInt(6),
+				Balances:          []decimal.Decimal{decimal.NewFromInt(100), decimal.NewFromInt(100)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := newAccount(ctx, 8011, currency.USD, 100); err != nil {
+					return err
+				}
+				if _, err := newAccount(ctx, 8012, currency.EUR, 100); err != nil {
+					return err
+				}
+
+				quote, err := db.BusDomain.TransferBus.CreateQuote(ctx, transferbus.NewQuote{
+					SourceAccountID:      8011,
+					DestinationAccountID: 8012,
+					Amount:               decimal.NewFromInt(10),
+				})
+				if err != nil {
+					return err
+				}
+
+				original, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      8011,
+					DestinationAccountID: 8012,
+					Amount:               decimal.NewFromInt(10),
+					QuoteID:              quote.QuoteID,
+				})
+				if err != nil {
+					return err
+				}
+
+				// partial reversals are converted back at the rate of the quote
+				first, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+					Amount:     decimal.NewFromInt(4),
+				})
+				if err != nil {
+					return err
+				}
+
+				rest, err := db.BusDomain.TransferBus.ReverseTransfer(ctx, transferbus.Reversal{
+					TransferID: original.TransferID,
+				})
+				if err != nil {
+					return err
+				}
+
+				bals, err := balances(ctx, 8011, 8012)
+				if err != nil {
+					return err
+				}
+
+				return reversalResult{
+					EntryType:         rest.EntryType,
+					SourceAccountID:   rest.SourceAccountID,
+					Amounts:           []decimal.Decimal{first.Amount, rest.Amount},
+					DestinationAmount: rest.DestinationAmount,
+					Balances:          bals,
+				}
+			},
+			CmpFunc: cmpReversal,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,338 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func scheduledTransfers(db *dbtest.Database) []unittest.Table {
+	// newAccounts opens accounts with 100 each
+	newAccounts := func(ctx context.Context, accountIDs ...int64) error {
+		for _, id := range accountIDs {
+			_, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// schedule creates a transfer that is already due and runs the scheduler
+	schedule := func(ctx context.Context, srcID int64, dstID int64, amount int64, recurrence string) (transferbus.ScheduledTransfer, error) {
+		st, err := db.BusDomain.TransferBus.CreateScheduledTransfer(ctx, transferbus.NewScheduledTransfer{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(amount),
+			Recurrence:           recurrence,
+			StartDate:            time.Now().Add(-time.Minute),
+		})
+		if err != nil {
+			return transferbus.ScheduledTransfer{}, err
+		}
+
+		if _, err := db.BusDomain.TransferBus.RunScheduledTransfers(ctx); err != nil {
+			return transferbus.ScheduledTransfer{}, err
+		}
+
+		return db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, st.ScheduleID)
+	}
+
+	type runResult struct {
+		Status    transferbus.ScheduleStatus
+		RunCount  int
+		LastError string
+		Balance   decimal.Decimal
+	}
+
+	// cmpRun compares the outcome of the run and the balance of the destination
+	cmpRun := func(got any, exp any) string {
+		gotResp, exists := got.(runResult)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+		return cmp.Diff(gotResp, exp)
+	}
+
+	run := func(ctx context.Context, st transferbus.ScheduledTransfer) any {
+		acc, err := db.BusDomain.TransferBus.GetBalance(ctx, st.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+		return runResult{
+			Status:    st.Status,
+			RunCount:  st.RunCount,
+			LastError: st.LastError,
+			Balance:   acc.Balance,
+		}
+	}
+
+	var recurring transferbus.ScheduledTransfer
+
+	table := []unittest.Table{
+		{
+			Name: "oneoff",
+			ExpResp: runResult{
+				Status:   transferbus.ScheduleStatusCompleted,
+				RunCount: 1,
+				Balance:  decimal.NewFromInt(110),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 16000, 16001); err != nil {
+					return err
+				}
+
+				st, err := schedule(ctx, 16000, 16001, 10, "")
+				if err != nil {
+					return err
+				}
+				return run(ctx, st)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name: "recurring",
+			ExpResp: runResult{
+				Status:   transferbus.ScheduleStatusScheduled,
+				RunCount: 1,
+				Balance:  decimal.NewFromInt(120),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				var err error
+				if recurring, err = schedule(ctx, 16000, 16001, 10, "@daily"); err != nil {
+					return err
+				}
+
+				// the next run is the next midnight in UTC
+				now := time.Now().UTC()
+				next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+				if !recurring.NextRunDate.Equal(next) {
+					return fmt.Errorf("next run %s, expected %s", recurring.NextRunDate, next)
+				}
+				return run(ctx, recurring)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name: "notdue",
+			ExpResp: runResult{
+				Status:   transferbus.ScheduleStatusScheduled,
+				RunCount: 1,
+				Balance:  decimal.NewFromInt(120),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := db.BusDomain.TransferBus.RunScheduledTransfers(ctx); err != nil {
+					return err
+				}
+
+				st, err := db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, recurring.ScheduleID)
+				if err != nil {
+					return err
+				}
+				return run(ctx, st)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name: "failed",
+			ExpResp: runResult{
+				Status:    transferbus.ScheduleStatusFailed,
+				RunCount:  1,
+				LastError: "insufficient funds: account[16002]: available credit 100",
+				Balance:   decimal.NewFromInt(100),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 16002, 16003); err != nil {
+					return err
+				}
+
+				st, err := schedule(ctx, 16002, 16003, 101, "")
+				if err != nil {
+					return err
+				}
+				return run(ctx, st)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name: "update",
+			ExpResp: runResult{
+				Status:   transferbus.ScheduleStatusCompleted,
+				RunCount: 2,
+				Balance:  decimal.NewFromInt(145),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				// turning the recurring transfer into a one off that is due now
+				amount := decimal.NewFromInt(25)
+				oneOff := ""
+				due := time.Now().Add(-time.Minute)
+				_, err := db.BusDomain.TransferBus.UpdateScheduledTransfer(ctx, recurring.ScheduleID, transferbus.UpdateScheduledTransfer{
+					Amount:      &amount,
+					Recurrence:  &oneOff,
+					NextRunDate: &due,
+				})
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.RunScheduledTransfers(ctx); err != nil {
+					return err
+				}
+
+				st, err := db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, recurring.ScheduleID)
+				if err != nil {
+					return err
+				}
+				return run(ctx, st)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name: "cancelled",
+			ExpResp: runResult{
+				Status:  transferbus.ScheduleStatusCancelled,
+				Balance: decimal.NewFromInt(100),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				st, err := db.BusDomain.TransferBus.CreateScheduledTransfer(ctx, transferbus.NewScheduledTransfer{
+					SourceAccountID:      16003,
+					DestinationAccountID: 16002,
+					Amount:               decimal.NewFromInt(10),
+					StartDate:            time.Now().Add(-time.Minute),
+				})
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.CancelScheduledTransfer(ctx, st.ScheduleID); err != nil {
+					return err
+				}
+
+				if _, err := db.BusDomain.TransferBus.RunScheduledTransfers(ctx); err != nil {
+					return err
+				}
+
+				st, err = db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, st.ScheduleID)
+				if err != nil {
+					return err
+				}
+				return run(ctx, st)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name: "concurrent",
+			ExpResp: runResult{
+				Status:   transferbus.ScheduleStatusCompleted,
+				RunCount: 1,
+				Balance:  decimal.NewFromInt(95),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := newAccounts(ctx, 16004, 16005); err != nil {
+					return err
+				}
+
+				// transfers in opposite directions run by two schedulers at once
+				var schedules []transferbus.ScheduledTransfer
+				for _, ns := range []transferbus.NewScheduledTransfer{
+					{SourceAccountID: 16004, DestinationAccountID: 16005, Amount: decimal.NewFromInt(10)},
+					{SourceAccountID: 16005, DestinationAccountID: 16004, Amount: decimal.NewFromInt(15)},
+				} {
+					ns.StartDate = time.Now().Add(-time.Minute)
+					st, err := db.BusDomain.TransferBus.CreateScheduledTransfer(ctx, ns)
+					if err != nil {
+						return err
+					}
+					schedules = append(schedules, st)
+				}
+
+				errs := make(chan error, 2)
+				for range 2 {
+					go func() {
+						_, err := db.BusDomain.TransferBus.RunScheduledTransfers(ctx)
+						errs <- err
+					}()
+				}
+				for range 2 {
+					if err := <-errs; err != nil {
+						return err
+					}
+				}
+
+				for _, st := range schedules {
+					st, err := db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, st.ScheduleID)
+					if err != nil {
+						return err
+					}
+					if st.Status != transferbus.ScheduleStatusCompleted {
+						return fmt.Errorf("schedule[%s]: status %s", st.ScheduleID, st.Status)
+					}
+				}
+
+				st, err := db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, schedules[0].ScheduleID)
+				if err != nil {
+					return err
+				}
+				return run(ctx, st)
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name:    "closed",
+			ExpResp: transferbus.ErrScheduleClosed,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CancelScheduledTransfer(ctx, recurring.ScheduleID)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "invalidrecurrence",
+			ExpResp: transferbus.ErrInvalidRecurrence,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CreateScheduledTransfer(ctx, transferbus.NewScheduledTransfer{
+					SourceAccountID:      16000,
+					DestinationAccountID: 16001,
+					Amount:               decimal.NewFromInt(10),
+					Recurrence:           "0 0 30 2 *",
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "accountnotfound",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.CreateScheduledTransfer(ctx, transferbus.NewScheduledTransfer{
+					SourceAccountID:      16000,
+					DestinationAccountID: 16999,
+					Amount:               decimal.NewFromInt(10),
+				})
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "notfound",
+			ExpResp: transferbus.ErrScheduleNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.QueryScheduledTransfer(ctx, uuid.New())
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func accountStreams(db *dbtest.Database) []unittest.Table {
+	// balances returns the balance after every event of the account following
+	// afterEventID
+	balances := func(ctx context.Context, accountID int64, afterEventID int64) ([]string, error) {
+		events, err := db.BusDomain.TransferBus.QueryAccountEvents(ctx, accountID, afterEventID, 100)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := make([]string, len(events))
+		for i, event := range events {
+			resp[i] = event.Balance.String()
+		}
+		return resp, nil
+	}
+
+	cmpBalances := func(got any, exp any) string {
+		gotResp, exists := got.([]string)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "notified",
+			ExpResp: []string{"90"},
+			ExcFunc: func(ctx context.Context) any {
+				for _, id := range []int64{19000, 19001} {
+					if _, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+						AccountID:      id,
+						InitialBalance: decimal.NewFromInt(100),
+					}); err != nil {
+						return err
+					}
+				}
+
+				last, err := db.BusDomain.TransferBus.QueryLastAccountEvent(ctx, 19000)
+				if err != nil {
+					return err
+				}
+				if !last.Balance.Equal(decimal.NewFromInt(100)) {
+					return fmt.Errorf("last event balance %s, expected the current balance 100", last.Balance)
+				}
+
+				notify, unsubscribe := db.BusDomain.TransferBus.SubscribeAccountEvents(19000)
+				defer unsubscribe()
+
+				listenCtx, stopListening := context.WithCancel(ctx)
+				defer stopListening()
+
+				listening := make(chan error, 1)
+				go func() {
+					listening <- db.BusDomain.TransferBus.ListenAccountEvents(listenCtx)
+				}()
+
+				// streams are woken up once listening starts
+				select {
+				case <-notify:
+				case err := <-listening:
+					return err
+				case <-time.After(5 * time.Second):
+					return fmt.Errorf("not woken up once listening")
+				}
+
+				if _, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      19000,
+					DestinationAccountID: 19001,
+					Amount:               decimal.NewFromInt(10),
+				}); err != nil {
+					return err
+				}
+
+				select {
+				case <-notify:
+				case err := <-listening:
+					return err
+				case <-time.After(5 * time.Second):
+					return fmt.Errorf("not notified of the transfer")
+				}
+
+				resp, err := balances(ctx, 19000, last.EventID)
+				if err != nil {
+					return err
+				}
+				return resp
+			},
+			CmpFunc: cmpBalances,
+		},
+		{
+			Name:    "resumed",
+			ExpResp: []string{"0", "100", "75"},
+			ExcFunc: func(ctx context.Context) any {
+				for _, id := range []int64{19002, 19003} {
+					if _, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+						AccountID:      id,
+						InitialBalance: decimal.NewFromInt(100),
+					}); err != nil {
+						return err
+					}
+				}
+
+				if _, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      19002,
+					DestinationAccountID: 19003,
+					Amount:               decimal.NewFromInt(25),
+				}); err != nil {
+					return err
+				}
+
+				// resuming from the start replays every balance change
+				resp, err := balances(ctx, 19002, 0)
+				if err != nil {
+					return err
+				}
+				return resp
+			},
+			CmpFunc: cmpBalances,
+		},
+	}
+
+	return table
+}
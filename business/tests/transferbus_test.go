@@ -10,8 +10,11 @@ import (
 
 	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
 	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
@@ -34,6 +37,23 @@ func Test_Transfer(t *testing.T) {
 	unittest.Run(t, accountCreation(db), "account-creation")
 	unittest.Run(t, accountQuery(db, sd), "account-query")
 	unittest.Run(t, transactionSubmission(db, sd), "transaction-submission")
+	unittest.Run(t, transactionQuery(db, sd), "transaction-query")
+	unittest.Run(t, ledger(db), "ledger")
+	unittest.Run(t, fxTransfer(db, sd), "fx-transfer")
+	unittest.Run(t, reversal(db), "reversal")
+	unittest.Run(t, holds(db), "holds")
+	unittest.Run(t, batch(db), "batch")
+	unittest.Run(t, accountLifecycle(db), "account-lifecycle")
+	unittest.Run(t, accountMetadata(db), "account-metadata")
+	unittest.Run(t, overdraft(db), "overdraft")
+	unittest.Run(t, limits(db), "limits")
+	unittest.Run(t, scheduledTransfers(db), "scheduled-transfers")
+	unittest.Run(t, events(db), "events")
+	unittest.Run(t, webhooks(db), "webhooks")
+	unittest.Run(t, accountStreams(db), "accountStreams")
+	unittest.Run(t, txRunner(db), "tx-runner")
+	unittest.Run(t, isolation(db), "isolation")
+	unittest.Run(t, reconciliation(db, sd), "reconciliation")
 }
 
 func accountSeedData(db *dbtest.Database) (dbtest.SeedData, error) {
@@ -69,12 +89,16 @@ func accountCreation(db *dbtest.Database) []unittest.Table {
 			Name: "basic",
 			ExpResp: transferbus.Account{
 				AccountID: 1,
-				Balance:   decimal.NewFromFloat(100.12345),
+				Currency:  currency.USD,
+				Balance:   decimal.NewFromFloat(100.12),
+				Status:    transferbus.AccountStatusActive,
+				Type:      transferbus.AccountTypeCustomer,
+				Labels:    []string{},
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nu := transferbus.NewAccount{
 					AccountID:      1,
-					InitialBalance: decimal.NewFromFloat(100.12345),
+					InitialBalance: decimal.NewFromFloat(100.12),
 				}
 
 				resp, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
@@ -102,12 +126,16 @@ func accountCreation(db *dbtest.Database) []unittest.Table {
 			Name: "correctlyroundup",
 			ExpResp: transferbus.Account{
 				AccountID: 2,
-				Balance:   decimal.NewFromFloat(100.12346),
+				Currency:  currency.USD,
+				Balance:   decimal.NewFromFloat(100.13),
+				Status:    transferbus.AccountStatusActive,
+				Type:      transferbus.AccountTypeCustomer,
+				Labels:    []string{},
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nu := transferbus.NewAccount{
 					AccountID:      2,
-					InitialBalance: decimal.NewFromFloat(100.1234599999),
+					InitialBalance: decimal.NewFromFloat(100.125),
 				}
 
 				resp, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
@@ -134,12 +162,16 @@ func accountCreation(db *dbtest.Database) []unittest.Table {
 			Name: "correctlyrounddown",
 			ExpResp: transferbus.Account{
 				AccountID: 3,
-				Balance:   decimal.NewFromFloat(100.12345),
+				Currency:  currency.USD,
+				Balance:   decimal.NewFromFloat(100.12),
+				Status:    transferbus.AccountStatusActive,
+				Type:      transferbus.AccountTypeCustomer,
+				Labels:    []string{},
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nu := transferbus.NewAccount{
 					AccountID:      3,
-					InitialBalance: decimal.NewFromFloat(100.1234549999),
+					InitialBalance: decimal.NewFromFloat(100.1249999),
 				}
 
 				resp, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
@@ -161,6 +193,84 @@ func accountCreation(db *dbtest.Database) []unittest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name: "currencyprecision",
+			ExpResp: transferbus.Account{
+				AccountID: 5,
+				Currency:  currency.JPY,
+				Balance:   decimal.NewFromInt(101),
+				Status:    transferbus.AccountStatusActive,
+				Type:      transferbus.AccountTypeCustomer,
+				Labels:    []string{},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nu := transferbus.NewAccount{
+					AccountID:      5,
+					Currency:       currency.JPY,
+					InitialBalance: decimal.NewFromFloat(100.5),
+				}
+
+				resp, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(transferbus.Account)
+				if !exists {
+					return "error occurred"
+				}
+				expResp := exp.(transferbus.Account)
+
+				expResp.CreatedDate = gotResp.CreatedDate
+				expResp.LastModifiedDate = gotResp.LastModifiedDate
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "allocatedid",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				nu := transferbus.NewAccount{
+					InitialBalance: decimal.NewFromInt(10),
+				}
+
+				first, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
+				if err != nil {
+					return err
+				}
+
+				second, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
+				if err != nil {
+					return err
+				}
+
+				return first.AccountID >= transferbus.FirstAllocatedAccountID && second.AccountID > first.AccountID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reservedid",
+			ExpResp: transferbus.ErrAccIDReserved,
+			ExcFunc: func(ctx context.Context) any {
+				nu := transferbus.NewAccount{
+					AccountID:      transferbus.FirstAllocatedAccountID,
+					InitialBalance: decimal.NewFromInt(10),
+				}
+
+				_, err := db.BusDomain.TransferBus.CreateAccount(ctx, nu)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(error).Error()
+				expResp := exp.(error).Error()
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
 		{
 			Name:    "negativebalance",
 			ExpResp: transferbus.ErrNegativeBalance,
@@ -231,6 +341,15 @@ func accountQuery(db *dbtest.Database, sd dbtest.SeedData) []unittest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "systemaccount",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := db.BusDomain.TransferBus.GetBalance(ctx, transferbus.EquityAccountID)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
 	}
 
 	return table
@@ -248,7 +367,7 @@ func transactionSubmission(db *dbtest.Database, sd dbtest.SeedData) []unittest.T
 		DestinationBalance decimal.Decimal
 	}
 
-	validAmount := decimal.NewFromFloat(12.12345)
+	validAmount := decimal.NewFromFloat(12.12)
 	exceedAmount := accs[0].Balance.Add(decimal.NewFromInt(100))
 
 	table := []unittest.Table{
@@ -264,7 +383,7 @@ func transactionSubmission(db *dbtest.Database, sd dbtest.SeedData) []unittest.T
 					DestinationAccountID: accs[1].AccountID,
 					Amount:               validAmount,
 				}
-				err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
 				if err != nil {
 					return err
 				}
@@ -295,15 +414,134 @@ func transactionSubmission(db *dbtest.Database, sd dbtest.SeedData) []unittest.T
 			},
 		},
 		{
-			Name:    "insufficientbalance",
-			ExpResp: transferbus.ErrInsufficientFunds,
+			Name: "transferidentity",
+			ExpResp: transferbus.Transfer{
+				EntryType:            transferbus.EntryTypeTransfer,
+				SourceAccountID:      accs[0].AccountID,
+				DestinationAccountID: accs[1].AccountID,
+				Amount:               validAmount,
+			},
 			ExcFunc: func(ctx context.Context) any {
 				r := transferbus.Transaction{
 					SourceAccountID:      accs[0].AccountID,
 					DestinationAccountID: accs[1].AccountID,
-					Amount:               exceedAmount,
+					Amount:               validAmount,
+				}
+				resp, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				if err != nil {
+					return err
+				}
+
+				// undo the transfer so the balances expected by the following cases hold
+				r.SourceAccountID, r.DestinationAccountID = r.DestinationAccountID, r.SourceAccountID
+				if _, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r); err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(transferbus.Transfer)
+				if !exists {
+					return "error occurred"
+				}
+				if gotResp.TransferID == uuid.Nil {
+					return "transfer id should be set"
+				}
+				if len(gotResp.Legs) != 2 {
+					return "transfer should have a debit and a credit leg"
+				}
+
+				expResp := exp.(transferbus.Transfer)
+				expResp.TransferID = gotResp.TransferID
+				expResp.CreatedDate = gotResp.CreatedDate
+				expResp.Legs = gotResp.Legs
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name: "idempotentreplay",
+			ExpResp: accountBalances{
+				SourceBalance:      accs[0].Account.Balance.Sub(validAmount.Mul(decimal.NewFromInt(2))),
+				DestinationBalance: accs[1].Account.Balance.Add(validAmount.Mul(decimal.NewFromInt(2))),
+			},
+			ExcFunc: func(ctx context.Context) any {
+				r := transferbus.Transaction{
+					SourceAccountID:      accs[0].AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               validAmount,
+					IdempotencyKey:       "idempotentreplay",
 				}
-				err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+
+				original, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				if err != nil {
+					return err
+				}
+
+				// another transfer moves the source balance away from the one the
+				// original transfer left, and is undone once the key is replayed
+				other := transferbus.Transaction{
+					SourceAccountID:      accs[0].AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               validAmount,
+				}
+				if _, err := db.BusDomain.TransferBus.CreateTransaction(ctx, other); err != nil {
+					return err
+				}
+
+				// the replay must return the original transfer without moving the funds a second time
+				replay, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				if err != nil {
+					return err
+				}
+				if replay.TransferID != original.TransferID {
+					return fmt.Errorf("replay returned transfer %s, expected %s", replay.TransferID, original.TransferID)
+				}
+				if !replay.SourceBalance.Equal(original.SourceBalance) {
+					return fmt.Errorf("replay returned source balance %s, expected %s", replay.SourceBalance, original.SourceBalance)
+				}
+
+				other.SourceAccountID, other.DestinationAccountID = other.DestinationAccountID, other.SourceAccountID
+				if _, err := db.BusDomain.TransferBus.CreateTransaction(ctx, other); err != nil {
+					return err
+				}
+
+				acc1, err := db.BusDomain.TransferBus.GetBalance(ctx, accs[0].AccountID)
+				if err != nil {
+					return err
+				}
+
+				acc2, err := db.BusDomain.TransferBus.GetBalance(ctx, accs[1].AccountID)
+				if err != nil {
+					return err
+				}
+
+				return accountBalances{
+					SourceBalance:      acc1.Balance,
+					DestinationBalance: acc2.Balance,
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(accountBalances)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(accountBalances)
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "idempotencykeyreused",
+			ExpResp: transferbus.ErrIdempotencyKeyReused,
+			ExcFunc: func(ctx context.Context) any {
+				r := transferbus.Transaction{
+					SourceAccountID:      accs[0].AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               validAmount.Add(decimal.NewFromInt(1)),
+					IdempotencyKey:       "idempotentreplay",
+				}
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
 				if err != nil {
 					return err
 				}
@@ -315,6 +553,61 @@ func transactionSubmission(db *dbtest.Database, sd dbtest.SeedData) []unittest.T
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "insufficientbalance",
+			ExpResp: transferbus.ErrInsufficientFunds,
+			ExcFunc: func(ctx context.Context) any {
+				r := transferbus.Transaction{
+					SourceAccountID:      accs[0].AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               exceedAmount,
+				}
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				if err != nil {
+					return err
+				}
+				return nil
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "currencymismatch",
+			ExpResp: transferbus.ErrCurrencyMismatch,
+			ExcFunc: func(ctx context.Context) any {
+				eurAcc, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+					AccountID:      6000,
+					Currency:       currency.EUR,
+					InitialBalance: decimal.NewFromInt(100),
+				})
+				if err != nil {
+					return err
+				}
+
+				r := transferbus.Transaction{
+					SourceAccountID:      eurAcc.AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               validAmount,
+				}
+				_, err = db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
+		{
+			Name:    "transfercurrencymismatch",
+			ExpResp: transferbus.ErrCurrencyMismatch,
+			ExcFunc: func(ctx context.Context) any {
+				r := transferbus.Transaction{
+					SourceAccountID:      accs[0].AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               validAmount,
+					Currency:             currency.SGD,
+				}
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				return err
+			},
+			CmpFunc: cmpErrorIs,
+		},
 		{
 			Name:    "invalidaccountid",
 			ExpResp: transferbus.ErrAccNotFound,
@@ -330,7 +623,7 @@ func transactionSubmission(db *dbtest.Database, sd dbtest.SeedData) []unittest.T
 					DestinationAccountID: invalidUserID,
 					Amount:               validAmount,
 				}
-				err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
+				_, err := db.BusDomain.TransferBus.CreateTransaction(ctx, r)
 				if err != nil {
 					return err
 				}
@@ -342,6 +635,381 @@ func transactionSubmission(db *dbtest.Database, sd dbtest.SeedData) []unittest.T
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "sourcebalance",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				transfer, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      sd.Accounts[0].AccountID,
+					DestinationAccountID: sd.Accounts[1].AccountID,
+					Amount:               decimal.NewFromInt(1),
+				})
+				if err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.GetBalance(ctx, sd.Accounts[0].AccountID)
+				if err != nil {
+					return err
+				}
+
+				return transfer.SourceBalance.Equal(acc.Balance)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+	return table
+}
+
+func transactionQuery(db *dbtest.Database, sd dbtest.SeedData) []unittest.Table {
+	accs := sd.Accounts
+
+	sort.Slice(accs, func(i, j int) bool {
+		return accs[i].AccountID <= accs[j].AccountID
+	})
+
+	amount := decimal.NewFromFloat(1.5)
+
+	table := []unittest.Table{
+		{
+			Name: "transfer",
+			ExpResp: []decimal.Decimal{
+				amount.Neg(),
+				amount,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				transfer, err := db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+					SourceAccountID:      accs[0].AccountID,
+					DestinationAccountID: accs[1].AccountID,
+					Amount:               amount,
+				})
+				if err != nil {
+					return err
+				}
+
+				resp, err := db.BusDomain.TransferBus.QueryTransfer(ctx, transfer.TransferID)
+				if err != nil {
+					return err
+				}
+
+				amounts := make([]decimal.Decimal, len(resp.Legs))
+				for i, leg := range resp.Legs {
+					if leg.TransferID != transfer.TransferID {
+						return fmt.Errorf("leg %d belongs to transfer %s", leg.TransactionID, leg.TransferID)
+					}
+					amounts[i] = leg.Amount
+				}
+
+				return amounts
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]decimal.Decimal)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]decimal.Decimal)
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "transfernotfound",
+			ExpResp: transferbus.ErrTransferNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := db.BusDomain.TransferBus.QueryTransfer(ctx, uuid.New())
+				if err != nil {
+					return err
+				}
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(error)
+				if !exists {
+					return "expected an error"
+				}
+				return cmp.Diff(gotResp.Error(), exp.(error).Error())
+			},
+		},
+		{
+			Name:    "debitsonly",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				legs, err := db.BusDomain.TransferBus.QueryAccountTransactions(ctx, transferbus.TransactionFilter{
+					AccountID: accs[0].AccountID,
+					Direction: transferbus.DirectionDebit,
+					Limit:     10,
+				})
+				if err != nil {
+					return err
+				}
+				if len(legs) == 0 {
+					return fmt.Errorf("expected debits for account %d", accs[0].AccountID)
+				}
+
+				for _, leg := range legs {
+					if !leg.Amount.IsNegative() || leg.AccountID != accs[0].AccountID {
+						return false
+					}
+				}
+				return true
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "pagination",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				filter := transferbus.TransactionFilter{
+					AccountID: accs[1].AccountID,
+					Limit:     1,
+				}
+
+				all, err := db.BusDomain.TransferBus.QueryAccountTransactions(ctx, transferbus.TransactionFilter{
+					AccountID: accs[1].AccountID,
+					Limit:     100,
+				})
+				if err != nil {
+					return err
+				}
+
+				// walking the pages one leg at a time must yield the full history
+				var paged []transferbus.TransferLeg
+				for {
+					legs, err := db.BusDomain.TransferBus.QueryAccountTransactions(ctx, filter)
+					if err != nil {
+						return err
+					}
+					if len(legs) == 0 {
+						break
+					}
+					paged = append(paged, legs...)
+					filter.Cursor = legs[len(legs)-1].TransactionID
+				}
+
+				return len(all) > 1 && cmp.Equal(all, paged)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "accountnotfound",
+			ExpResp: transferbus.ErrAccNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				// declare the account id as the sum of all ids to guarantee id is not found
+				invalidAccountID := int64(0)
+				for _, u := range sd.Accounts {
+					invalidAccountID += u.AccountID
+				}
+
+				resp, err := db.BusDomain.TransferBus.QueryAccountTransactions(ctx, transferbus.TransactionFilter{
+					AccountID: invalidAccountID,
+					Limit:     10,
+				})
+				if err != nil {
+					return err
+				}
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(error)
+				if !exists {
+					return "expected an error"
+				}
+				return cmp.Diff(gotResp.Error(), exp.(error).Error())
+			},
+		},
 	}
+
+	return table
+}
+
+func ledger(db *dbtest.Database) []unittest.Table {
+	type openingBalance struct {
+		EquityDelta decimal.Decimal
+		EntryType   transferbus.EntryType
+		SourceID    int64
+		PostingSum  decimal.Decimal
+	}
+
+	initialBalance := decimal.NewFromInt(50)
+
+	// equityBalance reads the equity account from the store, as the bus does
+	// not show system accounts
+	store := transferdb.NewTxQueries(db.DB, transferdb.DefaultRetryConfig)
+	equityBalance := func(ctx context.Context) (decimal.Decimal, error) {
+		equity, err := store.GetAccount(ctx, transferbus.EquityAccountID)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		return equity.Balance, nil
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "openingbalance",
+			ExpResp: openingBalance{
+				EquityDelta: initialBalance.Neg(),
+				EntryType:   transferbus.EntryTypeOpeningBalance,
+				SourceID:    transferbus.EquityAccountID,
+				PostingSum:  decimal.Zero,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				before, err := equityBalance(ctx)
+				if err != nil {
+					return err
+				}
+
+				acc, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+					AccountID:      5000,
+					InitialBalance: initialBalance,
+				})
+				if err != nil {
+					return err
+				}
+
+				after, err := equityBalance(ctx)
+				if err != nil {
+					return err
+				}
+
+				legs, err := db.BusDomain.TransferBus.QueryAccountTransactions(ctx, transferbus.TransactionFilter{
+					AccountID: acc.AccountID,
+					Limit:     1,
+				})
+				if err != nil {
+					return err
+				}
+				if len(legs) != 1 {
+					return fmt.Errorf("expected the opening balance credit, got %d legs", len(legs))
+				}
+
+				transfer, err := db.BusDomain.TransferBus.QueryTransfer(ctx, legs[0].TransferID)
+				if err != nil {
+					return err
+				}
+
+				sum := decimal.Zero
+				for _, leg := range transfer.Legs {
+					sum = sum.Add(leg.Amount)
+				}
+
+				return openingBalance{
+					EquityDelta: after.Sub(before),
+					EntryType:   transfer.EntryType,
+					SourceID:    transfer.SourceAccountID,
+					PostingSum:  sum,
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(openingBalance)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(openingBalance)
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func reconciliation(db *dbtest.Database, sd dbtest.SeedData) []unittest.Table {
+	type result struct {
+		Drifts       []transferbus.AccountDrift
+		TotalBalance decimal.Decimal
+	}
+
+	cur := sd.Accounts[0].Account.Currency.String()
+
+	driftedAcc := sd.Accounts[0].Account
+	driftAmount := decimal.NewFromInt(7)
+
+	table := []unittest.Table{
+		{
+			Name: "balanced",
+			ExpResp: result{
+				Drifts:       []transferbus.AccountDrift{},
+				TotalBalance: decimal.Zero,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := db.BusDomain.TransferBus.Reconcile(ctx, transferbus.ReconcileConfig{BatchSize: 1})
+				if err != nil {
+					return err
+				}
+				if !resp.Balanced() {
+					return fmt.Errorf("expected a balanced ledger")
+				}
+
+				return result{
+					Drifts:       resp.Drifts,
+					TotalBalance: resp.TotalBalances[cur],
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(result)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(result)
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name: "drift",
+			ExpResp: result{
+				Drifts: []transferbus.AccountDrift{
+					{
+						AccountID: driftedAcc.AccountID,
+						Currency:  cur,
+						Drift:     driftAmount,
+					},
+				},
+				TotalBalance: driftAmount,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				// bypass the ledger to corrupt a balance
+				const q = `UPDATE accounts SET balance = balance + $1 WHERE account_id = $2`
+				if _, err := db.DB.Exec(ctx, q, driftAmount, driftedAcc.AccountID); err != nil {
+					return err
+				}
+
+				resp, err := db.BusDomain.TransferBus.Reconcile(ctx, transferbus.ReconcileConfig{BatchSize: 1})
+				if err != nil {
+					return err
+				}
+
+				return result{
+					Drifts:       resp.Drifts,
+					TotalBalance: resp.TotalBalances[cur],
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(result)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(result)
+				if len(gotResp.Drifts) != len(expResp.Drifts) {
+					return cmp.Diff(gotResp, expResp)
+				}
+				for i := range gotResp.Drifts {
+					expResp.Drifts[i].Balance = gotResp.Drifts[i].Balance
+					expResp.Drifts[i].LedgerBalance = gotResp.Drifts[i].Balance.Sub(expResp.Drifts[i].Drift)
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
 	return table
 }
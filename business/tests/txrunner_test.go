@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func txRunner(db *dbtest.Database) []unittest.Table {
+	store := transferdb.NewTxQueries(db.DB, transferdb.RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	})
+
+	errNotRetryable := errors.New("not retryable")
+
+	type runResult struct {
+		Attempts  int
+		Exhausted bool
+		Err       bool
+	}
+
+	// run fails the first failures attempts with err and succeeds afterwards
+	run := func(ctx context.Context, failures int, err error) any {
+		var got runResult
+		runErr := store.InTx(ctx, pgx.TxOptions{}, func(dbtx transferdb.TxQuerier) error {
+			got.Attempts++
+			if got.Attempts <= failures {
+				return err
+			}
+			return nil
+		})
+		got.Exhausted = errors.Is(runErr, transferdb.ErrRetriesExhausted)
+		got.Err = runErr != nil
+		return got
+	}
+
+	cmpRun := func(got any, exp any) string {
+		return cmp.Diff(got, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name:    "retries-serialization-failure",
+			ExpResp: runResult{Attempts: 2},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, 1, &pgconn.PgError{Code: transferdb.SerializationFailureCode})
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name:    "retries-deadlock",
+			ExpResp: runResult{Attempts: 3},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, 2, &pgconn.PgError{Code: transferdb.DeadlockDetectedCode})
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name:    "exhausted",
+			ExpResp: runResult{Attempts: 3, Exhausted: true, Err: true},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, 5, &pgconn.PgError{Code: transferdb.SerializationFailureCode})
+			},
+			CmpFunc: cmpRun,
+		},
+		{
+			Name:    "not-retryable",
+			ExpResp: runResult{Attempts: 1, Err: true},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx, 5, errNotRetryable)
+			},
+			CmpFunc: cmpRun,
+		},
+	}
+
+	return table
+}
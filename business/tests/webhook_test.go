@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/api/dbtest"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
+	"github.com/danipurwadi/internal-transfer-system/foundation/unittest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+// receiver is a webhook endpoint that verifies the signature of every delivery
+// against the secret of its subscription.
+type receiver struct {
+	mu         sync.Mutex
+	secret     string
+	statusCode int
+	events     []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(transferbus.WebhookTimestampHeader), 10, 64)
+	if err != nil || r.Header.Get(transferbus.WebhookSignatureHeader) != transferbus.SignWebhook(rc.secret, timestamp, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	rc.events = append(rc.events, r.Header.Get(transferbus.WebhookEventHeader))
+	w.WriteHeader(rc.statusCode)
+}
+
+func webhooks(db *dbtest.Database) []unittest.Table {
+	// the receivers listen on the loopback address
+	db.BusDomain.TransferBus.AllowPrivateWebhooks()
+	cfg := transferbus.WebhookConfig{
+		Timeout:              time.Second,
+		MaxAttempts:          2,
+		MaxDelay:             time.Hour,
+		AllowPrivateNetworks: true,
+	}
+
+	// subscribe opens two accounts with 100 each, subscribes the receiver to the
+	// destination and transfers 10 to it
+	subscribe := func(ctx context.Context, srcID int64, dstID int64, rc *receiver, url string) (transferbus.WebhookSubscription, error) {
+		for _, id := range []int64{srcID, dstID} {
+			if _, err := db.BusDomain.TransferBus.CreateAccount(ctx, transferbus.NewAccount{
+				AccountID:      id,
+				InitialBalance: decimal.NewFromInt(100),
+			}); err != nil {
+				return transferbus.WebhookSubscription{}, err
+			}
+		}
+
+		sub, err := db.BusDomain.TransferBus.CreateWebhookSubscription(ctx, transferbus.NewWebhookSubscription{
+			AccountID: dstID,
+			URL:       url + "/hooks",
+		})
+		if err != nil {
+			return transferbus.WebhookSubscription{}, err
+		}
+		rc.mu.Lock()
+		rc.secret = sub.Secret
+		rc.mu.Unlock()
+
+		_, err = db.BusDomain.TransferBus.CreateTransaction(ctx, transferbus.Transaction{
+			SourceAccountID:      srcID,
+			DestinationAccountID: dstID,
+			Amount:               decimal.NewFromInt(10),
+		})
+		if err != nil {
+			return transferbus.WebhookSubscription{}, err
+		}
+		return sub, nil
+	}
+
+	type deliveryResult struct {
+		Events         []string
+		Status         transferbus.DeliveryStatus
+		Attempts       int
+		LastStatusCode int
+	}
+
+	// deliver runs the delivery worker the given number of times and returns
+	// the outcome of the only delivery of the subscription
+	deliver := func(ctx context.Context, cfg transferbus.WebhookConfig, sub transferbus.WebhookSubscription, rc *receiver, runs int) any {
+		for range runs {
+			if _, err := db.BusDomain.TransferBus.DeliverWebhooks(ctx, cfg); err != nil {
+				return err
+			}
+		}
+
+		deliveries, err := db.BusDomain.TransferBus.QueryWebhookDeliveries(ctx, transferbus.DeliveryFilter{
+			SubscriptionID: sub.SubscriptionID,
+			Limit:          10,
+		})
+		if err != nil {
+			return err
+		}
+		if len(deliveries) != 1 {
+			return fmt.Errorf("expected 1 delivery, got %d", len(deliveries))
+		}
+
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+
+		return deliveryResult{
+			Events:         rc.events,
+			Status:         deliveries[0].Status,
+			Attempts:       deliveries[0].Attempts,
+			LastStatusCode: deliveries[0].LastStatusCode,
+		}
+	}
+
+	cmpDelivery := func(got any, exp any) string {
+		gotResp, exists := got.(deliveryResult)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unittest.Table{
+		{
+			Name: "delivered",
+			ExpResp: deliveryResult{
+				Events:         []string{string(transferbus.EventTransferPosted)},
+				Status:         transferbus.DeliveryStatusDelivered,
+				Attempts:       1,
+				LastStatusCode: http.StatusOK,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				rc := receiver{statusCode: http.StatusOK}
+				srv := httptest.NewServer(&rc)
+				defer srv.Close()
+
+				sub, err := subscribe(ctx, 18000, 18001, &rc, srv.URL)
+				if err != nil {
+					return err
+				}
+
+				// a delivered webhook is not sent again
+				return deliver(ctx, cfg, sub, &rc, 2)
+			},
+			CmpFunc: cmpDelivery,
+		},
+		{
+			Name: "dead",
+			ExpResp: deliveryResult{
+				Events:         []string{string(transferbus.EventTransferPosted), string(transferbus.EventTransferPosted)},
+				Status:         transferbus.DeliveryStatusDead,
+				Attempts:       2,
+				LastStatusCode: http.StatusServiceUnavailable,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				rc := receiver{statusCode: http.StatusServiceUnavailable}
+				srv := httptest.NewServer(&rc)
+				defer srv.Close()
+
+				sub, err := subscribe(ctx, 18002, 18003, &rc, srv.URL)
+				if err != nil {
+					return err
+				}
+
+				// without a base delay the failed delivery is due again on the
+				// next run, until it runs out of attempts
+				return deliver(ctx, cfg, sub, &rc, 3)
+			},
+			CmpFunc: cmpDelivery,
+		},
+		{
+			Name: "privateaddress",
+			ExpResp: deliveryResult{
+				Status:   transferbus.DeliveryStatusPending,
+				Attempts: 1,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				rc := receiver{statusCode: http.StatusOK}
+				srv := httptest.NewServer(&rc)
+				defer srv.Close()
+
+				sub, err := subscribe(ctx, 18004, 18005, &rc, srv.URL)
+				if err != nil {
+					return err
+				}
+
+				// the loopback receiver is refused before it is connected to
+				public := cfg
+				public.AllowPrivateNetworks = false
+				return deliver(ctx, public, sub, &rc, 1)
+			},
+			CmpFunc: cmpDelivery,
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,123 @@
+package transferbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+)
+
+// BatchMode decides what happens to a batch when one of its transactions fails.
+type BatchMode string
+
+const (
+	// BatchModeAtomic rolls back the whole batch on the first failure.
+	BatchModeAtomic BatchMode = "atomic"
+
+	// BatchModeBestEffort rolls back only the failed transactions and reports
+	// the outcome of each one.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+// BatchResult is the outcome of a single transaction of a batch. Err is set
+// when the transaction failed, in which case none of its funds moved.
+type BatchResult struct {
+	Transfer Transfer
+	Err      error
+}
+
+// BatchError identifies the transaction that failed an atomic batch.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("transactions[%d]: %s", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// CreateTransactions posts every transaction of the batch in a single database
+// transaction. All accounts of the batch are locked up front in the order
+// shared by every transfer, so concurrent batches touching the same accounts
+// queue up instead of deadlocking. In atomic mode the first failure rolls back
+// the batch and is returned as a *BatchError. In best effort mode every
+// transaction runs in its own savepoint and the failures are reported in its
+// BatchResult.
+func (b *Bus) CreateTransactions(ctx context.Context, transactions []Transaction, mode BatchMode) ([]BatchResult, error) {
+	var results []BatchResult
+	err := b.store.InTx(ctx, b.batchTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		results, err = createTransactions(ctx, dbtx, transactions, mode)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// createTransactions posts the batch as part of the given database transaction.
+func createTransactions(ctx context.Context, dbtx transferdb.TxQuerier, transactions []Transaction, mode BatchMode) ([]BatchResult, error) {
+	if _, err := dbtx.LockAccounts(ctx, batchAccountIDs(transactions)); err != nil {
+		return nil, fmt.Errorf("lock accounts: %w", err)
+	}
+
+	results := make([]BatchResult, len(transactions))
+	for i, transaction := range transactions {
+		if mode == BatchModeBestEffort {
+			result, err := createSavepointTransaction(ctx, dbtx, transaction)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+			continue
+		}
+
+		transfer, err := createTransaction(ctx, dbtx, transaction)
+		if err != nil {
+			return nil, &BatchError{Index: i, Err: err}
+		}
+		results[i] = BatchResult{Transfer: transfer}
+	}
+
+	return results, nil
+}
+
+// createSavepointTransaction posts the transaction inside a savepoint, so a
+// failure only rolls back this transaction. A serialization failure or
+// deadlock aborts the whole database transaction and is returned, so the
+// batch is retried as a whole.
+func createSavepointTransaction(ctx context.Context, dbtx transferdb.TxQuerier, transaction Transaction) (BatchResult, error) {
+	var transfer Transfer
+	err := dbtx.InSavepoint(ctx, func(sptx transferdb.TxQuerier) error {
+		var err error
+		transfer, err = createTransaction(ctx, sptx, transaction)
+		return err
+	})
+	if err != nil {
+		if transferdb.IsRetryable(err) {
+			return BatchResult{}, err
+		}
+		return BatchResult{Err: err}, nil
+	}
+	return BatchResult{Transfer: transfer}, nil
+}
+
+// batchAccountIDs returns every account touched by the batch once.
+func batchAccountIDs(transactions []Transaction) []int64 {
+	seen := make(map[int64]struct{}, 2*len(transactions))
+	ids := make([]int64, 0, 2*len(transactions))
+	for _, t := range transactions {
+		for _, id := range []int64{t.SourceAccountID, t.DestinationAccountID} {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,420 @@
+package transferbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultRelayBatchSize is the number of events published per database
+// transaction.
+const DefaultRelayBatchSize = 100
+
+// relayLockKey is the advisory lock held by the relay publishing events, so
+// only one relay publishes at a time and events stay in order.
+const relayLockKey int64 = 0x6f7574626f78
+
+// EventType names the change an event describes.
+type EventType string
+
+const (
+	EventAccountCreated   EventType = "AccountCreated"
+	EventTransferPosted   EventType = "TransferPosted"
+	EventTransferReversed EventType = "TransferReversed"
+)
+
+// Event describes a committed change to an account. Transfers raise an event
+// for every account they post to. Events of the same account are published in
+// the order they were committed, and EventID increases with that order.
+// Balance is the balance of the account right after the change.
+type Event struct {
+	EventID     int64
+	Type        EventType
+	AccountID   int64
+	Balance     decimal.Decimal
+	Payload     json.RawMessage
+	CreatedDate time.Time
+}
+
+// Publisher delivers events to downstream services. Events are handed to it in
+// increasing EventID order. Delivery is at least once: an event is handed to it
+// again when the relay fails after publishing it, so publishers and consumers
+// must deduplicate on EventID.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// RelayEvents publishes the events written since the last relay, in order,
+// and returns how many were published. An event is held back until every
+// transaction running when it was written has finished, so an event committing
+// late is never left behind one with a higher EventID. It stops at the first
+// event the publisher fails on, which is published again by the next relay
+// together with every event after it. Relays running concurrently with another
+// relay publish nothing.
+func (b *Bus) RelayEvents(ctx context.Context, publisher Publisher) (int, error) {
+	var published int
+	for {
+		n, err := b.relayEventsBatch(ctx, publisher)
+		published += n
+		if err != nil {
+			return published, err
+		}
+
+		if n < DefaultRelayBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// relayEventsBatch publishes a batch of events and marks them as published in
+// the same database transaction, which holds the relay lock until the marks
+// commit. When the transaction fails after the publisher accepted events, the
+// next relay hands them to it again, and publishers skip the ids they already
+// published.
+func (b *Bus) relayEventsBatch(ctx context.Context, publisher Publisher) (int, error) {
+	var published int
+	var publishErr error
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		published, publishErr = 0, nil
+
+		locked, err := dbtx.LockOutboxRelay(ctx, relayLockKey)
+		if err != nil {
+			return fmt.Errorf("lock outbox relay: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		dbEvents, err := dbtx.GetUnpublishedOutboxEvents(ctx, DefaultRelayBatchSize)
+		if err != nil {
+			return fmt.Errorf("get unpublished outbox events: %w", err)
+		}
+
+		for _, dbEvent := range dbEvents {
+			event := toEvent(dbEvent)
+			if err := publisher.Publish(ctx, event); err != nil {
+				// the events published so far are still marked
+				publishErr = fmt.Errorf("publish event[%d]: %w", event.EventID, err)
+				return nil
+			}
+
+			if err := dbtx.MarkOutboxEventPublished(ctx, transferdbgen.MarkOutboxEventPublishedParams{
+				PublishedDate: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				EventID:       event.EventID,
+			}); err != nil {
+				return fmt.Errorf("mark outbox event published: %d: %w", event.EventID, err)
+			}
+			published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// recordEvent writes the event to the outbox as part of the given database
+// transaction, together with a delivery to every webhook of the account. The
+// streams of the account are notified once the transaction commits.
+func recordEvent(ctx context.Context, dbtx transferdb.TxQuerier, eventType EventType, accountID int64, balance decimal.Decimal, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+
+	now := time.Now()
+
+	eventID, err := dbtx.CreateOutboxEvent(ctx, transferdbgen.CreateOutboxEventParams{
+		EventType:   string(eventType),
+		AccountID:   accountID,
+		Balance:     balance,
+		Payload:     data,
+		CreatedDate: now,
+	})
+	if err != nil {
+		return fmt.Errorf("create outbox event: %w", err)
+	}
+
+	if err := dbtx.CreateWebhookDeliveries(ctx, transferdbgen.CreateWebhookDeliveriesParams{
+		EventID:   eventID,
+		Status:    string(DeliveryStatusPending),
+		Now:       now,
+		AccountID: accountID,
+	}); err != nil {
+		return fmt.Errorf("create webhook deliveries: event[%d]: %w", eventID, err)
+	}
+
+	if err := dbtx.NotifyOutboxEvent(ctx, transferdbgen.NotifyOutboxEventParams{
+		Channel: accountEventsChannel,
+		Payload: strconv.FormatInt(accountID, 10),
+	}); err != nil {
+		return fmt.Errorf("notify outbox event: event[%d]: %w", eventID, err)
+	}
+	return nil
+}
+
+// recordTransferEvents writes an event for every account the transfer posted
+// to.
+func recordTransferEvents(ctx context.Context, dbtx transferdb.TxQuerier, transfer Transfer) error {
+	eventType := EventTransferPosted
+	if transfer.ReversalOf != uuid.Nil {
+		eventType = EventTransferReversed
+	}
+
+	payload := toTransferPayload(transfer)
+
+	var accountIDs []int64
+	for _, leg := range transfer.Legs {
+		if slices.Contains(accountIDs, leg.AccountID) {
+			continue
+		}
+		accountIDs = append(accountIDs, leg.AccountID)
+
+		balance, err := dbtx.GetBalance(ctx, leg.AccountID)
+		if err != nil {
+			return fmt.Errorf("get balance: %d: %w", leg.AccountID, err)
+		}
+
+		if err := recordEvent(ctx, dbtx, eventType, leg.AccountID, balance, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toEvent(dbEvent transferdbgen.OutboxEvent) Event {
+	return Event{
+		EventID:     dbEvent.EventID,
+		Type:        EventType(dbEvent.EventType),
+		AccountID:   dbEvent.AccountID,
+		Balance:     dbEvent.Balance,
+		Payload:     dbEvent.Payload,
+		CreatedDate: dbEvent.CreatedDate,
+	}
+}
+
+// =============================================================================
+
+// accountPayload is the payload of AccountCreated events. Accounts are created
+// empty and funded by a TransferPosted event of their opening balance.
+type accountPayload struct {
+	AccountID   int64         `json:"account_id"`
+	Currency    string        `json:"currency"`
+	Type        AccountType   `json:"type"`
+	Status      AccountStatus `json:"status"`
+	Owner       string        `json:"owner,omitempty"`
+	CreatedDate time.Time     `json:"created_date"`
+}
+
+func toAccountPayload(acc Account) accountPayload {
+	return accountPayload{
+		AccountID:   acc.AccountID,
+		Currency:    acc.Currency.String(),
+		Type:        acc.Type,
+		Status:      acc.Status,
+		Owner:       acc.OwnerRef,
+		CreatedDate: acc.CreatedDate,
+	}
+}
+
+// transferPayload is the payload of TransferPosted and TransferReversed
+// events.
+type transferPayload struct {
+	TransferID           uuid.UUID       `json:"transfer_id"`
+	EntryType            EntryType       `json:"entry_type"`
+	SourceAccountID      int64           `json:"source_account_id"`
+	DestinationAccountID int64           `json:"destination_account_id"`
+	Amount               decimal.Decimal `json:"amount"`
+	Currency             string          `json:"currency"`
+	DestinationAmount    decimal.Decimal `json:"destination_amount"`
+	DestinationCurrency  string          `json:"destination_currency"`
+	ReversalOf           *uuid.UUID      `json:"reversal_of,omitempty"`
+	Legs                 []legPayload    `json:"legs"`
+	CreatedDate          time.Time       `json:"created_date"`
+}
+
+type legPayload struct {
+	AccountID int64           `json:"account_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	Currency  string          `json:"currency"`
+}
+
+func toTransferPayload(transfer Transfer) transferPayload {
+	var reversalOf *uuid.UUID
+	if transfer.ReversalOf != uuid.Nil {
+		reversalOf = &transfer.ReversalOf
+	}
+
+	legs := make([]legPayload, len(transfer.Legs))
+	for i, leg := range transfer.Legs {
+		legs[i] = legPayload{
+			AccountID: leg.AccountID,
+			Amount:    leg.Amount,
+			Currency:  leg.Currency.String(),
+		}
+	}
+
+	return transferPayload{
+		TransferID:           transfer.TransferID,
+		EntryType:            transfer.EntryType,
+		SourceAccountID:      transfer.SourceAccountID,
+		DestinationAccountID: transfer.DestinationAccountID,
+		Amount:               transfer.Amount,
+		Currency:             transfer.Currency.String(),
+		DestinationAmount:    transfer.DestinationAmount,
+		DestinationCurrency:  transfer.DestinationCurrency.String(),
+		ReversalOf:           reversalOf,
+		Legs:                 legs,
+		CreatedDate:          transfer.CreatedDate,
+	}
+}
+
+// =============================================================================
+
+// MemoryPublisher keeps the published events in memory. It is meant for tests
+// and local use.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+var _ Publisher = (*MemoryPublisher)(nil)
+
+// Publish implements the Publisher interface. Events up to the last one
+// published are skipped.
+func (m *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if n := len(m.events); n > 0 && event.EventID <= m.events[n-1].EventID {
+		return nil
+	}
+
+	m.events = append(m.events, event)
+	return nil
+}
+
+// Events returns the events published so far, in the order they were
+// published.
+func (m *MemoryPublisher) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.events)
+}
+
+// eventMessage is the JSON form of an event, written by FilePublisher and sent
+// to webhooks.
+type eventMessage struct {
+	EventID     int64           `json:"event_id"`
+	Type        EventType       `json:"type"`
+	AccountID   int64           `json:"account_id"`
+	Balance     decimal.Decimal `json:"balance"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedDate time.Time       `json:"created_date"`
+}
+
+// FilePublisher appends every event as a line of JSON to a file. It is meant
+// for local use.
+type FilePublisher struct {
+	mu          sync.Mutex
+	file        *os.File
+	lastEventID int64
+}
+
+var _ Publisher = (*FilePublisher)(nil)
+
+// NewFilePublisher constructs a FilePublisher appending to the file at path,
+// creating it when it does not exist. The events already in the file are not
+// written again.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create events directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open events file: %w", err)
+	}
+
+	lastEventID, err := lastFileEventID(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FilePublisher{
+		file:        file,
+		lastEventID: lastEventID,
+	}, nil
+}
+
+// lastFileEventID returns the id of the last event written to the file.
+func lastFileEventID(file *os.File) (int64, error) {
+	var lastEventID int64
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var msg struct {
+			EventID int64 `json:"event_id"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			// a line cut short by a crash holds no event
+			continue
+		}
+		lastEventID = max(lastEventID, msg.EventID)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read events file: %w", err)
+	}
+
+	return lastEventID, nil
+}
+
+// Publish implements the Publisher interface. The event is synced to disk
+// before it counts as published, and events up to the last one written are
+// skipped.
+func (f *FilePublisher) Publish(ctx context.Context, event Event) error {
+	line, err := json.Marshal(eventMessage(event))
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if event.EventID <= f.lastEventID {
+		return nil
+	}
+
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("sync events file: %w", err)
+	}
+
+	f.lastEventID = event.EventID
+	return nil
+}
+
+// Close closes the file.
+func (f *FilePublisher) Close() error {
+	return f.file.Close()
+}
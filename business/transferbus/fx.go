@@ -0,0 +1,173 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateQuote prices a transfer between two accounts holding different
+// currencies and locks the rate until the quote expires. Quotes whose amount
+// converts to nothing in the destination currency fail with ErrZeroAmount.
+func (b *Bus) CreateQuote(ctx context.Context, nq NewQuote) (Quote, error) {
+	if nq.Amount.IsNegative() {
+		return Quote{}, ErrNegativeBalance
+	}
+	if nq.SourceAccountID == nq.DestinationAccountID {
+		return Quote{}, ErrSameAccount
+	}
+
+	var quote Quote
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		quote, err = b.createQuote(ctx, dbtx, nq)
+		return err
+	})
+	if err != nil {
+		return Quote{}, err
+	}
+	return quote, nil
+}
+
+// createQuote prices and records the quote as part of the given database
+// transaction.
+func (b *Bus) createQuote(ctx context.Context, dbtx transferdb.TxQuerier, nq NewQuote) (Quote, error) {
+	// the accounts stay locked until the quote is recorded, so neither can be
+	// frozen or closed in between
+	accounts, err := dbtx.LockAccounts(ctx, []int64{nq.SourceAccountID, nq.DestinationAccountID})
+	if err != nil {
+		return Quote{}, fmt.Errorf("lock accounts: %w", err)
+	}
+	if len(accounts) != 2 {
+		return Quote{}, ErrAccNotFound
+	}
+
+	var srcCur, dstCur currency.Currency
+	for _, dbAccount := range accounts {
+		acc, err := fromDBAccount(dbAccount)
+		if err != nil {
+			return Quote{}, err
+		}
+		if err := checkActive(acc.Status); err != nil {
+			return Quote{}, fmt.Errorf("account[%d]: %w", acc.AccountID, err)
+		}
+
+		switch acc.AccountID {
+		case nq.SourceAccountID:
+			srcCur = acc.Currency
+		case nq.DestinationAccountID:
+			dstCur = acc.Currency
+		}
+	}
+
+	price, err := b.fx.Price(ctx, srcCur, dstCur, nq.Amount)
+	if err != nil {
+		return Quote{}, err
+	}
+	if !price.DestinationAmount.IsPositive() {
+		return Quote{}, ErrZeroAmount
+	}
+
+	dbQuote, err := dbtx.CreateFxQuote(ctx, transferdbgen.CreateFxQuoteParams{
+		QuoteID:              uuid.New(),
+		SourceAccountID:      nq.SourceAccountID,
+		DestinationAccountID: nq.DestinationAccountID,
+		SourceCurrency:       price.SourceCurrency.String(),
+		DestinationCurrency:  price.DestinationCurrency.String(),
+		SourceAmount:         price.SourceAmount,
+		DestinationAmount:    price.DestinationAmount,
+		Rate:                 price.Rate,
+		CreatedDate:          price.CreatedDate,
+		ExpiresDate:          price.ExpiresDate,
+	})
+	if err != nil {
+		return Quote{}, fmt.Errorf("create fx quote: %w", err)
+	}
+
+	return toQuote(dbQuote)
+}
+
+// fxJournalEntry claims the quote of the transaction for the transfer and
+// builds its journal entry. The source amount is moved into the fx account of
+// the source currency and the destination amount out of the fx account of the
+// destination currency, so the entry balances in both currencies.
+func fxJournalEntry(ctx context.Context, dbtx transferdb.TxQuerier, transaction Transaction, transferID uuid.UUID) (journalEntry, error) {
+	quote, err := claimQuote(ctx, dbtx, transaction.QuoteID, transferID)
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	if time.Now().After(quote.ExpiresDate) {
+		return journalEntry{}, ErrQuoteExpired
+	}
+	if quote.SourceAccountID != transaction.SourceAccountID ||
+		quote.DestinationAccountID != transaction.DestinationAccountID ||
+		!quote.SourceAmount.Equal(transaction.Amount) {
+		return journalEntry{}, ErrQuoteMismatch
+	}
+	if !transaction.Currency.IsZero() && !transaction.Currency.Equal(quote.SourceCurrency) {
+		return journalEntry{}, fmt.Errorf("%w: transfer in %s from a %s account", ErrCurrencyMismatch, transaction.Currency, quote.SourceCurrency)
+	}
+
+	fxSrcAccountID, err := systemAccount(ctx, dbtx, SystemAccountFX, quote.SourceCurrency)
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	fxDstAccountID, err := systemAccount(ctx, dbtx, SystemAccountFX, quote.DestinationCurrency)
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	return journalEntry{
+		TransferID:           transferID,
+		EntryType:            EntryTypeFXTransfer,
+		SourceAccountID:      quote.SourceAccountID,
+		DestinationAccountID: quote.DestinationAccountID,
+		Amount:               quote.SourceAmount,
+		Currency:             quote.SourceCurrency,
+		DestinationAmount:    quote.DestinationAmount,
+		DestinationCurrency:  quote.DestinationCurrency,
+		Rate:                 quote.Rate,
+		QuoteID:              quote.QuoteID,
+		Postings: []posting{
+			{AccountID: quote.SourceAccountID, Amount: quote.SourceAmount.Neg(), Currency: quote.SourceCurrency},
+			{AccountID: fxSrcAccountID, Amount: quote.SourceAmount, Currency: quote.SourceCurrency},
+			{AccountID: fxDstAccountID, Amount: quote.DestinationAmount.Neg(), Currency: quote.DestinationCurrency},
+			{AccountID: quote.DestinationAccountID, Amount: quote.DestinationAmount, Currency: quote.DestinationCurrency},
+		},
+	}, nil
+}
+
+// claimQuote marks the quote as used by the transfer. Concurrent transfers with
+// the same quote block on its row until the first one commits or rolls back.
+func claimQuote(ctx context.Context, dbtx transferdb.TxQuerier, quoteID uuid.UUID, transferID uuid.UUID) (Quote, error) {
+	dbQuote, err := dbtx.ClaimFxQuote(ctx, transferdbgen.ClaimFxQuoteParams{
+		TransferID: pgtype.UUID{Bytes: transferID, Valid: true},
+		QuoteID:    quoteID,
+	})
+	if err == nil {
+		return toQuote(dbQuote)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return Quote{}, fmt.Errorf("claim fx quote: %s: %w", quoteID, err)
+	}
+
+	// the quote either does not exist or was claimed by another transfer
+	if _, err := dbtx.GetFxQuote(ctx, quoteID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Quote{}, ErrQuoteNotFound
+		}
+		return Quote{}, fmt.Errorf("get fx quote: %s: %w", quoteID, err)
+	}
+
+	return Quote{}, ErrQuoteUsed
+}
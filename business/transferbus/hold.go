@@ -0,0 +1,368 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultHoldTTL is how long a hold reserves funds when no expiry is requested.
+const DefaultHoldTTL = 7 * 24 * time.Hour
+
+// DefaultExpireHoldsBatchSize is the number of expired holds released per
+// database transaction.
+const DefaultExpireHoldsBatchSize = 100
+
+// AuthorizeTransfer reserves the amount of the hold on the source account. The
+// funds stay in the ledger balance of the account but are no longer available
+// until the hold is captured, voided or expires.
+func (b *Bus) AuthorizeTransfer(ctx context.Context, nh NewHold) (Hold, error) {
+	if nh.Amount.IsNegative() {
+		return Hold{}, ErrNegativeBalance
+	}
+	if nh.SourceAccountID == nh.DestinationAccountID {
+		return Hold{}, ErrSameAccount
+	}
+
+	var hold Hold
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		hold, err = authorizeTransfer(ctx, dbtx, nh)
+		return err
+	})
+	if err != nil {
+		return Hold{}, err
+	}
+	return hold, nil
+}
+
+// authorizeTransfer reserves the funds and records the hold as part of the
+// given database transaction.
+func authorizeTransfer(ctx context.Context, dbtx transferdb.TxQuerier, nh NewHold) (Hold, error) {
+	// both accounts stay locked until the hold is recorded, so they cannot be
+	// frozen, closed or debited between the checks and the reservation
+	accounts, err := dbtx.LockAccounts(ctx, []int64{nh.SourceAccountID, nh.DestinationAccountID})
+	if err != nil {
+		return Hold{}, fmt.Errorf("lock accounts: %w", err)
+	}
+	if len(accounts) != 2 {
+		return Hold{}, ErrAccNotFound
+	}
+	for _, acc := range accounts {
+		if err := checkActive(AccountStatus(acc.Status)); err != nil {
+			return Hold{}, fmt.Errorf("account[%d]: %w", acc.AccountID, err)
+		}
+	}
+
+	cur, err := transferCurrency(Transaction{
+		SourceAccountID:      nh.SourceAccountID,
+		DestinationAccountID: nh.DestinationAccountID,
+		Currency:             nh.Currency,
+	}, accounts)
+	if err != nil {
+		return Hold{}, err
+	}
+	amount := cur.Round(nh.Amount)
+	if !amount.IsPositive() {
+		return Hold{}, ErrZeroAmount
+	}
+
+	result, err := dbtx.HoldFunds(ctx, transferdbgen.HoldFundsParams{
+		Amount:    amount,
+		AccountID: nh.SourceAccountID,
+	})
+	if err != nil {
+		return Hold{}, fmt.Errorf("hold funds: %w", err)
+	}
+
+	// if no rows is updated, the available balance was too low
+	if result.RowsAffected() == 0 {
+		return Hold{}, insufficientFunds(ctx, dbtx, nh.SourceAccountID)
+	}
+
+	ttl := nh.ExpiresIn
+	if ttl <= 0 {
+		ttl = DefaultHoldTTL
+	}
+	now := time.Now()
+
+	dbHold, err := dbtx.CreateHold(ctx, transferdbgen.CreateHoldParams{
+		HoldID:               uuid.New(),
+		SourceAccountID:      nh.SourceAccountID,
+		DestinationAccountID: nh.DestinationAccountID,
+		Currency:             cur.String(),
+		Amount:               amount,
+		Status:               string(HoldStatusAuthorized),
+		CreatedDate:          now,
+		ExpiresDate:          now.Add(ttl),
+		LastModifiedDate:     now,
+	})
+	if err != nil {
+		return Hold{}, fmt.Errorf("create hold: %w", err)
+	}
+
+	return toHold(dbHold)
+}
+
+// Capture transfers all or part of an authorized hold to its destination. The
+// whole hold is released, so the part that is not captured becomes available
+// again.
+func (b *Bus) Capture(ctx context.Context, capture HoldCapture) (Transfer, error) {
+	if capture.Amount.IsNegative() {
+		return Transfer{}, ErrNegativeBalance
+	}
+
+	var transfer Transfer
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		transfer, err = captureHold(ctx, dbtx, capture)
+		return err
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	return transfer, nil
+}
+
+// captureHold posts the capture and closes the hold as part of the given
+// database transaction.
+func captureHold(ctx context.Context, dbtx transferdb.TxQuerier, capture HoldCapture) (Transfer, error) {
+	hold, err := lockAuthorizedHold(ctx, dbtx, capture.HoldID)
+	if err != nil {
+		return Transfer{}, err
+	}
+	if time.Now().After(hold.ExpiresDate) {
+		return Transfer{}, ErrHoldExpired
+	}
+
+	amount := capture.Amount
+	if amount.IsZero() {
+		amount = hold.Amount
+	} else if amount = hold.Currency.Round(amount); amount.IsZero() {
+		return Transfer{}, ErrZeroAmount
+	}
+	if !amount.IsPositive() || amount.GreaterThan(hold.Amount) {
+		return Transfer{}, ErrCaptureExceeded
+	}
+
+	// the capture is the debit of the source account, so it counts against its
+	// limits like any other transfer
+	accounts, err := dbtx.LockAccounts(ctx, []int64{hold.SourceAccountID, hold.DestinationAccountID})
+	if err != nil {
+		return Transfer{}, fmt.Errorf("lock accounts: %w", err)
+	}
+	for _, acc := range accounts {
+		if err := checkActive(AccountStatus(acc.Status)); err != nil {
+			return Transfer{}, fmt.Errorf("account[%d]: %w", acc.AccountID, err)
+		}
+	}
+	for _, acc := range accounts {
+		if acc.AccountID != hold.SourceAccountID {
+			continue
+		}
+		if err := checkLimits(ctx, dbtx, acc, amount); err != nil {
+			return Transfer{}, err
+		}
+	}
+
+	// the funds are released before the debit, which may only use available funds
+	if err := dbtx.ReleaseFunds(ctx, transferdbgen.ReleaseFundsParams{
+		Amount:    hold.Amount,
+		AccountID: hold.SourceAccountID,
+	}); err != nil {
+		return Transfer{}, fmt.Errorf("release funds: %w", err)
+	}
+
+	transfer, err := postJournalEntry(ctx, dbtx, journalEntry{
+		TransferID:           uuid.New(),
+		EntryType:            EntryTypeCapture,
+		SourceAccountID:      hold.SourceAccountID,
+		DestinationAccountID: hold.DestinationAccountID,
+		Amount:               amount,
+		Currency:             hold.Currency,
+		Postings: []posting{
+			{AccountID: hold.SourceAccountID, Amount: amount.Neg(), Currency: hold.Currency},
+			{AccountID: hold.DestinationAccountID, Amount: amount, Currency: hold.Currency},
+		},
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	if _, err := dbtx.UpdateHold(ctx, transferdbgen.UpdateHoldParams{
+		Status:         string(HoldStatusCaptured),
+		CapturedAmount: amount,
+		TransferID:     pgtype.UUID{Bytes: transfer.TransferID, Valid: true},
+		HoldID:         hold.HoldID,
+	}); err != nil {
+		return Transfer{}, fmt.Errorf("update hold: %s: %w", hold.HoldID, err)
+	}
+
+	return transfer, nil
+}
+
+// Void releases an authorized hold without moving any funds.
+func (b *Bus) Void(ctx context.Context, holdID uuid.UUID) (Hold, error) {
+	var hold Hold
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		hold, err = voidHold(ctx, dbtx, holdID)
+		return err
+	})
+	if err != nil {
+		return Hold{}, err
+	}
+	return hold, nil
+}
+
+// voidHold releases the hold as part of the given database transaction.
+func voidHold(ctx context.Context, dbtx transferdb.TxQuerier, holdID uuid.UUID) (Hold, error) {
+	hold, err := lockAuthorizedHold(ctx, dbtx, holdID)
+	if err != nil {
+		return Hold{}, err
+	}
+
+	voided, err := releaseHold(ctx, dbtx, hold, HoldStatusVoided)
+	if err != nil {
+		return Hold{}, err
+	}
+
+	return voided, nil
+}
+
+// ExpireHolds releases every authorized hold that has expired and returns how
+// many were released. Holds locked by a concurrent capture or void are left
+// for the next run.
+func (b *Bus) ExpireHolds(ctx context.Context) (int, error) {
+	var expired int
+	for {
+		n, err := b.expireHoldsBatch(ctx)
+		if err != nil {
+			return expired, err
+		}
+		expired += n
+
+		if n < DefaultExpireHoldsBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+func (b *Bus) expireHoldsBatch(ctx context.Context) (int, error) {
+	var expired int
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		dbHolds, err := dbtx.LockExpiredHolds(ctx, transferdbgen.LockExpiredHoldsParams{
+			Now:      time.Now(),
+			RowLimit: DefaultExpireHoldsBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("lock expired holds: %w", err)
+		}
+
+		holds := make([]Hold, len(dbHolds))
+		for i, dbHold := range dbHolds {
+			hold, err := toHold(dbHold)
+			if err != nil {
+				return err
+			}
+			holds[i] = hold
+		}
+
+		// Lock the source accounts in the same order transfers do before
+		// releasing their funds, so expiry cannot deadlock against them.
+		if _, err := dbtx.LockAccounts(ctx, holdSourceAccountIDs(holds)); err != nil {
+			return fmt.Errorf("lock accounts: %w", err)
+		}
+
+		for _, hold := range holds {
+			if _, err := releaseHold(ctx, dbtx, hold, HoldStatusExpired); err != nil {
+				return err
+			}
+		}
+
+		expired = len(dbHolds)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+// holdSourceAccountIDs returns the distinct source accounts of the holds.
+func holdSourceAccountIDs(holds []Hold) []int64 {
+	seen := make(map[int64]struct{}, len(holds))
+	ids := make([]int64, 0, len(holds))
+	for _, h := range holds {
+		if _, ok := seen[h.SourceAccountID]; !ok {
+			seen[h.SourceAccountID] = struct{}{}
+			ids = append(ids, h.SourceAccountID)
+		}
+	}
+	return ids
+}
+
+// QueryHold returns the hold with the given id.
+func (b *Bus) QueryHold(ctx context.Context, holdID uuid.UUID) (Hold, error) {
+	dbHold, err := b.store.GetHold(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Hold{}, ErrHoldNotFound
+		}
+		return Hold{}, fmt.Errorf("get hold: %s: %w", holdID, err)
+	}
+
+	return toHold(dbHold)
+}
+
+// lockAuthorizedHold locks the hold for the rest of the database transaction,
+// so a hold is only ever captured, voided or expired once.
+func lockAuthorizedHold(ctx context.Context, dbtx transferdb.TxQuerier, holdID uuid.UUID) (Hold, error) {
+	dbHold, err := dbtx.LockHold(ctx, holdID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Hold{}, ErrHoldNotFound
+		}
+		return Hold{}, fmt.Errorf("lock hold: %s: %w", holdID, err)
+	}
+
+	hold, err := toHold(dbHold)
+	if err != nil {
+		return Hold{}, err
+	}
+	if hold.Status != HoldStatusAuthorized {
+		return Hold{}, ErrHoldClosed
+	}
+
+	return hold, nil
+}
+
+// releaseHold makes the funds of the hold available again and closes it with
+// the given status.
+func releaseHold(ctx context.Context, dbtx transferdb.TxQuerier, hold Hold, status HoldStatus) (Hold, error) {
+	if err := dbtx.ReleaseFunds(ctx, transferdbgen.ReleaseFundsParams{
+		Amount:    hold.Amount,
+		AccountID: hold.SourceAccountID,
+	}); err != nil {
+		return Hold{}, fmt.Errorf("release funds: %w", err)
+	}
+
+	dbHold, err := dbtx.UpdateHold(ctx, transferdbgen.UpdateHoldParams{
+		Status:         string(status),
+		CapturedAmount: decimal.Zero,
+		HoldID:         hold.HoldID,
+	})
+	if err != nil {
+		return Hold{}, fmt.Errorf("update hold: %s: %w", hold.HoldID, err)
+	}
+
+	return toHold(dbHold)
+}
@@ -0,0 +1,219 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// EquityAccountID is the system account that funds the initial balance of every
+// account held in currency.Default. System accounts have negative ids, which
+// clients can never pick, and are the only accounts allowed to have a negative
+// balance.
+const EquityAccountID int64 = -1
+
+// SystemAccountPurpose identifies the role of a system account. Every currency
+// has one system account per purpose.
+type SystemAccountPurpose string
+
+const (
+	SystemAccountEquity SystemAccountPurpose = "equity"
+	SystemAccountFX     SystemAccountPurpose = "fx"
+)
+
+// posting credits a single account with a positive amount or debits it with a
+// negative one, in the currency of that account.
+type posting struct {
+	AccountID int64
+	Amount    decimal.Decimal
+	Currency  currency.Currency
+}
+
+// journalEntry describes a movement of funds from the source to the destination
+// account through postings that must sum to zero in every currency. Amount is
+// denominated in Currency, the currency of the source account. The destination
+// fields are only set for conversions and otherwise default to the source ones.
+// ReversalOf is set when the entry sends back funds of an earlier transfer.
+type journalEntry struct {
+	TransferID           uuid.UUID
+	EntryType            EntryType
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Amount               decimal.Decimal
+	Currency             currency.Currency
+	DestinationAmount    decimal.Decimal
+	DestinationCurrency  currency.Currency
+	Rate                 decimal.Decimal
+	QuoteID              uuid.UUID
+	ReversalOf           uuid.UUID
+	Postings             []posting
+}
+
+// postJournalEntry records the journal entry and applies each of its postings to
+// the balance of the account it touches, in order. The database checks again
+// that the postings of the entry sum to zero when the transaction commits.
+//
+// Accounts are always locked in descending account id order. System accounts
+// have negative ids, so callers may lock the customer accounts of a transfer
+// first and the system accounts are only locked here, once the entry is built.
+func postJournalEntry(ctx context.Context, dbtx transferdb.TxQuerier, entry journalEntry) (Transfer, error) {
+	sums := make(map[currency.Currency]decimal.Decimal)
+	for i, p := range entry.Postings {
+		entry.Postings[i].Amount = p.Currency.Round(p.Amount)
+		sums[p.Currency] = sums[p.Currency].Add(entry.Postings[i].Amount)
+	}
+	for _, sum := range sums {
+		if !sum.IsZero() {
+			return Transfer{}, ErrUnbalancedEntry
+		}
+	}
+
+	if entry.DestinationCurrency.IsZero() {
+		entry.DestinationAmount = entry.Amount
+		entry.DestinationCurrency = entry.Currency
+		entry.Rate = decimal.NewFromInt(1)
+	}
+
+	accountIDs := make([]int64, len(entry.Postings))
+	for i, p := range entry.Postings {
+		accountIDs[i] = p.AccountID
+	}
+	accounts, err := dbtx.LockAccounts(ctx, accountIDs)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("lock accounts: %w", err)
+	}
+
+	// frozen and closed accounts neither send nor receive funds
+	for _, acc := range accounts {
+		if err := checkActive(AccountStatus(acc.Status)); err != nil {
+			return Transfer{}, fmt.Errorf("account[%d]: %w", acc.AccountID, err)
+		}
+	}
+
+	now := time.Now()
+
+	dbEntry, err := dbtx.CreateJournalEntry(ctx, transferdbgen.CreateJournalEntryParams{
+		TransferID:           entry.TransferID,
+		EntryType:            string(entry.EntryType),
+		SourceAccountID:      entry.SourceAccountID,
+		DestinationAccountID: entry.DestinationAccountID,
+		Amount:               entry.Currency.Round(entry.Amount),
+		Currency:             entry.Currency.String(),
+		DestinationAmount:    entry.DestinationCurrency.Round(entry.DestinationAmount),
+		DestinationCurrency:  entry.DestinationCurrency.String(),
+		Rate:                 entry.Rate,
+		QuoteID:              pgtype.UUID{Bytes: entry.QuoteID, Valid: entry.QuoteID != uuid.Nil},
+		ReversalOf:           pgtype.UUID{Bytes: entry.ReversalOf, Valid: entry.ReversalOf != uuid.Nil},
+		CreatedDate:          now,
+	})
+	if err != nil {
+		return Transfer{}, fmt.Errorf("create journal entry: %w", err)
+	}
+
+	legs := make([]TransferLeg, len(entry.Postings))
+	for i, p := range entry.Postings {
+		if err := applyPosting(ctx, dbtx, p); err != nil {
+			return Transfer{}, err
+		}
+
+		dbTransaction, err := dbtx.CreateTransaction(ctx, transferdbgen.CreateTransactionParams{
+			TransferID:  entry.TransferID,
+			AccountID:   p.AccountID,
+			Amount:      p.Amount,
+			Currency:    p.Currency.String(),
+			CreatedDate: now,
+		})
+		if err != nil {
+			return Transfer{}, fmt.Errorf("create transaction: %w", err)
+		}
+
+		if legs[i], err = fromDBTransaction(dbTransaction); err != nil {
+			return Transfer{}, err
+		}
+	}
+
+	transfer, err := toTransfer(dbEntry, legs)
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	if err := recordTransferEvents(ctx, dbtx, transfer); err != nil {
+		return Transfer{}, err
+	}
+
+	return transfer, nil
+}
+
+// applyPosting moves the balance of the account of the posting. Debits fail with
+// ErrInsufficientFunds when the account cannot cover them.
+func applyPosting(ctx context.Context, dbtx transferdb.TxQuerier, p posting) error {
+	if p.Amount.IsNegative() {
+		debitResult, err := dbtx.DebitAccount(ctx, transferdbgen.DebitAccountParams{
+			Amount:    p.Amount.Neg(),
+			AccountID: p.AccountID,
+		})
+		if err != nil {
+			return fmt.Errorf("debit account: %w", err)
+		}
+
+		// if no rows is updated, balance was too low
+		if debitResult.RowsAffected() == 0 {
+			return insufficientFunds(ctx, dbtx, p.AccountID)
+		}
+		return nil
+	}
+
+	if _, err := dbtx.CreditAccount(ctx, transferdbgen.CreditAccountParams{
+		Amount:    p.Amount,
+		AccountID: p.AccountID,
+	}); err != nil {
+		return fmt.Errorf("credit account: %w", err)
+	}
+	return nil
+}
+
+// queryTransfer loads a journal entry together with its postings.
+func queryTransfer(ctx context.Context, store transferdb.TxQuerier, transferID uuid.UUID) (Transfer, error) {
+	dbEntry, err := store.GetJournalEntry(ctx, transferID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Transfer{}, ErrTransferNotFound
+		}
+		return Transfer{}, fmt.Errorf("get journal entry: %s: %w", transferID, err)
+	}
+
+	dbTransactions, err := store.GetTransferTransactions(ctx, transferID)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("get transfer transactions: %s: %w", transferID, err)
+	}
+
+	legs, err := fromDBTransactions(dbTransactions)
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	return toTransfer(dbEntry, legs)
+}
+
+// systemAccount returns the id of the system account with the given purpose in
+// the currency.
+func systemAccount(ctx context.Context, store transferdb.TxQuerier, purpose SystemAccountPurpose, cur currency.Currency) (int64, error) {
+	accountID, err := store.GetSystemAccount(ctx, transferdbgen.GetSystemAccountParams{
+		Purpose:  string(purpose),
+		Currency: cur.String(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get system account: %s %s: %w", purpose, cur, err)
+	}
+
+	return accountID, nil
+}
@@ -0,0 +1,189 @@
+package transferbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/google/uuid"
+)
+
+// FreezeAccount stops the account from sending or receiving funds until it is
+// unfrozen. Freezing a frozen account leaves it unchanged.
+func (b *Bus) FreezeAccount(ctx context.Context, accountID int64) (Account, error) {
+	var acc Account
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		acc, err = changeAccountStatus(ctx, dbtx, accountID, AccountStatusFrozen)
+		return err
+	})
+	if err != nil {
+		return Account{}, err
+	}
+	return acc, nil
+}
+
+// UnfreezeAccount makes a frozen account active again. Unfreezing an active
+// account leaves it unchanged.
+func (b *Bus) UnfreezeAccount(ctx context.Context, accountID int64) (Account, error) {
+	var acc Account
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		acc, err = changeAccountStatus(ctx, dbtx, accountID, AccountStatusActive)
+		return err
+	})
+	if err != nil {
+		return Account{}, err
+	}
+	return acc, nil
+}
+
+// CloseAccount closes an active account for good. An account with a positive
+// balance is only closed when the closure names a sweep account, which then
+// receives the whole balance in the same database transaction.
+func (b *Bus) CloseAccount(ctx context.Context, closure AccountClosure) (Account, error) {
+	var acc Account
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		acc, err = closeAccount(ctx, dbtx, closure)
+		return err
+	})
+	if err != nil {
+		return Account{}, err
+	}
+	return acc, nil
+}
+
+func closeAccount(ctx context.Context, dbtx transferdb.TxQuerier, closure AccountClosure) (Account, error) {
+	if closure.AccountID < 0 {
+		return Account{}, ErrSystemAccount
+	}
+	if closure.SweepAccountID == closure.AccountID {
+		return Account{}, ErrSameAccount
+	}
+
+	// the sweep account is locked together with the closed account, so both are
+	// taken in the same order as every transfer
+	accountIDs := []int64{closure.AccountID}
+	if closure.SweepAccountID != 0 {
+		accountIDs = append(accountIDs, closure.SweepAccountID)
+	}
+	accounts, err := dbtx.LockAccounts(ctx, accountIDs)
+	if err != nil {
+		return Account{}, fmt.Errorf("lock accounts: %w", err)
+	}
+
+	var acc Account
+	found := false
+	for _, dbAccount := range accounts {
+		if dbAccount.AccountID == closure.AccountID {
+			if acc, err = fromDBAccount(dbAccount); err != nil {
+				return Account{}, err
+			}
+			found = true
+		}
+	}
+	if !found || len(accounts) != len(accountIDs) {
+		return Account{}, ErrAccNotFound
+	}
+	if err := checkActive(acc.Status); err != nil {
+		return Account{}, err
+	}
+
+	// funds on hold belong to pending transfers, which must be settled first
+	if acc.HeldBalance.IsPositive() {
+		return Account{}, ErrAccHasHolds
+	}
+
+	if !acc.Balance.IsZero() {
+		if closure.SweepAccountID == 0 || acc.Balance.IsNegative() {
+			return Account{}, ErrAccNotEmpty
+		}
+
+		cur, err := transferCurrency(Transaction{
+			SourceAccountID:      acc.AccountID,
+			DestinationAccountID: closure.SweepAccountID,
+		}, accounts)
+		if err != nil {
+			return Account{}, err
+		}
+
+		// the sweep is not a transfer of the customer, so it is neither capped by
+		// nor counted against the transfer limits of the account
+		if _, err := postJournalEntry(ctx, dbtx, journalEntry{
+			TransferID:           uuid.New(),
+			EntryType:            EntryTypeClosure,
+			SourceAccountID:      acc.AccountID,
+			DestinationAccountID: closure.SweepAccountID,
+			Amount:               acc.Balance,
+			Currency:             cur,
+			Postings: []posting{
+				{AccountID: acc.AccountID, Amount: acc.Balance.Neg(), Currency: cur},
+				{AccountID: closure.SweepAccountID, Amount: acc.Balance, Currency: cur},
+			},
+		}); err != nil {
+			return Account{}, fmt.Errorf("sweep balance: %w", err)
+		}
+	}
+
+	dbAccount, err := dbtx.UpdateAccountStatus(ctx, transferdbgen.UpdateAccountStatusParams{
+		Status:    string(AccountStatusClosed),
+		AccountID: acc.AccountID,
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("update account status: %d: %w", acc.AccountID, err)
+	}
+
+	return fromDBAccount(dbAccount)
+}
+
+// changeAccountStatus moves the account between active and frozen. Closed
+// accounts stay closed.
+func changeAccountStatus(ctx context.Context, dbtx transferdb.TxQuerier, accountID int64, status AccountStatus) (Account, error) {
+	// every account creation and conversion relies on the system accounts
+	if accountID < 0 {
+		return Account{}, ErrSystemAccount
+	}
+
+	accounts, err := dbtx.LockAccounts(ctx, []int64{accountID})
+	if err != nil {
+		return Account{}, fmt.Errorf("lock accounts: %w", err)
+	}
+	if len(accounts) != 1 {
+		return Account{}, ErrAccNotFound
+	}
+
+	acc, err := fromDBAccount(accounts[0])
+	if err != nil {
+		return Account{}, err
+	}
+	if acc.Status == AccountStatusClosed {
+		return Account{}, ErrAccClosed
+	}
+	if acc.Status == status {
+		return acc, nil
+	}
+
+	dbAccount, err := dbtx.UpdateAccountStatus(ctx, transferdbgen.UpdateAccountStatusParams{
+		Status:    string(status),
+		AccountID: accountID,
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("update account status: %d: %w", accountID, err)
+	}
+
+	return fromDBAccount(dbAccount)
+}
+
+// checkActive returns the error of an account that cannot send or receive
+// funds in the given status.
+func checkActive(status AccountStatus) error {
+	switch status {
+	case AccountStatusFrozen:
+		return ErrAccFrozen
+	case AccountStatusClosed:
+		return ErrAccClosed
+	}
+	return nil
+}
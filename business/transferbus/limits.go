@@ -0,0 +1,162 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dailyLimitWindow   = 24 * time.Hour
+	monthlyLimitWindow = 30 * 24 * time.Hour
+)
+
+// limitedEntryTypes are the journal entries that spend the limits of their
+// source account. Reversals and closing sweeps send funds back or out without
+// the customer spending them.
+var limitedEntryTypes = []string{
+	string(EntryTypeTransfer),
+	string(EntryTypeFXTransfer),
+	string(EntryTypeCapture),
+}
+
+// SetAccountLimits sets the transfer limits of the account, replacing the
+// limits of its account type.
+func (b *Bus) SetAccountLimits(ctx context.Context, accountID int64, limits TransferLimits) (TransferLimits, error) {
+	if limits.negative() {
+		return TransferLimits{}, ErrNegativeLimit
+	}
+
+	var dbLimit transferdbgen.AccountLimit
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		dbLimit, err = dbtx.UpsertAccountLimit(ctx, transferdbgen.UpsertAccountLimitParams{
+			AccountID:     accountID,
+			MaxAmount:     limits.MaxAmount,
+			DailyAmount:   limits.DailyAmount,
+			MonthlyAmount: limits.MonthlyAmount,
+		})
+		if err != nil {
+			var pgError *pgconn.PgError
+			if errors.As(err, &pgError) && pgError.Code == ViolatesForeignKeyConstraint {
+				return ErrAccNotFound
+			}
+			return fmt.Errorf("upsert account limit: %d: %w", accountID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return TransferLimits{}, err
+	}
+
+	return fromDBAccountLimit(dbLimit), nil
+}
+
+// SetAccountTypeLimits sets the transfer limits of every account of the type
+// and currency that has no limits of its own.
+func (b *Bus) SetAccountTypeLimits(ctx context.Context, accountType AccountType, cur currency.Currency, limits TransferLimits) (TransferLimits, error) {
+	if !accountType.known() {
+		return TransferLimits{}, ErrUnknownAccountType
+	}
+	if limits.negative() {
+		return TransferLimits{}, ErrNegativeLimit
+	}
+
+	var dbLimit transferdbgen.AccountTypeLimit
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		dbLimit, err = dbtx.UpsertAccountTypeLimit(ctx, transferdbgen.UpsertAccountTypeLimitParams{
+			AccountType:   string(accountType),
+			Currency:      cur.String(),
+			MaxAmount:     limits.MaxAmount,
+			DailyAmount:   limits.DailyAmount,
+			MonthlyAmount: limits.MonthlyAmount,
+		})
+		if err != nil {
+			return fmt.Errorf("upsert account type limit: %s: %s: %w", accountType, cur, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return TransferLimits{}, err
+	}
+
+	return fromDBAccountTypeLimit(dbLimit), nil
+}
+
+// transferLimits returns the limits of the account, falling back to the limits
+// of its account type in the currency of the account.
+func transferLimits(ctx context.Context, dbtx transferdb.TxQuerier, account transferdbgen.Account) (TransferLimits, error) {
+	dbLimit, err := dbtx.GetAccountLimit(ctx, account.AccountID)
+	if err == nil {
+		return fromDBAccountLimit(dbLimit), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return TransferLimits{}, fmt.Errorf("get account limit: %d: %w", account.AccountID, err)
+	}
+
+	dbTypeLimit, err := dbtx.GetAccountTypeLimit(ctx, transferdbgen.GetAccountTypeLimitParams{
+		AccountType: account.AccountType,
+		Currency:    account.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TransferLimits{}, nil
+		}
+		return TransferLimits{}, fmt.Errorf("get account type limit: %s: %s: %w", account.AccountType, account.Currency, err)
+	}
+	return fromDBAccountTypeLimit(dbTypeLimit), nil
+}
+
+// checkLimits fails with ErrLimitExceeded when debiting amount from the source
+// account breaks one of its limits. The rolling sums only hold while the
+// account is locked, so concurrent transfers cannot both fit the same room.
+func checkLimits(ctx context.Context, dbtx transferdb.TxQuerier, source transferdbgen.Account, amount decimal.Decimal) error {
+	limits, err := transferLimits(ctx, dbtx, source)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxAmount.IsPositive() && amount.GreaterThan(limits.MaxAmount) {
+		return fmt.Errorf("%w: single transfer limit %s", ErrLimitExceeded, limits.MaxAmount)
+	}
+
+	now := time.Now()
+	windows := []struct {
+		name   string
+		limit  decimal.Decimal
+		window time.Duration
+	}{
+		{name: "daily", limit: limits.DailyAmount, window: dailyLimitWindow},
+		{name: "monthly", limit: limits.MonthlyAmount, window: monthlyLimitWindow},
+	}
+	for _, w := range windows {
+		if !w.limit.IsPositive() {
+			continue
+		}
+
+		sent, err := dbtx.SumAccountDebits(ctx, transferdbgen.SumAccountDebitsParams{
+			AccountID:  source.AccountID,
+			Since:      now.Add(-w.window),
+			EntryTypes: limitedEntryTypes,
+		})
+		if err != nil {
+			return fmt.Errorf("sum account debits: %d: %w", source.AccountID, err)
+		}
+
+		if sent.Add(amount).GreaterThan(w.limit) {
+			left := decimal.Max(w.limit.Sub(sent), decimal.Zero)
+			return fmt.Errorf("%w: %s limit %s, %s left", ErrLimitExceeded, w.name, w.limit, left)
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,16 @@
 package transferbus
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
 	"time"
 
 	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
@@ -12,29 +19,665 @@ type AccountBalance struct {
 	Balance   decimal.Decimal
 }
 
+// AccountType describes what an account is used for. It does not change how
+// funds move.
+type AccountType string
+
+const (
+	AccountTypeCustomer    AccountType = "customer"
+	AccountTypeOperational AccountType = "operational"
+	AccountTypeFee         AccountType = "fee"
+	AccountTypeSuspense    AccountType = "suspense"
+)
+
+// known reports whether t is one of the account types.
+func (t AccountType) known() bool {
+	switch t {
+	case AccountTypeCustomer, AccountTypeOperational, AccountTypeFee, AccountTypeSuspense:
+		return true
+	}
+	return false
+}
+
+// TransferLimits caps the outbound transfers of an account, in the currency of
+// the account. MaxAmount caps a single transfer, while DailyAmount and
+// MonthlyAmount cap the debits of the last 24 hours and 30 days. Zero amounts
+// are not enforced.
+type TransferLimits struct {
+	MaxAmount     decimal.Decimal
+	DailyAmount   decimal.Decimal
+	MonthlyAmount decimal.Decimal
+}
+
+func (l TransferLimits) negative() bool {
+	return l.MaxAmount.IsNegative() || l.DailyAmount.IsNegative() || l.MonthlyAmount.IsNegative()
+}
+
+func fromDBAccountLimit(dbLimit transferdbgen.AccountLimit) TransferLimits {
+	return TransferLimits{
+		MaxAmount:     dbLimit.MaxAmount,
+		DailyAmount:   dbLimit.DailyAmount,
+		MonthlyAmount: dbLimit.MonthlyAmount,
+	}
+}
+
+func fromDBAccountTypeLimit(dbLimit transferdbgen.AccountTypeLimit) TransferLimits {
+	return TransferLimits{
+		MaxAmount:     dbLimit.MaxAmount,
+		DailyAmount:   dbLimit.DailyAmount,
+		MonthlyAmount: dbLimit.MonthlyAmount,
+	}
+}
+
+// NewAccount is the data needed to open an account. Accounts without an id
+// are given one from the range starting at FirstAllocatedAccountID, accounts
+// without a currency are opened in currency.Default and accounts without a
+// type are customer accounts.
 type NewAccount struct {
 	AccountID      int64
+	Currency       currency.Currency
 	InitialBalance decimal.Decimal
+	OwnerRef       string
+	Type           AccountType
+	Name           string
+	Labels         []string
 }
 
+// UpdateAccount holds the metadata of an account to change. Nil fields are
+// left unchanged.
+type UpdateAccount struct {
+	OwnerRef *string
+	Type     *AccountType
+	Name     *string
+	Labels   *[]string
+}
+
+// AccountFilter selects a page of accounts ordered by account id. Cursor is the
+// AccountID of the last account of the previous page, and an account matches
+// Labels when it carries all of them. Zero values leave a filter unset.
+type AccountFilter struct {
+	OwnerRef string
+	Type     AccountType
+	Labels   []string
+	Cursor   int64
+	Limit    int
+}
+
+// AccountStatus describes where an account is in its lifecycle. Only active
+// accounts send or receive funds.
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+// Account is an account and its ledger balance. HeldBalance is the part of the
+// balance reserved by authorized holds, which cannot be debited.
 type Account struct {
 	AccountID        int64
+	Currency         currency.Currency
 	Balance          decimal.Decimal
+	HeldBalance      decimal.Decimal
+	Status           AccountStatus
+	OwnerRef         string
+	Type             AccountType
+	Name             string
+	Labels           []string
+	OverdraftLimit   decimal.Decimal
 	CreatedDate      time.Time
 	LastModifiedDate time.Time
 }
 
-func fromDBAccount(dbAccount transferdbgen.Account) Account {
+// AvailableBalance is the part of the balance that is not on hold.
+func (a Account) AvailableBalance() decimal.Decimal {
+	return a.Balance.Sub(a.HeldBalance)
+}
+
+// AvailableCredit is how much can still be debited from the account, which is
+// the available balance plus the overdraft limit.
+func (a Account) AvailableCredit() decimal.Decimal {
+	return a.AvailableBalance().Add(a.OverdraftLimit)
+}
+
+// AccountClosure is a request to close an account. SweepAccountID receives
+// the remaining balance and may be zero when the balance is already zero.
+type AccountClosure struct {
+	AccountID      int64
+	SweepAccountID int64
+}
+
+func fromDBAccount(dbAccount transferdbgen.Account) (Account, error) {
+	cur, err := currency.Parse(dbAccount.Currency)
+	if err != nil {
+		return Account{}, fmt.Errorf("parse currency: account[%d]: %w", dbAccount.AccountID, err)
+	}
+
 	return Account{
 		AccountID:        dbAccount.AccountID,
+		Currency:         cur,
 		Balance:          dbAccount.Balance,
+		HeldBalance:      dbAccount.HeldBalance,
+		Status:           AccountStatus(dbAccount.Status),
+		OwnerRef:         dbAccount.OwnerRef,
+		Type:             AccountType(dbAccount.AccountType),
+		Name:             dbAccount.Name,
+		Labels:           dbAccount.Labels,
+		OverdraftLimit:   dbAccount.OverdraftLimit,
 		CreatedDate:      dbAccount.CreatedDate,
 		LastModifiedDate: dbAccount.LastModifiedDate,
-	}
+	}, nil
 }
 
+// Transaction is a request to move funds between two accounts holding the same
+// currency. When Currency is set it must match the currency of both accounts.
+// Accounts holding different currencies need a QuoteID, whose source amount
+// must equal Amount.
 type Transaction struct {
 	SourceAccountID      int64
 	DestinationAccountID int64
 	Amount               decimal.Decimal
+	Currency             currency.Currency
+	QuoteID              uuid.UUID
+	IdempotencyKey       string
+}
+
+// requestHash fingerprints the payload of the transaction so that a replayed
+// idempotency key can be matched against the request that first used it.
+func (t Transaction) requestHash() string {
+	payload := fmt.Sprintf("%d:%d:%s", t.SourceAccountID, t.DestinationAccountID, t.Amount.String())
+	if !t.Currency.IsZero() {
+		payload += ":" + t.Currency.String()
+	}
+	if t.QuoteID != uuid.Nil {
+		payload += ":" + t.QuoteID.String()
+	}
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// EntryType describes why a journal entry was recorded.
+type EntryType string
+
+const (
+	EntryTypeOpeningBalance EntryType = "opening_balance"
+	EntryTypeTransfer       EntryType = "transfer"
+	EntryTypeFXTransfer     EntryType = "fx_transfer"
+	EntryTypeReversal       EntryType = "reversal"
+	EntryTypeCapture        EntryType = "capture"
+	EntryTypeClosure        EntryType = "closure"
+
+	// EntryTypeLegacy is a leg recorded before journal entries existed that
+	// could not be paired into a transfer, balanced against the equity account.
+	EntryTypeLegacy EntryType = "legacy"
+)
+
+// Reversal is a request to send back Amount of a transfer, denominated in the
+// currency the transfer was sent in. A zero Amount reverses whatever is left
+// of the transfer.
+type Reversal struct {
+	TransferID uuid.UUID
+	Amount     decimal.Decimal
+}
+
+// Transfer is a journal entry moving funds from the source to the destination
+// account. Its TransferID links the postings recorded for it, which are its
+// legs and always sum to zero in every currency. Amount is debited from the
+// source in Currency and DestinationAmount credited to the destination in
+// DestinationCurrency, converted at Rate. QuoteID is set when the transfer
+// used an fx quote and ReversalOf when it sends back funds of another transfer.
+type Transfer struct {
+	TransferID           uuid.UUID
+	EntryType            EntryType
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Amount               decimal.Decimal
+	Currency             currency.Currency
+	DestinationAmount    decimal.Decimal
+	DestinationCurrency  currency.Currency
+	Rate                 decimal.Decimal
+	QuoteID              uuid.UUID
+	ReversalOf           uuid.UUID
+	CreatedDate          time.Time
+	Legs                 []TransferLeg
+	// SourceBalance is the balance the source account was left with. It is
+	// only set by CreateTransaction, and a replayed idempotency key returns the
+	// balance its transfer left.
+	SourceBalance decimal.Decimal
+}
+
+func toTransfer(dbEntry transferdbgen.JournalEntry, legs []TransferLeg) (Transfer, error) {
+	cur, err := currency.Parse(dbEntry.Currency)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("parse currency: transfer[%s]: %w", dbEntry.TransferID, err)
+	}
+
+	destCur, err := currency.Parse(dbEntry.DestinationCurrency)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("parse destination currency: transfer[%s]: %w", dbEntry.TransferID, err)
+	}
+
+	return Transfer{
+		TransferID:           dbEntry.TransferID,
+		EntryType:            EntryType(dbEntry.EntryType),
+		SourceAccountID:      dbEntry.SourceAccountID,
+		DestinationAccountID: dbEntry.DestinationAccountID,
+		Amount:               dbEntry.Amount,
+		Currency:             cur,
+		DestinationAmount:    dbEntry.DestinationAmount,
+		DestinationCurrency:  destCur,
+		Rate:                 dbEntry.Rate,
+		QuoteID:              dbEntry.QuoteID.Bytes,
+		ReversalOf:           dbEntry.ReversalOf.Bytes,
+		CreatedDate:          dbEntry.CreatedDate,
+		Legs:                 legs,
+	}, nil
+}
+
+// TransferLeg is a posting of a transfer against a single account. Debits carry
+// a negative amount.
+type TransferLeg struct {
+	TransactionID int64
+	TransferID    uuid.UUID
+	AccountID     int64
+	Amount        decimal.Decimal
+	Currency      currency.Currency
+	CreatedDate   time.Time
+}
+
+func fromDBTransaction(dbTransaction transferdbgen.Transaction) (TransferLeg, error) {
+	cur, err := currency.Parse(dbTransaction.Currency)
+	if err != nil {
+		return TransferLeg{}, fmt.Errorf("parse currency: transaction[%d]: %w", dbTransaction.TransactionID, err)
+	}
+
+	return TransferLeg{
+		TransactionID: dbTransaction.TransactionID,
+		TransferID:    dbTransaction.TransferID,
+		AccountID:     dbTransaction.AccountID,
+		Amount:        dbTransaction.Amount,
+		Currency:      cur,
+		CreatedDate:   dbTransaction.CreatedDate,
+	}, nil
+}
+
+func fromDBTransactions(dbTransactions []transferdbgen.Transaction) ([]TransferLeg, error) {
+	legs := make([]TransferLeg, len(dbTransactions))
+	for i, dbTransaction := range dbTransactions {
+		leg, err := fromDBTransaction(dbTransaction)
+		if err != nil {
+			return nil, err
+		}
+		legs[i] = leg
+	}
+	return legs, nil
+}
+
+// Direction filters the legs of an account by the side they were posted on.
+type Direction string
+
+const (
+	DirectionDebit  Direction = "debit"
+	DirectionCredit Direction = "credit"
+)
+
+// TransactionFilter selects a page of the transaction history of an account,
+// ordered from the latest transaction. Cursor is the TransactionID of the last
+// leg of the previous page and zero values leave a filter unset.
+type TransactionFilter struct {
+	AccountID int64
+	Cursor    int64
+	StartDate time.Time
+	EndDate   time.Time
+	Direction Direction
+	Limit     int
+}
+
+func toDBAccountFilter(filter AccountFilter) transferdbgen.QueryAccountsParams {
+	labels := filter.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	// system accounts have negative ids, so the first page starts below them
+	after := filter.Cursor
+	if after == 0 {
+		after = math.MinInt64
+	}
+
+	return transferdbgen.QueryAccountsParams{
+		AfterAccountID: after,
+		OwnerRef:       pgtype.Text{String: filter.OwnerRef, Valid: filter.OwnerRef != ""},
+		AccountType:    pgtype.Text{String: string(filter.Type), Valid: filter.Type != ""},
+		Labels:         labels,
+		RowLimit:       int32(filter.Limit),
+	}
+}
+
+func toDBUpdateAccount(accountID int64, ua UpdateAccount) transferdbgen.UpdateAccountMetadataParams {
+	params := transferdbgen.UpdateAccountMetadataParams{
+		AccountID: accountID,
+	}
+	if ua.OwnerRef != nil {
+		params.OwnerRef = pgtype.Text{String: *ua.OwnerRef, Valid: true}
+	}
+	if ua.Type != nil {
+		params.AccountType = pgtype.Text{String: string(*ua.Type), Valid: true}
+	}
+	if ua.Name != nil {
+		params.Name = pgtype.Text{String: *ua.Name, Valid: true}
+	}
+	// a nil slice is sent as NULL, which leaves the labels unchanged
+	if ua.Labels != nil {
+		params.Labels = *ua.Labels
+		if params.Labels == nil {
+			params.Labels = []string{}
+		}
+	}
+	return params
+}
+
+func toDBTransactionFilter(filter TransactionFilter) transferdbgen.QueryAccountTransactionsParams {
+	return transferdbgen.QueryAccountTransactionsParams{
+		AccountID: filter.AccountID,
+		Cursor:    pgtype.Int8{Int64: filter.Cursor, Valid: filter.Cursor != 0},
+		StartDate: pgtype.Timestamptz{Time: filter.StartDate, Valid: !filter.StartDate.IsZero()},
+		EndDate:   pgtype.Timestamptz{Time: filter.EndDate, Valid: !filter.EndDate.IsZero()},
+		Direction: pgtype.Text{String: string(filter.Direction), Valid: filter.Direction != ""},
+		RowLimit:  int32(filter.Limit),
+	}
+}
+
+// NewQuote is a request to price a transfer of Amount, in the currency of the
+// source account, to an account holding another currency.
+type NewQuote struct {
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Amount               decimal.Decimal
+}
+
+// Quote locks the rate of a cross-currency transfer between two accounts until
+// ExpiresDate. A transfer made with the quote debits exactly SourceAmount and
+// credits exactly DestinationAmount.
+type Quote struct {
+	QuoteID              uuid.UUID
+	SourceAccountID      int64
+	DestinationAccountID int64
+	SourceCurrency       currency.Currency
+	DestinationCurrency  currency.Currency
+	SourceAmount         decimal.Decimal
+	DestinationAmount    decimal.Decimal
+	Rate                 decimal.Decimal
+	CreatedDate          time.Time
+	ExpiresDate          time.Time
+}
+
+func toQuote(dbQuote transferdbgen.FxQuote) (Quote, error) {
+	srcCur, err := currency.Parse(dbQuote.SourceCurrency)
+	if err != nil {
+		return Quote{}, fmt.Errorf("parse source currency: quote[%s]: %w", dbQuote.QuoteID, err)
+	}
+
+	destCur, err := currency.Parse(dbQuote.DestinationCurrency)
+	if err != nil {
+		return Quote{}, fmt.Errorf("parse destination currency: quote[%s]: %w", dbQuote.QuoteID, err)
+	}
+
+	return Quote{
+		QuoteID:              dbQuote.QuoteID,
+		SourceAccountID:      dbQuote.SourceAccountID,
+		DestinationAccountID: dbQuote.DestinationAccountID,
+		SourceCurrency:       srcCur,
+		DestinationCurrency:  destCur,
+		SourceAmount:         dbQuote.SourceAmount,
+		DestinationAmount:    dbQuote.DestinationAmount,
+		Rate:                 dbQuote.Rate,
+		CreatedDate:          dbQuote.CreatedDate,
+		ExpiresDate:          dbQuote.ExpiresDate,
+	}, nil
+}
+
+// HoldStatus describes where a hold is in its lifecycle. Only authorized holds
+// reserve funds.
+type HoldStatus string
+
+const (
+	HoldStatusAuthorized HoldStatus = "authorized"
+	HoldStatusCaptured   HoldStatus = "captured"
+	HoldStatusVoided     HoldStatus = "voided"
+	HoldStatusExpired    HoldStatus = "expired"
+)
+
+// NewHold is a request to reserve Amount on the source account for a later
+// transfer to the destination account. When Currency is set it must match the
+// currency of both accounts, and holds without ExpiresIn last DefaultHoldTTL.
+type NewHold struct {
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Amount               decimal.Decimal
+	Currency             currency.Currency
+	ExpiresIn            time.Duration
+}
+
+// Hold reserves Amount on the source account until it is captured, voided or
+// expires. TransferID is the transfer created by its capture.
+type Hold struct {
+	HoldID               uuid.UUID
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Currency             currency.Currency
+	Amount               decimal.Decimal
+	CapturedAmount       decimal.Decimal
+	Status               HoldStatus
+	TransferID           uuid.UUID
+	CreatedDate          time.Time
+	ExpiresDate          time.Time
+	LastModifiedDate     time.Time
+}
+
+func toHold(dbHold transferdbgen.Hold) (Hold, error) {
+	cur, err := currency.Parse(dbHold.Currency)
+	if err != nil {
+		return Hold{}, fmt.Errorf("parse currency: hold[%s]: %w", dbHold.HoldID, err)
+	}
+
+	return Hold{
+		HoldID:               dbHold.HoldID,
+		SourceAccountID:      dbHold.SourceAccountID,
+		DestinationAccountID: dbHold.DestinationAccountID,
+		Currency:             cur,
+		Amount:               dbHold.Amount,
+		CapturedAmount:       dbHold.CapturedAmount,
+		Status:               HoldStatus(dbHold.Status),
+		TransferID:           dbHold.TransferID.Bytes,
+		CreatedDate:          dbHold.CreatedDate,
+		ExpiresDate:          dbHold.ExpiresDate,
+		LastModifiedDate:     dbHold.LastModifiedDate,
+	}, nil
+}
+
+// HoldCapture is a request to transfer Amount of a hold to its destination. A
+// zero Amount captures the whole hold.
+type HoldCapture struct {
+	HoldID uuid.UUID
+	Amount decimal.Decimal
+}
+
+// ScheduleStatus describes where a scheduled transfer is in its lifecycle. Only
+// scheduled transfers are run.
+type ScheduleStatus string
+
+const (
+	ScheduleStatusScheduled ScheduleStatus = "scheduled"
+	ScheduleStatusCompleted ScheduleStatus = "completed"
+	ScheduleStatusFailed    ScheduleStatus = "failed"
+	ScheduleStatusCancelled ScheduleStatus = "cancelled"
+)
+
+// NewScheduledTransfer is a request to transfer Amount at StartDate, and again
+// at every occurrence of Recurrence when it is set. Without a StartDate a one
+// off transfer is due immediately and a recurring one at its next occurrence.
+// When Currency is set it must match the currency of both accounts.
+type NewScheduledTransfer struct {
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Amount               decimal.Decimal
+	Currency             currency.Currency
+	Recurrence           string
+	StartDate            time.Time
+}
+
+// UpdateScheduledTransfer holds the changes to a scheduled transfer. Nil
+// fields are left unchanged, and an empty Recurrence makes the transfer a one
+// off.
+type UpdateScheduledTransfer struct {
+	Amount      *decimal.Decimal
+	Recurrence  *string
+	NextRunDate *time.Time
+}
+
+// ScheduledTransfer moves Amount from the source to the destination account at
+// NextRunDate. LastTransferID and LastError hold the outcome of the last run.
+type ScheduledTransfer struct {
+	ScheduleID           uuid.UUID
+	SourceAccountID      int64
+	DestinationAccountID int64
+	Currency             currency.Currency
+	Amount               decimal.Decimal
+	Recurrence           string
+	Status               ScheduleStatus
+	NextRunDate          time.Time
+	RunCount             int
+	LastRunDate          time.Time
+	LastTransferID       uuid.UUID
+	LastError            string
+	CreatedDate          time.Time
+	LastModifiedDate     time.Time
+}
+
+func toScheduledTransfer(dbSchedule transferdbgen.ScheduledTransfer) (ScheduledTransfer, error) {
+	cur, err := currency.Parse(dbSchedule.Currency)
+	if err != nil {
+		return ScheduledTransfer{}, fmt.Errorf("parse currency: schedule[%s]: %w", dbSchedule.ScheduleID, err)
+	}
+
+	return ScheduledTransfer{
+		ScheduleID:           dbSchedule.ScheduleID,
+		SourceAccountID:      dbSchedule.SourceAccountID,
+		DestinationAccountID: dbSchedule.DestinationAccountID,
+		Currency:             cur,
+		Amount:               dbSchedule.Amount,
+		Recurrence:           dbSchedule.Recurrence,
+		Status:               ScheduleStatus(dbSchedule.Status),
+		NextRunDate:          dbSchedule.NextRunDate,
+		RunCount:             int(dbSchedule.RunCount),
+		LastRunDate:          dbSchedule.LastRunDate.Time,
+		LastTransferID:       dbSchedule.LastTransferID.Bytes,
+		LastError:            dbSchedule.LastError,
+		CreatedDate:          dbSchedule.CreatedDate,
+		LastModifiedDate:     dbSchedule.LastModifiedDate,
+	}, nil
+}
+
+// WebhookStatus describes whether a webhook subscription is sent its events.
+// Events of a paused subscription are kept and sent once it is active again.
+type WebhookStatus string
+
+const (
+	WebhookStatusActive WebhookStatus = "active"
+	WebhookStatusPaused WebhookStatus = "paused"
+)
+
+// NewWebhookSubscription is a request to send the events of an account to URL.
+type NewWebhookSubscription struct {
+	AccountID int64
+	URL       string
+}
+
+// UpdateWebhookSubscription holds the changes to a webhook subscription. Nil
+// fields are left unchanged.
+type UpdateWebhookSubscription struct {
+	URL    *string
+	Status *WebhookStatus
+}
+
+// WebhookSubscription sends the events of an account to URL. Every delivery is
+// signed with Secret, which only the subscriber and the service know.
+type WebhookSubscription struct {
+	SubscriptionID   uuid.UUID
+	AccountID        int64
+	URL              string
+	Secret           string
+	Status           WebhookStatus
+	CreatedDate      time.Time
+	LastModifiedDate time.Time
+}
+
+func toWebhookSubscription(dbSub transferdbgen.WebhookSubscription) WebhookSubscription {
+	return WebhookSubscription{
+		SubscriptionID:   dbSub.SubscriptionID,
+		AccountID:        dbSub.AccountID,
+		URL:              dbSub.Url,
+		Secret:           dbSub.Secret,
+		Status:           WebhookStatus(dbSub.Status),
+		CreatedDate:      dbSub.CreatedDate,
+		LastModifiedDate: dbSub.LastModifiedDate,
+	}
+}
+
+// DeliveryStatus describes where a webhook delivery is in its lifecycle.
+// Pending deliveries are retried until they are delivered or run out of
+// attempts, after which they are dead.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusDead      DeliveryStatus = "dead"
+)
+
+// WebhookDelivery sends an event to a webhook subscription. LastStatusCode and
+// LastError hold the outcome of the last attempt, and a pending delivery is
+// attempted again at NextAttemptDate.
+type WebhookDelivery struct {
+	DeliveryID      int64
+	SubscriptionID  uuid.UUID
+	EventID         int64
+	Status          DeliveryStatus
+	Attempts        int
+	NextAttemptDate time.Time
+	LastAttemptDate time.Time
+	LastStatusCode  int
+	LastError       string
+	CreatedDate     time.Time
+}
+
+func toWebhookDeliveries(dbDeliveries []transferdbgen.WebhookDelivery) []WebhookDelivery {
+	deliveries := make([]WebhookDelivery, len(dbDeliveries))
+	for i, d := range dbDeliveries {
+		deliveries[i] = WebhookDelivery{
+			DeliveryID:      d.DeliveryID,
+			SubscriptionID:  d.SubscriptionID,
+			EventID:         d.EventID,
+			Status:          DeliveryStatus(d.Status),
+			Attempts:        int(d.Attempts),
+			NextAttemptDate: d.NextAttemptDate,
+			LastAttemptDate: d.LastAttemptDate.Time,
+			LastStatusCode:  int(d.LastStatusCode),
+			LastError:       d.LastError,
+			CreatedDate:     d.CreatedDate,
+		}
+	}
+	return deliveries
+}
+
+// DeliveryFilter selects a page of the delivery log of a webhook subscription,
+// newest first. Cursor is the DeliveryID of the last delivery of the previous
+// page.
+type DeliveryFilter struct {
+	SubscriptionID uuid.UUID
+	Status         DeliveryStatus
+	Cursor         int64
+	Limit          int
 }
@@ -0,0 +1,82 @@
+package transferbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/shopspring/decimal"
+)
+
+// SetOverdraftLimit changes how far the balance of the account may go below
+// zero. A limit lower than the overdraft the account already uses is rejected
+// with ErrOverdraftInUse, and one lower than the overdraft its authorized holds
+// rely on with ErrOverdraftHeld.
+func (b *Bus) SetOverdraftLimit(ctx context.Context, accountID int64, limit decimal.Decimal) (Account, error) {
+	if limit.IsNegative() {
+		return Account{}, ErrNegativeOverdraft
+	}
+
+	var acc Account
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		acc, err = setOverdraftLimit(ctx, dbtx, accountID, limit)
+		return err
+	})
+	if err != nil {
+		return Account{}, err
+	}
+	return acc, nil
+}
+
+func setOverdraftLimit(ctx context.Context, dbtx transferdb.TxQuerier, accountID int64, limit decimal.Decimal) (Account, error) {
+	accounts, err := dbtx.LockAccounts(ctx, []int64{accountID})
+	if err != nil {
+		return Account{}, fmt.Errorf("lock accounts: %w", err)
+	}
+	if len(accounts) != 1 {
+		return Account{}, ErrAccNotFound
+	}
+
+	acc, err := fromDBAccount(accounts[0])
+	if err != nil {
+		return Account{}, err
+	}
+	limit = acc.Currency.Round(limit)
+
+	if acc.Balance.Add(limit).IsNegative() {
+		return Account{}, ErrOverdraftInUse
+	}
+
+	// holds must stay capturable, their funds were reserved against the limit
+	if acc.AvailableBalance().Add(limit).IsNegative() {
+		return Account{}, ErrOverdraftHeld
+	}
+
+	dbAccount, err := dbtx.UpdateOverdraftLimit(ctx, transferdbgen.UpdateOverdraftLimitParams{
+		OverdraftLimit: limit,
+		AccountID:      accountID,
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("update overdraft limit: %d: %w", accountID, err)
+	}
+
+	return fromDBAccount(dbAccount)
+}
+
+// insufficientFunds reports how much the account could still have been
+// debited when a debit or a hold did not fit.
+func insufficientFunds(ctx context.Context, dbtx transferdb.TxQuerier, accountID int64) error {
+	dbAccount, err := dbtx.GetAccount(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("get account: %d: %w", accountID, err)
+	}
+
+	acc, err := fromDBAccount(dbAccount)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: account[%d]: available credit %s", ErrInsufficientFunds, accountID, acc.AvailableCredit())
+}
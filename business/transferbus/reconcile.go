@@ -0,0 +1,137 @@
+package transferbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultReconcileBatchSize is the number of accounts scanned per query when no
+// batch size is configured.
+const DefaultReconcileBatchSize = 500
+
+// ReconcileConfig controls how a reconciliation is run.
+type ReconcileConfig struct {
+	// BatchSize is the number of accounts read per query.
+	BatchSize int
+
+	// Alert logs every drift at error level so the logger Events.Error hook
+	// fires for each finding.
+	Alert bool
+}
+
+// AccountDrift describes an account whose stored balance differs from the sum
+// of its transactions.
+type AccountDrift struct {
+	AccountID     int64
+	Currency      string
+	Balance       decimal.Decimal
+	LedgerBalance decimal.Decimal
+	Drift         decimal.Decimal
+}
+
+// Reconciliation is the outcome of comparing every account balance against its
+// transactions. TotalBalances holds the sum of all balances per currency code.
+type Reconciliation struct {
+	AccountsScanned int
+	Drifts          []AccountDrift
+	TotalBalances   map[string]decimal.Decimal
+	ReconciledDate  time.Time
+}
+
+// Balanced reports whether every account matches its transactions and the
+// ledger sums to zero in every currency.
+func (r Reconciliation) Balanced() bool {
+	for _, total := range r.TotalBalances {
+		if !total.IsZero() {
+			return false
+		}
+	}
+	return len(r.Drifts) == 0
+}
+
+// Reconcile scans all accounts in batches and compares accounts.balance against
+// the sum of the account's transactions. Every batch is read in the same read
+// only transaction, so at repeatable read or above transfers committed while
+// the scan runs cannot show up as drift.
+func (b *Bus) Reconcile(ctx context.Context, cfg ReconcileConfig) (Reconciliation, error) {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultReconcileBatchSize
+	}
+
+	var result Reconciliation
+	err := b.store.InTx(ctx, b.readTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		result, err = b.reconcile(ctx, dbtx, cfg, batchSize)
+		return err
+	})
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	return result, nil
+}
+
+// reconcile scans the accounts in batches of batchSize as part of the given
+// database transaction.
+func (b *Bus) reconcile(ctx context.Context, dbtx transferdb.TxQuerier, cfg ReconcileConfig, batchSize int) (Reconciliation, error) {
+	result := Reconciliation{
+		Drifts:         []AccountDrift{},
+		TotalBalances:  make(map[string]decimal.Decimal),
+		ReconciledDate: time.Now(),
+	}
+
+	// account ids are signed, so the first batch starts below every system account
+	afterAccountID := int64(-1 << 63)
+	for {
+		rows, err := dbtx.ReconcileAccounts(ctx, transferdbgen.ReconcileAccountsParams{
+			AfterAccountID: afterAccountID,
+			RowLimit:       int32(batchSize),
+		})
+		if err != nil {
+			return Reconciliation{}, fmt.Errorf("reconcile accounts: after[%d]: %w", afterAccountID, err)
+		}
+
+		for _, row := range rows {
+			result.AccountsScanned++
+			result.TotalBalances[row.Currency] = result.TotalBalances[row.Currency].Add(row.Balance)
+
+			if row.Balance.Equal(row.LedgerBalance) {
+				continue
+			}
+
+			drift := AccountDrift{
+				AccountID:     row.AccountID,
+				Currency:      row.Currency,
+				Balance:       row.Balance,
+				LedgerBalance: row.LedgerBalance,
+				Drift:         row.Balance.Sub(row.LedgerBalance),
+			}
+			result.Drifts = append(result.Drifts, drift)
+
+			if cfg.Alert {
+				b.log.Error(ctx, "reconciliation", "status", "balance drift", "accountId", drift.AccountID,
+					"currency", drift.Currency, "balance", drift.Balance, "ledgerBalance", drift.LedgerBalance, "drift", drift.Drift)
+			}
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+		afterAccountID = rows[len(rows)-1].AccountID
+	}
+
+	if cfg.Alert {
+		for cur, total := range result.TotalBalances {
+			if !total.IsZero() {
+				b.log.Error(ctx, "reconciliation", "status", "ledger does not sum to zero", "currency", cur, "totalBalance", total)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// ReverseTransfer sends back all or part of a transfer with compensating
+// postings that reference it. The reversals of a transfer can never add up to
+// more than it sent, and the reversal fails with ErrInsufficientFunds when the
+// destination account no longer holds the funds.
+func (b *Bus) ReverseTransfer(ctx context.Context, reversal Reversal) (Transfer, error) {
+	if reversal.Amount.IsNegative() {
+		return Transfer{}, ErrNegativeBalance
+	}
+
+	var transfer Transfer
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		transfer, err = reverseTransfer(ctx, dbtx, reversal)
+		return err
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	return transfer, nil
+}
+
+// reverseTransfer posts the reversal as part of the given database transaction.
+func reverseTransfer(ctx context.Context, dbtx transferdb.TxQuerier, reversal Reversal) (Transfer, error) {
+	// concurrent reversals of the same transfer wait on its journal entry, so
+	// each one sees the amounts reversed before it
+	dbEntry, err := dbtx.LockJournalEntry(ctx, reversal.TransferID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Transfer{}, ErrTransferNotFound
+		}
+		return Transfer{}, fmt.Errorf("lock journal entry: %s: %w", reversal.TransferID, err)
+	}
+
+	original, err := toTransfer(dbEntry, nil)
+	if err != nil {
+		return Transfer{}, err
+	}
+	switch original.EntryType {
+	case EntryTypeTransfer, EntryTypeFXTransfer, EntryTypeCapture:
+	default:
+		return Transfer{}, ErrNotReversible
+	}
+
+	entry, err := reversalJournalEntry(ctx, dbtx, original, reversal.Amount)
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	transfer, err := postJournalEntry(ctx, dbtx, entry)
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	return transfer, nil
+}
+
+// reversalJournalEntry builds the journal entry sending amount of the original
+// transfer back from its destination to its source. Cross-currency transfers
+// are reversed at their original rate through the same fx accounts, and the
+// last reversal returns exactly what is left on both sides.
+func reversalJournalEntry(ctx context.Context, dbtx transferdb.TxQuerier, original Transfer, amount decimal.Decimal) (journalEntry, error) {
+	reversed, err := dbtx.GetReversedAmounts(ctx, pgtype.UUID{Bytes: original.TransferID, Valid: true})
+	if err != nil {
+		return journalEntry{}, fmt.Errorf("get reversed amounts: %s: %w", original.TransferID, err)
+	}
+
+	remaining := original.Amount.Sub(reversed.ReversedAmount)
+	remainingDest := original.DestinationAmount.Sub(reversed.ReversedDestinationAmount)
+
+	if amount.IsZero() {
+		amount = remaining
+	} else if amount = original.Currency.Round(amount); amount.IsZero() {
+		return journalEntry{}, ErrZeroAmount
+	}
+	if !amount.IsPositive() || amount.GreaterThan(remaining) {
+		return journalEntry{}, ErrReversalExceeded
+	}
+
+	entry := journalEntry{
+		TransferID:           uuid.New(),
+		EntryType:            EntryTypeReversal,
+		SourceAccountID:      original.DestinationAccountID,
+		DestinationAccountID: original.SourceAccountID,
+		Amount:               amount,
+		Currency:             original.Currency,
+		ReversalOf:           original.TransferID,
+		Postings: []posting{
+			{AccountID: original.DestinationAccountID, Amount: amount.Neg(), Currency: original.Currency},
+			{AccountID: original.SourceAccountID, Amount: amount, Currency: original.Currency},
+		},
+	}
+	if original.EntryType != EntryTypeFXTransfer {
+		return entry, nil
+	}
+
+	destAmount := remainingDest
+	if amount.LessThan(remaining) {
+		destAmount = original.DestinationCurrency.Round(original.DestinationAmount.Mul(amount).Div(original.Amount))
+	}
+
+	fxSrcAccountID, err := systemAccount(ctx, dbtx, SystemAccountFX, original.Currency)
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	fxDstAccountID, err := systemAccount(ctx, dbtx, SystemAccountFX, original.DestinationCurrency)
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	// the reversal is debited in the currency the original transfer credited
+	entry.Amount = destAmount
+	entry.Currency = original.DestinationCurrency
+	entry.DestinationAmount = amount
+	entry.DestinationCurrency = original.Currency
+	entry.Rate = decimal.NewFromInt(1).DivRound(original.Rate, fxbus.RateScale)
+	entry.Postings = []posting{
+		{AccountID: original.DestinationAccountID, Amount: destAmount.Neg(), Currency: original.DestinationCurrency},
+		{AccountID: fxDstAccountID, Amount: destAmount, Currency: original.DestinationCurrency},
+		{AccountID: fxSrcAccountID, Amount: amount.Neg(), Currency: original.Currency},
+		{AccountID: original.SourceAccountID, Amount: amount, Currency: original.Currency},
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,325 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/business/types/cron"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateScheduledTransfer schedules a transfer between two accounts holding
+// the same currency.
+func (b *Bus) CreateScheduledTransfer(ctx context.Context, ns NewScheduledTransfer) (ScheduledTransfer, error) {
+	if ns.Amount.IsNegative() {
+		return ScheduledTransfer{}, ErrNegativeBalance
+	}
+	if ns.SourceAccountID == ns.DestinationAccountID {
+		return ScheduledTransfer{}, ErrSameAccount
+	}
+
+	schedule, err := parseRecurrence(ns.Recurrence)
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+
+	now := time.Now()
+	nextRun := ns.StartDate
+	if nextRun.IsZero() {
+		nextRun = now
+		if !schedule.IsZero() {
+			nextRun = schedule.Next(now)
+		}
+	}
+
+	var st ScheduledTransfer
+	err = b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		accounts, err := dbtx.GetAccounts(ctx, []int64{ns.SourceAccountID, ns.DestinationAccountID})
+		if err != nil {
+			return fmt.Errorf("get accounts: %w", err)
+		}
+		if len(accounts) != 2 {
+			return ErrAccNotFound
+		}
+
+		cur, err := transferCurrency(Transaction{
+			SourceAccountID:      ns.SourceAccountID,
+			DestinationAccountID: ns.DestinationAccountID,
+			Currency:             ns.Currency,
+		}, accounts)
+		if err != nil {
+			return err
+		}
+
+		amount := cur.Round(ns.Amount)
+		if amount.IsZero() {
+			return ErrZeroAmount
+		}
+
+		dbSchedule, err := dbtx.CreateScheduledTransfer(ctx, transferdbgen.CreateScheduledTransferParams{
+			ScheduleID:           uuid.New(),
+			SourceAccountID:      ns.SourceAccountID,
+			DestinationAccountID: ns.DestinationAccountID,
+			Currency:             cur.String(),
+			Amount:               amount,
+			Recurrence:           schedule.String(),
+			Status:               string(ScheduleStatusScheduled),
+			NextRunDate:          nextRun,
+			CreatedDate:          now,
+			LastModifiedDate:     now,
+		})
+		if err != nil {
+			return fmt.Errorf("create scheduled transfer: %w", err)
+		}
+
+		st, err = toScheduledTransfer(dbSchedule)
+		return err
+	})
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+	return st, nil
+}
+
+// QueryScheduledTransfer returns the scheduled transfer with the given id.
+func (b *Bus) QueryScheduledTransfer(ctx context.Context, scheduleID uuid.UUID) (ScheduledTransfer, error) {
+	dbSchedule, err := b.store.GetScheduledTransfer(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ScheduledTransfer{}, ErrScheduleNotFound
+		}
+		return ScheduledTransfer{}, fmt.Errorf("get scheduled transfer: %s: %w", scheduleID, err)
+	}
+
+	return toScheduledTransfer(dbSchedule)
+}
+
+// UpdateScheduledTransfer changes the amount, recurrence or next run of a
+// transfer that is still scheduled. A new recurrence without a next run is
+// first run at its next occurrence.
+func (b *Bus) UpdateScheduledTransfer(ctx context.Context, scheduleID uuid.UUID, us UpdateScheduledTransfer) (ScheduledTransfer, error) {
+	if us.Amount != nil && us.Amount.IsNegative() {
+		return ScheduledTransfer{}, ErrNegativeBalance
+	}
+
+	var schedule cron.Schedule
+	if us.Recurrence != nil {
+		var err error
+		if schedule, err = parseRecurrence(*us.Recurrence); err != nil {
+			return ScheduledTransfer{}, err
+		}
+	}
+
+	var st ScheduledTransfer
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		current, err := lockScheduledTransfer(ctx, dbtx, scheduleID)
+		if err != nil {
+			return err
+		}
+
+		if us.Amount != nil {
+			current.Amount = current.Currency.Round(*us.Amount)
+			if current.Amount.IsZero() {
+				return ErrZeroAmount
+			}
+		}
+		if us.Recurrence != nil {
+			current.Recurrence = schedule.String()
+			if !schedule.IsZero() {
+				current.NextRunDate = schedule.Next(time.Now())
+			}
+		}
+		if us.NextRunDate != nil {
+			current.NextRunDate = *us.NextRunDate
+		}
+
+		st, err = updateScheduledTransfer(ctx, dbtx, current, ScheduleStatusScheduled)
+		return err
+	})
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+	return st, nil
+}
+
+// CancelScheduledTransfer stops a scheduled transfer from running again. The
+// transfer is kept, together with the outcome of its last run.
+func (b *Bus) CancelScheduledTransfer(ctx context.Context, scheduleID uuid.UUID) (ScheduledTransfer, error) {
+	var st ScheduledTransfer
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		current, err := lockScheduledTransfer(ctx, dbtx, scheduleID)
+		if err != nil {
+			return err
+		}
+
+		st, err = updateScheduledTransfer(ctx, dbtx, current, ScheduleStatusCancelled)
+		return err
+	})
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+	return st, nil
+}
+
+// RunScheduledTransfers runs every scheduled transfer that is due and returns
+// how many were run. Transfers locked by a concurrent run, update or cancel
+// are left for the next run, so several schedulers can share the work.
+func (b *Bus) RunScheduledTransfers(ctx context.Context) (int, error) {
+	var ran int
+	for {
+		found, err := b.runDueScheduledTransfer(ctx)
+		if err != nil {
+			return ran, err
+		}
+		if !found {
+			return ran, nil
+		}
+		ran++
+	}
+}
+
+// runDueScheduledTransfer claims the next due transfer and runs it in its own
+// database transaction, reporting false when none is due. A run only locks
+// its schedule and the two accounts of its transfer, so a retry repeats a
+// single transfer. The transfer takes the path of Bus.CreateTransaction, but
+// inside the claiming transaction, so it is only ever posted together with the
+// record of its run.
+func (b *Bus) runDueScheduledTransfer(ctx context.Context) (bool, error) {
+	var found bool
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		found = false
+		now := time.Now()
+
+		dbSchedule, err := dbtx.LockDueScheduledTransfer(ctx, now)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("lock due scheduled transfer: %w", err)
+		}
+
+		st, err := toScheduledTransfer(dbSchedule)
+		if err != nil {
+			return err
+		}
+
+		if err := b.runScheduledTransfer(ctx, dbtx, st, now); err != nil {
+			return err
+		}
+
+		found = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// runScheduledTransfer posts the transfer in a savepoint, so a failed transfer
+// only rolls back itself, and records the outcome together with the next run.
+// One off transfers are completed or failed after their run.
+func (b *Bus) runScheduledTransfer(ctx context.Context, dbtx transferdb.TxQuerier, st ScheduledTransfer, now time.Time) error {
+	result, err := createSavepointTransaction(ctx, dbtx, Transaction{
+		SourceAccountID:      st.SourceAccountID,
+		DestinationAccountID: st.DestinationAccountID,
+		Amount:               st.Amount,
+		Currency:             st.Currency,
+	})
+	if err != nil {
+		return err
+	}
+
+	status := ScheduleStatusScheduled
+	nextRun := st.NextRunDate
+	if st.Recurrence == "" {
+		status = ScheduleStatusCompleted
+		if result.Err != nil {
+			status = ScheduleStatusFailed
+		}
+	} else {
+		schedule, err := cron.Parse(st.Recurrence)
+		if err != nil {
+			return fmt.Errorf("parse recurrence: schedule[%s]: %w", st.ScheduleID, err)
+		}
+
+		// runs missed while the scheduler was down are skipped
+		nextRun = schedule.Next(now)
+	}
+
+	var lastError string
+	if result.Err != nil {
+		lastError = result.Err.Error()
+		b.log.Info(ctx, "scheduled transfer", "status", "failed", "scheduleId", st.ScheduleID, "msg", result.Err)
+	}
+
+	if _, err := dbtx.RecordScheduledTransferRun(ctx, transferdbgen.RecordScheduledTransferRunParams{
+		Status:         string(status),
+		NextRunDate:    nextRun,
+		LastRunDate:    pgtype.Timestamptz{Time: now, Valid: true},
+		LastTransferID: pgtype.UUID{Bytes: result.Transfer.TransferID, Valid: result.Err == nil},
+		LastError:      lastError,
+		ScheduleID:     st.ScheduleID,
+	}); err != nil {
+		return fmt.Errorf("record scheduled transfer run: %s: %w", st.ScheduleID, err)
+	}
+
+	return nil
+}
+
+// lockScheduledTransfer locks the scheduled transfer for the rest of the
+// database transaction, so it cannot run while it is changed.
+func lockScheduledTransfer(ctx context.Context, dbtx transferdb.TxQuerier, scheduleID uuid.UUID) (ScheduledTransfer, error) {
+	dbSchedule, err := dbtx.LockScheduledTransfer(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ScheduledTransfer{}, ErrScheduleNotFound
+		}
+		return ScheduledTransfer{}, fmt.Errorf("lock scheduled transfer: %s: %w", scheduleID, err)
+	}
+
+	st, err := toScheduledTransfer(dbSchedule)
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+	if st.Status != ScheduleStatusScheduled {
+		return ScheduledTransfer{}, ErrScheduleClosed
+	}
+
+	return st, nil
+}
+
+func updateScheduledTransfer(ctx context.Context, dbtx transferdb.TxQuerier, st ScheduledTransfer, status ScheduleStatus) (ScheduledTransfer, error) {
+	dbSchedule, err := dbtx.UpdateScheduledTransfer(ctx, transferdbgen.UpdateScheduledTransferParams{
+		Amount:      st.Amount,
+		Recurrence:  st.Recurrence,
+		Status:      string(status),
+		NextRunDate: st.NextRunDate,
+		ScheduleID:  st.ScheduleID,
+	})
+	if err != nil {
+		return ScheduledTransfer{}, fmt.Errorf("update scheduled transfer: %s: %w", st.ScheduleID, err)
+	}
+
+	return toScheduledTransfer(dbSchedule)
+}
+
+// parseRecurrence parses the cron expression of a recurring transfer. An empty
+// recurrence is a one off transfer.
+func parseRecurrence(recurrence string) (cron.Schedule, error) {
+	if recurrence == "" {
+		return cron.Schedule{}, nil
+	}
+
+	schedule, err := cron.Parse(recurrence)
+	if err != nil {
+		return cron.Schedule{}, fmt.Errorf("%w: %w", ErrInvalidRecurrence, err)
+	}
+	return schedule, nil
+}
@@ -10,18 +10,24 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts (account_id, balance, created_date, last_modified_date)
-VALUES ($1, $2, $3, $4)
-RETURNING account_id, balance, created_date, last_modified_date
+INSERT INTO accounts (account_id, balance, currency, owner_ref, account_type, name, labels, created_date, last_modified_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit
 `
 
 type CreateAccountParams struct {
 	AccountID        int64           `json:"accountId"`
 	Balance          decimal.Decimal `json:"balance"`
+	Currency         string          `json:"currency"`
+	OwnerRef         string          `json:"ownerRef"`
+	AccountType      string          `json:"accountType"`
+	Name             string          `json:"name"`
+	Labels           []string        `json:"labels"`
 	CreatedDate      time.Time       `json:"createdDate"`
 	LastModifiedDate time.Time       `json:"lastModifiedDate"`
 }
@@ -30,6 +36,11 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 	row := q.db.QueryRow(ctx, createAccount,
 		arg.AccountID,
 		arg.Balance,
+		arg.Currency,
+		arg.OwnerRef,
+		arg.AccountType,
+		arg.Name,
+		arg.Labels,
 		arg.CreatedDate,
 		arg.LastModifiedDate,
 	)
@@ -39,6 +50,14 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.CreatedDate,
 		&i.LastModifiedDate,
+		&i.Currency,
+		&i.HeldBalance,
+		&i.Status,
+		&i.OwnerRef,
+		&i.AccountType,
+		&i.Name,
+		&i.Labels,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
@@ -67,7 +86,7 @@ SET
     balance = balance - $1,
     last_modified_date = NOW()
 WHERE
-    account_id = $2 AND balance >= $1
+    account_id = $2 AND (account_id < 0 OR balance - held_balance + overdraft_limit >= $1)
 `
 
 type DebitAccountParams struct {
@@ -80,7 +99,7 @@ func (q *Queries) DebitAccount(ctx context.Context, arg DebitAccountParams) (pgc
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT account_id, balance, created_date, last_modified_date FROM accounts WHERE account_id = $1
+SELECT account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit FROM accounts WHERE account_id = $1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, accountID int64) (Account, error) {
@@ -91,12 +110,20 @@ func (q *Queries) GetAccount(ctx context.Context, accountID int64) (Account, err
 		&i.Balance,
 		&i.CreatedDate,
 		&i.LastModifiedDate,
+		&i.Currency,
+		&i.HeldBalance,
+		&i.Status,
+		&i.OwnerRef,
+		&i.AccountType,
+		&i.Name,
+		&i.Labels,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
 
 const getAccounts = `-- name: GetAccounts :many
-SELECT account_id, balance, created_date, last_modified_date FROM accounts where account_id = any($1::bigint[])
+SELECT account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit FROM accounts where account_id = any($1::bigint[])
 `
 
 func (q *Queries) GetAccounts(ctx context.Context, accountIds []int64) ([]Account, error) {
@@ -113,6 +140,14 @@ func (q *Queries) GetAccounts(ctx context.Context, accountIds []int64) ([]Accoun
 			&i.Balance,
 			&i.CreatedDate,
 			&i.LastModifiedDate,
+			&i.Currency,
+			&i.HeldBalance,
+			&i.Status,
+			&i.OwnerRef,
+			&i.AccountType,
+			&i.Name,
+			&i.Labels,
+			&i.OverdraftLimit,
 		); err != nil {
 			return nil, err
 		}
@@ -134,3 +169,319 @@ func (q *Queries) GetBalance(ctx context.Context, accountID int64) (decimal.Deci
 	err := row.Scan(&balance)
 	return balance, err
 }
+
+const holdFunds = `-- name: HoldFunds :execresult
+UPDATE accounts
+SET
+    held_balance = held_balance + $1,
+    last_modified_date = NOW()
+WHERE
+    account_id = $2 AND balance - held_balance + overdraft_limit >= $1
+`
+
+type HoldFundsParams struct {
+	Amount    decimal.Decimal `json:"amount"`
+	AccountID int64           `json:"accountId"`
+}
+
+func (q *Queries) HoldFunds(ctx context.Context, arg HoldFundsParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, holdFunds, arg.Amount, arg.AccountID)
+}
+
+const lockAccounts = `-- name: LockAccounts :many
+SELECT account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit FROM accounts WHERE account_id = any($1::bigint[]) ORDER BY account_id DESC FOR UPDATE
+`
+
+func (q *Queries) LockAccounts(ctx context.Context, accountIds []int64) ([]Account, error) {
+	rows, err := q.db.Query(ctx, lockAccounts, accountIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Balance,
+			&i.CreatedDate,
+			&i.LastModifiedDate,
+			&i.Currency,
+			&i.HeldBalance,
+			&i.Status,
+			&i.OwnerRef,
+			&i.AccountType,
+			&i.Name,
+			&i.Labels,
+			&i.OverdraftLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextAccountID = `-- name: NextAccountID :one
+SELECT nextval('accounts_account_id_seq')::bigint AS account_id
+`
+
+func (q *Queries) NextAccountID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, nextAccountID)
+	var account_id int64
+	err := row.Scan(&account_id)
+	return account_id, err
+}
+
+const queryAccounts = `-- name: QueryAccounts :many
+SELECT account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit FROM accounts
+WHERE
+    account_id > $1
+    AND ($2::text IS NULL OR owner_ref = $2)
+    AND ($3::text IS NULL OR account_type = $3)
+    AND labels @> $4::text[]
+ORDER BY account_id
+LIMIT $5
+`
+
+type QueryAccountsParams struct {
+	AfterAccountID int64       `json:"afterAccountId"`
+	OwnerRef       pgtype.Text `json:"ownerRef"`
+	AccountType    pgtype.Text `json:"accountType"`
+	Labels         []string    `json:"labels"`
+	RowLimit       int32       `json:"rowLimit"`
+}
+
+func (q *Queries) QueryAccounts(ctx context.Context, arg QueryAccountsParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, queryAccounts,
+		arg.AfterAccountID,
+		arg.OwnerRef,
+		arg.AccountType,
+		arg.Labels,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Balance,
+			&i.CreatedDate,
+			&i.LastModifiedDate,
+			&i.Currency,
+			&i.HeldBalance,
+			&i.Status,
+			&i.OwnerRef,
+			&i.AccountType,
+			&i.Name,
+			&i.Labels,
+			&i.OverdraftLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reconcileAccounts = `-- name: ReconcileAccounts :many
+SELECT
+    a.account_id,
+    a.currency,
+    a.balance,
+    COALESCE(SUM(t.amount), 0)::NUMERIC(19, 5) AS ledger_balance
+FROM
+    accounts a
+    LEFT JOIN transactions t ON t.account_id = a.account_id
+WHERE
+    a.account_id > $1
+GROUP BY
+    a.account_id
+ORDER BY
+    a.account_id
+LIMIT
+    $2
+`
+
+type ReconcileAccountsParams struct {
+	AfterAccountID int64 `json:"afterAccountId"`
+	RowLimit       int32 `json:"rowLimit"`
+}
+
+type ReconcileAccountsRow struct {
+	AccountID     int64           `json:"accountId"`
+	Currency      string          `json:"currency"`
+	Balance       decimal.Decimal `json:"balance"`
+	LedgerBalance decimal.Decimal `json:"ledgerBalance"`
+}
+
+func (q *Queries) ReconcileAccounts(ctx context.Context, arg ReconcileAccountsParams) ([]ReconcileAccountsRow, error) {
+	rows, err := q.db.Query(ctx, reconcileAccounts, arg.AfterAccountID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReconcileAccountsRow
+	for rows.Next() {
+		var i ReconcileAccountsRow
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Currency,
+			&i.Balance,
+			&i.LedgerBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseFunds = `-- name: ReleaseFunds :exec
+UPDATE accounts
+SET
+    held_balance = held_balance - $1,
+    last_modified_date = NOW()
+WHERE
+    account_id = $2
+`
+
+type ReleaseFundsParams struct {
+	Amount    decimal.Decimal `json:"amount"`
+	AccountID int64           `json:"accountId"`
+}
+
+func (q *Queries) ReleaseFunds(ctx context.Context, arg ReleaseFundsParams) error {
+	_, err := q.db.Exec(ctx, releaseFunds, arg.Amount, arg.AccountID)
+	return err
+}
+
+const updateAccountMetadata = `-- name: UpdateAccountMetadata :one
+UPDATE accounts
+SET
+    owner_ref = COALESCE($1, owner_ref),
+    account_type = COALESCE($2, account_type),
+    name = COALESCE($3, name),
+    labels = COALESCE($4::text[], labels),
+    last_modified_date = NOW()
+WHERE
+    account_id = $5
+RETURNING account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit
+`
+
+type UpdateAccountMetadataParams struct {
+	OwnerRef    pgtype.Text `json:"ownerRef"`
+	AccountType pgtype.Text `json:"accountType"`
+	Name        pgtype.Text `json:"name"`
+	Labels      []string    `json:"labels"`
+	AccountID   int64       `json:"accountId"`
+}
+
+func (q *Queries) UpdateAccountMetadata(ctx context.Context, arg UpdateAccountMetadataParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountMetadata,
+		arg.OwnerRef,
+		arg.AccountType,
+		arg.Name,
+		arg.Labels,
+		arg.AccountID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.Balance,
+		&i.CreatedDate,
+		&i.LastModifiedDate,
+		&i.Currency,
+		&i.HeldBalance,
+		&i.Status,
+		&i.OwnerRef,
+		&i.AccountType,
+		&i.Name,
+		&i.Labels,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET
+    status = $1,
+    last_modified_date = NOW()
+WHERE
+    account_id = $2
+RETURNING account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit
+`
+
+type UpdateAccountStatusParams struct {
+	Status    string `json:"status"`
+	AccountID int64  `json:"accountId"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.Status, arg.AccountID)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.Balance,
+		&i.CreatedDate,
+		&i.LastModifiedDate,
+		&i.Currency,
+		&i.HeldBalance,
+		&i.Status,
+		&i.OwnerRef,
+		&i.AccountType,
+		&i.Name,
+		&i.Labels,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
+
+const updateOverdraftLimit = `-- name: UpdateOverdraftLimit :one
+UPDATE accounts
+SET
+    overdraft_limit = $1,
+    last_modified_date = NOW()
+WHERE
+    account_id = $2
+RETURNING account_id, balance, created_date, last_modified_date, currency, held_balance, status, owner_ref, account_type, name, labels, overdraft_limit
+`
+
+type UpdateOverdraftLimitParams struct {
+	OverdraftLimit decimal.Decimal `json:"overdraftLimit"`
+	AccountID      int64           `json:"accountId"`
+}
+
+func (q *Queries) UpdateOverdraftLimit(ctx context.Context, arg UpdateOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateOverdraftLimit, arg.OverdraftLimit, arg.AccountID)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.Balance,
+		&i.CreatedDate,
+		&i.LastModifiedDate,
+		&i.Currency,
+		&i.HeldBalance,
+		&i.Status,
+		&i.OwnerRef,
+		&i.AccountType,
+		&i.Name,
+		&i.Labels,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
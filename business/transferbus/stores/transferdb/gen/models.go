@@ -7,6 +7,8 @@ package transferdbgen
 import (
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
@@ -15,10 +17,146 @@ type Account struct {
 	Balance          decimal.Decimal `json:"balance"`
 	CreatedDate      time.Time       `json:"createdDate"`
 	LastModifiedDate time.Time       `json:"lastModifiedDate"`
+	Currency         string          `json:"currency"`
+	HeldBalance      decimal.Decimal `json:"heldBalance"`
+	Status           string          `json:"status"`
+	OwnerRef         string          `json:"ownerRef"`
+	AccountType      string          `json:"accountType"`
+	Name             string          `json:"name"`
+	Labels           []string        `json:"labels"`
+	OverdraftLimit   decimal.Decimal `json:"overdraftLimit"`
+}
+
+type AccountLimit struct {
+	AccountID        int64           `json:"accountId"`
+	MaxAmount        decimal.Decimal `json:"maxAmount"`
+	DailyAmount      decimal.Decimal `json:"dailyAmount"`
+	MonthlyAmount    decimal.Decimal `json:"monthlyAmount"`
+	LastModifiedDate time.Time       `json:"lastModifiedDate"`
+}
+
+type AccountTypeLimit struct {
+	AccountType      string          `json:"accountType"`
+	MaxAmount        decimal.Decimal `json:"maxAmount"`
+	DailyAmount      decimal.Decimal `json:"dailyAmount"`
+	MonthlyAmount    decimal.Decimal `json:"monthlyAmount"`
+	LastModifiedDate time.Time       `json:"lastModifiedDate"`
+	Currency         string          `json:"currency"`
+}
+
+type FxQuote struct {
+	QuoteID              uuid.UUID       `json:"quoteId"`
+	SourceAccountID      int64           `json:"sourceAccountId"`
+	DestinationAccountID int64           `json:"destinationAccountId"`
+	SourceCurrency       string          `json:"sourceCurrency"`
+	DestinationCurrency  string          `json:"destinationCurrency"`
+	SourceAmount         decimal.Decimal `json:"sourceAmount"`
+	DestinationAmount    decimal.Decimal `json:"destinationAmount"`
+	Rate                 decimal.Decimal `json:"rate"`
+	CreatedDate          time.Time       `json:"createdDate"`
+	ExpiresDate          time.Time       `json:"expiresDate"`
+	TransferID           pgtype.UUID     `json:"transferId"`
+}
+
+type Hold struct {
+	HoldID               uuid.UUID       `json:"holdId"`
+	SourceAccountID      int64           `json:"sourceAccountId"`
+	DestinationAccountID int64           `json:"destinationAccountId"`
+	Currency             string          `json:"currency"`
+	Amount               decimal.Decimal `json:"amount"`
+	CapturedAmount       decimal.Decimal `json:"capturedAmount"`
+	Status               string          `json:"status"`
+	TransferID           pgtype.UUID     `json:"transferId"`
+	CreatedDate          time.Time       `json:"createdDate"`
+	ExpiresDate          time.Time       `json:"expiresDate"`
+	LastModifiedDate     time.Time       `json:"lastModifiedDate"`
+}
+
+type IdempotencyKey struct {
+	IdempotencyKey string         `json:"idempotencyKey"`
+	RequestHash    string         `json:"requestHash"`
+	CreatedDate    time.Time      `json:"createdDate"`
+	TransferID     pgtype.UUID    `json:"transferId"`
+	SourceBalance  pgtype.Numeric `json:"sourceBalance"`
+}
+
+type JournalEntry struct {
+	TransferID           uuid.UUID       `json:"transferId"`
+	EntryType            string          `json:"entryType"`
+	SourceAccountID      int64           `json:"sourceAccountId"`
+	DestinationAccountID int64           `json:"destinationAccountId"`
+	Amount               decimal.Decimal `json:"amount"`
+	CreatedDate          time.Time       `json:"createdDate"`
+	Currency             string          `json:"currency"`
+	DestinationAmount    decimal.Decimal `json:"destinationAmount"`
+	DestinationCurrency  string          `json:"destinationCurrency"`
+	Rate                 decimal.Decimal `json:"rate"`
+	QuoteID              pgtype.UUID     `json:"quoteId"`
+	ReversalOf           pgtype.UUID     `json:"reversalOf"`
+}
+
+type OutboxEvent struct {
+	EventID       int64              `json:"eventId"`
+	EventType     string             `json:"eventType"`
+	AccountID     int64              `json:"accountId"`
+	Payload       []byte             `json:"payload"`
+	CreatedDate   time.Time          `json:"createdDate"`
+	PublishedDate pgtype.Timestamptz `json:"publishedDate"`
+	Balance       decimal.Decimal    `json:"balance"`
+	RelayAfterXid int64              `json:"relayAfterXid"`
+}
+
+type ScheduledTransfer struct {
+	ScheduleID           uuid.UUID          `json:"scheduleId"`
+	SourceAccountID      int64              `json:"sourceAccountId"`
+	DestinationAccountID int64              `json:"destinationAccountId"`
+	Currency             string             `json:"currency"`
+	Amount               decimal.Decimal    `json:"amount"`
+	Recurrence           string             `json:"recurrence"`
+	Status               string             `json:"status"`
+	NextRunDate          time.Time          `json:"nextRunDate"`
+	RunCount             int32              `json:"runCount"`
+	LastRunDate          pgtype.Timestamptz `json:"lastRunDate"`
+	LastTransferID       pgtype.UUID        `json:"lastTransferId"`
+	LastError            string             `json:"lastError"`
+	CreatedDate          time.Time          `json:"createdDate"`
+	LastModifiedDate     time.Time          `json:"lastModifiedDate"`
+}
+
+type SystemAccount struct {
+	Purpose   string `json:"purpose"`
+	Currency  string `json:"currency"`
+	AccountID int64  `json:"accountId"`
 }
 
 type Transaction struct {
-	AccountID   int64           `json:"accountId"`
-	Amount      decimal.Decimal `json:"amount"`
-	CreatedDate time.Time       `json:"createdDate"`
+	AccountID     int64           `json:"accountId"`
+	Amount        decimal.Decimal `json:"amount"`
+	CreatedDate   time.Time       `json:"createdDate"`
+	TransactionID int64           `json:"transactionId"`
+	TransferID    uuid.UUID       `json:"transferId"`
+	Currency      string          `json:"currency"`
+}
+
+type WebhookDelivery struct {
+	DeliveryID      int64              `json:"deliveryId"`
+	SubscriptionID  uuid.UUID          `json:"subscriptionId"`
+	EventID         int64              `json:"eventId"`
+	Status          string             `json:"status"`
+	Attempts        int32              `json:"attempts"`
+	NextAttemptDate time.Time          `json:"nextAttemptDate"`
+	LastAttemptDate pgtype.Timestamptz `json:"lastAttemptDate"`
+	LastStatusCode  int32              `json:"lastStatusCode"`
+	LastError       string             `json:"lastError"`
+	CreatedDate     time.Time          `json:"createdDate"`
+}
+
+type WebhookSubscription struct {
+	SubscriptionID   uuid.UUID `json:"subscriptionId"`
+	AccountID        int64     `json:"accountId"`
+	Url              string    `json:"url"`
+	Secret           string    `json:"secret"`
+	Status           string    `json:"status"`
+	CreatedDate      time.Time `json:"createdDate"`
+	LastModifiedDate time.Time `json:"lastModifiedDate"`
 }
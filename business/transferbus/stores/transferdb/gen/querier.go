@@ -6,19 +6,77 @@ package transferdbgen
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
 type Querier interface {
+	ClaimFxQuote(ctx context.Context, arg ClaimFxQuoteParams) (FxQuote, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
-	CreateTransaction(ctx context.Context, arg CreateTransactionParams) error
+	CreateFxQuote(ctx context.Context, arg CreateFxQuoteParams) (FxQuote, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (pgconn.CommandTag, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (int64, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
+	CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
 	CreditAccount(ctx context.Context, arg CreditAccountParams) (pgconn.CommandTag, error)
 	DebitAccount(ctx context.Context, arg DebitAccountParams) (pgconn.CommandTag, error)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) (pgconn.CommandTag, error)
 	GetAccount(ctx context.Context, accountID int64) (Account, error)
+	GetAccountLimit(ctx context.Context, accountID int64) (AccountLimit, error)
+	GetAccountOutboxEvents(ctx context.Context, arg GetAccountOutboxEventsParams) ([]OutboxEvent, error)
+	GetAccountTypeLimit(ctx context.Context, arg GetAccountTypeLimitParams) (AccountTypeLimit, error)
 	GetAccounts(ctx context.Context, accountIds []int64) ([]Account, error)
 	GetBalance(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	GetFxQuote(ctx context.Context, quoteID uuid.UUID) (FxQuote, error)
+	GetHold(ctx context.Context, holdID uuid.UUID) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, idempotencyKey string) (IdempotencyKey, error)
+	GetJournalEntry(ctx context.Context, transferID uuid.UUID) (JournalEntry, error)
+	GetLastAccountOutboxEvent(ctx context.Context, accountID int64) (OutboxEvent, error)
+	GetReversedAmounts(ctx context.Context, reversalOf pgtype.UUID) (GetReversedAmountsRow, error)
+	GetScheduledTransfer(ctx context.Context, scheduleID uuid.UUID) (ScheduledTransfer, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (int64, error)
+	GetTransferTransactions(ctx context.Context, transferID uuid.UUID) ([]Transaction, error)
+	GetUnpublishedOutboxEvents(ctx context.Context, rowLimit int32) ([]OutboxEvent, error)
+	GetWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) (WebhookSubscription, error)
+	HoldFunds(ctx context.Context, arg HoldFundsParams) (pgconn.CommandTag, error)
+	LeaseWebhookDeliveries(ctx context.Context, arg LeaseWebhookDeliveriesParams) error
+	LockAccounts(ctx context.Context, accountIds []int64) ([]Account, error)
+	LockDueScheduledTransfer(ctx context.Context, now time.Time) (ScheduledTransfer, error)
+	LockDueWebhookDeliveries(ctx context.Context, arg LockDueWebhookDeliveriesParams) ([]LockDueWebhookDeliveriesRow, error)
+	LockExpiredHolds(ctx context.Context, arg LockExpiredHoldsParams) ([]Hold, error)
+	LockHold(ctx context.Context, holdID uuid.UUID) (Hold, error)
+	LockJournalEntry(ctx context.Context, transferID uuid.UUID) (JournalEntry, error)
+	LockOutboxRelay(ctx context.Context, lockKey int64) (bool, error)
+	LockScheduledTransfer(ctx context.Context, scheduleID uuid.UUID) (ScheduledTransfer, error)
+	MarkOutboxEventPublished(ctx context.Context, arg MarkOutboxEventPublishedParams) error
+	NextAccountID(ctx context.Context) (int64, error)
+	NotifyOutboxEvent(ctx context.Context, arg NotifyOutboxEventParams) error
+	QueryAccountTransactions(ctx context.Context, arg QueryAccountTransactionsParams) ([]Transaction, error)
+	QueryAccountWebhookSubscriptions(ctx context.Context, accountID int64) ([]WebhookSubscription, error)
+	QueryAccounts(ctx context.Context, arg QueryAccountsParams) ([]Account, error)
+	QueryWebhookDeliveries(ctx context.Context, arg QueryWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ReconcileAccounts(ctx context.Context, arg ReconcileAccountsParams) ([]ReconcileAccountsRow, error)
+	RecordScheduledTransferRun(ctx context.Context, arg RecordScheduledTransferRunParams) (ScheduledTransfer, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) error
+	ReleaseFunds(ctx context.Context, arg ReleaseFundsParams) error
+	SetIdempotencyKeySourceBalance(ctx context.Context, arg SetIdempotencyKeySourceBalanceParams) error
+	SumAccountDebits(ctx context.Context, arg SumAccountDebitsParams) (decimal.Decimal, error)
+	UpdateAccountMetadata(ctx context.Context, arg UpdateAccountMetadataParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateHold(ctx context.Context, arg UpdateHoldParams) (Hold, error)
+	UpdateOverdraftLimit(ctx context.Context, arg UpdateOverdraftLimitParams) (Account, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error)
+	UpsertAccountLimit(ctx context.Context, arg UpsertAccountLimitParams) (AccountLimit, error)
+	UpsertAccountTypeLimit(ctx context.Context, arg UpsertAccountTypeLimitParams) (AccountTypeLimit, error)
 }
 
 var _ Querier = (*Queries)(nil)
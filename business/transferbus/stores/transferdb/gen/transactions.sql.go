@@ -9,21 +9,157 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
-const createTransaction = `-- name: CreateTransaction :exec
-INSERT INTO transactions (account_id, amount, created_date)
-VALUES ($1, $2, $3)
+const createTransaction = `-- name: CreateTransaction :one
+INSERT INTO transactions (transfer_id, account_id, amount, currency, created_date)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING account_id, amount, created_date, transaction_id, transfer_id, currency
 `
 
 type CreateTransactionParams struct {
+	TransferID  uuid.UUID       `json:"transferId"`
 	AccountID   int64           `json:"accountId"`
 	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency"`
 	CreatedDate time.Time       `json:"createdDate"`
 }
 
-func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) error {
-	_, err := q.db.Exec(ctx, createTransaction, arg.AccountID, arg.Amount, arg.CreatedDate)
-	return err
+func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
+	row := q.db.QueryRow(ctx, createTransaction,
+		arg.TransferID,
+		arg.AccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.CreatedDate,
+	)
+	var i Transaction
+	err := row.Scan(
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedDate,
+		&i.TransactionID,
+		&i.TransferID,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getTransferTransactions = `-- name: GetTransferTransactions :many
+SELECT account_id, amount, created_date, transaction_id, transfer_id, currency FROM transactions WHERE transfer_id = $1 ORDER BY transaction_id
+`
+
+func (q *Queries) GetTransferTransactions(ctx context.Context, transferID uuid.UUID) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, getTransferTransactions, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedDate,
+			&i.TransactionID,
+			&i.TransferID,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryAccountTransactions = `-- name: QueryAccountTransactions :many
+SELECT account_id, amount, created_date, transaction_id, transfer_id, currency FROM transactions
+WHERE
+    account_id = $1
+    AND ($2::bigint IS NULL OR transaction_id < $2)
+    AND ($3::timestamptz IS NULL OR created_date >= $3)
+    AND ($4::timestamptz IS NULL OR created_date < $4)
+    AND (
+        $5::text IS NULL
+        OR ($5 = 'debit' AND amount < 0)
+        OR ($5 = 'credit' AND amount >= 0)
+    )
+ORDER BY transaction_id DESC
+LIMIT $6
+`
+
+type QueryAccountTransactionsParams struct {
+	AccountID int64              `json:"accountId"`
+	Cursor    pgtype.Int8        `json:"cursor"`
+	StartDate pgtype.Timestamptz `json:"startDate"`
+	EndDate   pgtype.Timestamptz `json:"endDate"`
+	Direction pgtype.Text        `json:"direction"`
+	RowLimit  int32              `json:"rowLimit"`
+}
+
+func (q *Queries) QueryAccountTransactions(ctx context.Context, arg QueryAccountTransactionsParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, queryAccountTransactions,
+		arg.AccountID,
+		arg.Cursor,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Direction,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedDate,
+			&i.TransactionID,
+			&i.TransferID,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumAccountDebits = `-- name: SumAccountDebits :one
+SELECT
+    COALESCE(SUM(-t.amount), 0)::NUMERIC(19, 5) AS total
+FROM
+    transactions t
+    JOIN journal_entries j ON j.transfer_id = t.transfer_id
+WHERE
+    t.account_id = $1
+    AND t.amount < 0
+    AND t.created_date >= $2
+    AND j.entry_type = any($3::text[])
+`
+
+type SumAccountDebitsParams struct {
+	AccountID  int64     `json:"accountId"`
+	Since      time.Time `json:"since"`
+	EntryTypes []string  `json:"entryTypes"`
+}
+
+func (q *Queries) SumAccountDebits(ctx context.Context, arg SumAccountDebitsParams) (decimal.Decimal, error) {
+	row := q.db.QueryRow(ctx, sumAccountDebits, arg.AccountID, arg.Since, arg.EntryTypes)
+	var total decimal.Decimal
+	err := row.Scan(&total)
+	return total, err
 }
@@ -0,0 +1,21 @@
+package transferdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ParseIsoLevel parses an isolation level such as "serializable" or
+// "repeatable-read". Words may be separated by spaces, dashes or underscores.
+func ParseIsoLevel(level string) (pgx.TxIsoLevel, error) {
+	normalized := strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(strings.TrimSpace(level)))
+
+	switch iso := pgx.TxIsoLevel(normalized); iso {
+	case pgx.Serializable, pgx.RepeatableRead, pgx.ReadCommitted:
+		return iso, nil
+	default:
+		return "", fmt.Errorf("unknown isolation level %q", level)
+	}
+}
@@ -0,0 +1,36 @@
+package transferdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Listen listens for notifications on the channel and calls fn with the
+// payload of every notification until the context is cancelled or the
+// connection fails. listening is called once notifications are received, so
+// callers can catch up on what was missed before. The connection is taken out
+// of the pool and closed afterwards rather than returned still listening.
+func (q *TxQueries) Listen(ctx context.Context, channel string, listening func(), fn func(payload string)) error {
+	poolConn, err := q.TxnPool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+
+	conn := poolConn.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen: %s: %w", channel, err)
+	}
+	listening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %s: %w", channel, err)
+		}
+		fn(notification.Payload)
+	}
+}
@@ -1,6 +1,6 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (account_id, balance, created_date, last_modified_date)
-VALUES (@account_id, @balance, @created_date, @last_modified_date)
+INSERT INTO accounts (account_id, balance, currency, owner_ref, account_type, name, labels, created_date, last_modified_date)
+VALUES (@account_id, @balance, @currency, @owner_ref, @account_type, @name, @labels, @created_date, @last_modified_date)
 RETURNING *;
 
 -- name: GetBalance :one
@@ -12,13 +12,19 @@ SELECT * FROM accounts WHERE account_id = @account_id;
 -- name: GetAccounts :many
 SELECT * FROM accounts where account_id = any(@account_ids::bigint[]);
 
+-- name: NextAccountID :one
+SELECT nextval('accounts_account_id_seq')::bigint AS account_id;
+
+-- name: LockAccounts :many
+SELECT * FROM accounts WHERE account_id = any(@account_ids::bigint[]) ORDER BY account_id DESC FOR UPDATE;
+
 -- name: DebitAccount :execresult
 UPDATE accounts
 SET
     balance = balance - @amount,
     last_modified_date = NOW()
 WHERE
-    account_id = @account_id AND balance >= @amount;
+    account_id = @account_id AND (account_id < 0 OR balance - held_balance + overdraft_limit >= @amount);
 
 -- name: CreditAccount :execresult
 UPDATE accounts
@@ -27,3 +33,77 @@ SET
     last_modified_date = NOW()
 WHERE
     account_id = @account_id;
+
+-- name: HoldFunds :execresult
+UPDATE accounts
+SET
+    held_balance = held_balance + @amount,
+    last_modified_date = NOW()
+WHERE
+    account_id = @account_id AND balance - held_balance + overdraft_limit >= @amount;
+
+-- name: ReleaseFunds :exec
+UPDATE accounts
+SET
+    held_balance = held_balance - @amount,
+    last_modified_date = NOW()
+WHERE
+    account_id = @account_id;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET
+    status = @status,
+    last_modified_date = NOW()
+WHERE
+    account_id = @account_id
+RETURNING *;
+
+-- name: UpdateAccountMetadata :one
+UPDATE accounts
+SET
+    owner_ref = COALESCE(sqlc.narg('owner_ref'), owner_ref),
+    account_type = COALESCE(sqlc.narg('account_type'), account_type),
+    name = COALESCE(sqlc.narg('name'), name),
+    labels = COALESCE(sqlc.narg('labels')::text[], labels),
+    last_modified_date = NOW()
+WHERE
+    account_id = @account_id
+RETURNING *;
+
+-- name: UpdateOverdraftLimit :one
+UPDATE accounts
+SET
+    overdraft_limit = @overdraft_limit,
+    last_modified_date = NOW()
+WHERE
+    account_id = @account_id
+RETURNING *;
+
+-- name: QueryAccounts :many
+SELECT * FROM accounts
+WHERE
+    account_id > @after_account_id
+    AND (sqlc.narg('owner_ref')::text IS NULL OR owner_ref = sqlc.narg('owner_ref'))
+    AND (sqlc.narg('account_type')::text IS NULL OR account_type = sqlc.narg('account_type'))
+    AND labels @> @labels::text[]
+ORDER BY account_id
+LIMIT @row_limit;
+
+-- name: ReconcileAccounts :many
+SELECT
+    a.account_id,
+    a.currency,
+    a.balance,
+    COALESCE(SUM(t.amount), 0)::NUMERIC(19, 5) AS ledger_balance
+FROM
+    accounts a
+    LEFT JOIN transactions t ON t.account_id = a.account_id
+WHERE
+    a.account_id > @after_account_id
+GROUP BY
+    a.account_id
+ORDER BY
+    a.account_id
+LIMIT
+    @row_limit;
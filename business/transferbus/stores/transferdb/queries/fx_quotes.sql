@@ -0,0 +1,21 @@
+-- name: CreateFxQuote :one
+INSERT INTO fx_quotes (
+    quote_id, source_account_id, destination_account_id, source_currency, destination_currency,
+    source_amount, destination_amount, rate, created_date, expires_date
+)
+VALUES (
+    @quote_id, @source_account_id, @destination_account_id, @source_currency, @destination_currency,
+    @source_amount, @destination_amount, @rate, @created_date, @expires_date
+)
+RETURNING *;
+
+-- name: GetFxQuote :one
+SELECT * FROM fx_quotes WHERE quote_id = @quote_id;
+
+-- name: ClaimFxQuote :one
+UPDATE fx_quotes
+SET
+    transfer_id = @transfer_id
+WHERE
+    quote_id = @quote_id AND transfer_id IS NULL
+RETURNING *;
@@ -0,0 +1,35 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    hold_id, source_account_id, destination_account_id, currency, amount,
+    status, created_date, expires_date, last_modified_date
+)
+VALUES (
+    @hold_id, @source_account_id, @destination_account_id, @currency, @amount,
+    @status, @created_date, @expires_date, @last_modified_date
+)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds WHERE hold_id = @hold_id;
+
+-- name: LockHold :one
+SELECT * FROM holds WHERE hold_id = @hold_id FOR UPDATE;
+
+-- name: UpdateHold :one
+UPDATE holds
+SET
+    status = @status,
+    captured_amount = @captured_amount,
+    transfer_id = @transfer_id,
+    last_modified_date = NOW()
+WHERE
+    hold_id = @hold_id
+RETURNING *;
+
+-- name: LockExpiredHolds :many
+SELECT * FROM holds
+WHERE
+    status = 'authorized' AND expires_date <= @now
+ORDER BY expires_date
+LIMIT @row_limit
+FOR UPDATE SKIP LOCKED;
@@ -0,0 +1,10 @@
+-- name: CreateIdempotencyKey :execresult
+INSERT INTO idempotency_keys (idempotency_key, request_hash, transfer_id, created_date)
+VALUES (@idempotency_key, @request_hash, @transfer_id, @created_date)
+ON CONFLICT (idempotency_key) DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE idempotency_key = @idempotency_key;
+
+-- name: SetIdempotencyKeySourceBalance :exec
+UPDATE idempotency_keys SET source_balance = @source_balance::numeric WHERE idempotency_key = @idempotency_key;
@@ -0,0 +1,25 @@
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+    transfer_id, entry_type, source_account_id, destination_account_id, amount, currency,
+    destination_amount, destination_currency, rate, quote_id, reversal_of, created_date
+)
+VALUES (
+    @transfer_id, @entry_type, @source_account_id, @destination_account_id, @amount, @currency,
+    @destination_amount, @destination_currency, @rate, @quote_id, @reversal_of, @created_date
+)
+RETURNING *;
+
+-- name: GetJournalEntry :one
+SELECT * FROM journal_entries WHERE transfer_id = @transfer_id;
+
+-- name: LockJournalEntry :one
+SELECT * FROM journal_entries WHERE transfer_id = @transfer_id FOR UPDATE;
+
+-- name: GetReversedAmounts :one
+SELECT
+    COALESCE(SUM(destination_amount), 0)::NUMERIC(19, 5) AS reversed_amount,
+    COALESCE(SUM(amount), 0)::NUMERIC(19, 5) AS reversed_destination_amount
+FROM
+    journal_entries
+WHERE
+    reversal_of = @reversal_of;
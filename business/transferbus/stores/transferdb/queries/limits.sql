@@ -0,0 +1,27 @@
+-- name: GetAccountLimit :one
+SELECT * FROM account_limits WHERE account_id = @account_id;
+
+-- name: GetAccountTypeLimit :one
+SELECT * FROM account_type_limits WHERE account_type = @account_type AND currency = @currency;
+
+-- name: UpsertAccountLimit :one
+INSERT INTO account_limits (account_id, max_amount, daily_amount, monthly_amount, last_modified_date)
+VALUES (@account_id, @max_amount, @daily_amount, @monthly_amount, NOW())
+ON CONFLICT (account_id) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    last_modified_date = EXCLUDED.last_modified_date
+RETURNING *;
+
+-- name: UpsertAccountTypeLimit :one
+INSERT INTO account_type_limits (account_type, currency, max_amount, daily_amount, monthly_amount, last_modified_date)
+VALUES (@account_type, @currency, @max_amount, @daily_amount, @monthly_amount, NOW())
+ON CONFLICT (account_type, currency) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    last_modified_date = EXCLUDED.last_modified_date
+RETURNING *;
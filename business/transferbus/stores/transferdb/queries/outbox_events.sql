@@ -0,0 +1,48 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (event_type, account_id, balance, payload, created_date)
+VALUES (@event_type, @account_id, @balance, @payload, @created_date)
+RETURNING event_id;
+
+-- name: GetAccountOutboxEvents :many
+SELECT * FROM outbox_events
+WHERE
+    account_id = @account_id
+    AND event_id > @after_event_id
+ORDER BY event_id
+LIMIT @row_limit;
+
+-- name: GetLastAccountOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE
+    account_id = @account_id
+ORDER BY event_id DESC
+LIMIT 1;
+
+-- name: GetUnpublishedOutboxEvents :many
+SELECT * FROM outbox_events
+WHERE
+    published_date IS NULL
+    AND event_id < COALESCE(
+        (
+            SELECT MIN(held.event_id) FROM outbox_events held
+            WHERE
+                held.published_date IS NULL
+                AND held.relay_after_xid > pg_snapshot_xmin (pg_current_snapshot ())::TEXT::BIGINT
+        ),
+        9223372036854775807
+    )
+ORDER BY event_id
+LIMIT @row_limit;
+
+-- name: LockOutboxRelay :one
+SELECT pg_try_advisory_xact_lock(@lock_key::BIGINT)::BOOLEAN AS locked;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox_events
+SET
+    published_date = @published_date
+WHERE
+    event_id = @event_id;
+
+-- name: NotifyOutboxEvent :exec
+SELECT pg_notify(@channel::TEXT, @payload::TEXT);
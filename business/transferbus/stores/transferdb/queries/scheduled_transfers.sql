@@ -0,0 +1,50 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    schedule_id, source_account_id, destination_account_id, currency, amount,
+    recurrence, status, next_run_date, created_date, last_modified_date
+)
+VALUES (
+    @schedule_id, @source_account_id, @destination_account_id, @currency, @amount,
+    @recurrence, @status, @next_run_date, @created_date, @last_modified_date
+)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers WHERE schedule_id = @schedule_id;
+
+-- name: LockScheduledTransfer :one
+SELECT * FROM scheduled_transfers WHERE schedule_id = @schedule_id FOR UPDATE;
+
+-- name: LockDueScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE
+    status = 'scheduled' AND next_run_date <= @now
+ORDER BY next_run_date
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: RecordScheduledTransferRun :one
+UPDATE scheduled_transfers
+SET
+    status = @status,
+    next_run_date = @next_run_date,
+    run_count = run_count + 1,
+    last_run_date = @last_run_date,
+    last_transfer_id = @last_transfer_id,
+    last_error = @last_error,
+    last_modified_date = NOW()
+WHERE
+    schedule_id = @schedule_id
+RETURNING *;
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET
+    amount = @amount,
+    recurrence = @recurrence,
+    status = @status,
+    next_run_date = @next_run_date,
+    last_modified_date = NOW()
+WHERE
+    schedule_id = @schedule_id
+RETURNING *;
@@ -0,0 +1,2 @@
+-- name: GetSystemAccount :one
+SELECT account_id FROM system_accounts WHERE purpose = @purpose AND currency = @currency;
@@ -1,3 +1,34 @@
--- name: CreateTransaction :exec
-INSERT INTO transactions (account_id, amount, created_date)
-VALUES (@account_id, @amount, @created_date);
\ No newline at end of file
+-- name: CreateTransaction :one
+INSERT INTO transactions (transfer_id, account_id, amount, currency, created_date)
+VALUES (@transfer_id, @account_id, @amount, @currency, @created_date)
+RETURNING *;
+
+-- name: GetTransferTransactions :many
+SELECT * FROM transactions WHERE transfer_id = @transfer_id ORDER BY transaction_id;
+
+-- name: QueryAccountTransactions :many
+SELECT * FROM transactions
+WHERE
+    account_id = @account_id
+    AND (sqlc.narg('cursor')::bigint IS NULL OR transaction_id < sqlc.narg('cursor'))
+    AND (sqlc.narg('start_date')::timestamptz IS NULL OR created_date >= sqlc.narg('start_date'))
+    AND (sqlc.narg('end_date')::timestamptz IS NULL OR created_date < sqlc.narg('end_date'))
+    AND (
+        sqlc.narg('direction')::text IS NULL
+        OR (sqlc.narg('direction') = 'debit' AND amount < 0)
+        OR (sqlc.narg('direction') = 'credit' AND amount >= 0)
+    )
+ORDER BY transaction_id DESC
+LIMIT @row_limit;
+
+-- name: SumAccountDebits :one
+SELECT
+    COALESCE(SUM(-t.amount), 0)::NUMERIC(19, 5) AS total
+FROM
+    transactions t
+    JOIN journal_entries j ON j.transfer_id = t.transfer_id
+WHERE
+    t.account_id = @account_id
+    AND t.amount < 0
+    AND t.created_date >= @since
+    AND j.entry_type = any(@entry_types::text[]);
@@ -0,0 +1,85 @@
+-- name: CreateWebhookDeliveries :exec
+INSERT INTO webhook_deliveries (subscription_id, event_id, status, next_attempt_date, created_date)
+SELECT
+    subscription_id, @event_id, @status, @now, @now
+FROM
+    webhook_subscriptions
+WHERE
+    account_id = @account_id;
+
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (subscription_id, account_id, url, secret, status, created_date, last_modified_date)
+VALUES (@subscription_id, @account_id, @url, @secret, @status, @created_date, @last_modified_date)
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :execresult
+DELETE FROM webhook_subscriptions WHERE subscription_id = @subscription_id;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions WHERE subscription_id = @subscription_id;
+
+-- name: LeaseWebhookDeliveries :exec
+UPDATE webhook_deliveries
+SET
+    next_attempt_date = @lease_until
+WHERE
+    delivery_id = any(@delivery_ids::bigint[]);
+
+-- name: LockDueWebhookDeliveries :many
+SELECT
+    d.delivery_id,
+    d.subscription_id,
+    d.attempts,
+    s.url,
+    s.secret,
+    e.event_id,
+    e.event_type,
+    e.account_id,
+    e.balance,
+    e.payload,
+    e.created_date
+FROM
+    webhook_deliveries d
+    JOIN webhook_subscriptions s ON s.subscription_id = d.subscription_id
+    JOIN outbox_events e ON e.event_id = d.event_id
+WHERE
+    d.status = 'pending'
+    AND s.status = 'active'
+    AND d.next_attempt_date <= @now
+ORDER BY d.next_attempt_date, d.delivery_id
+LIMIT @row_limit
+FOR UPDATE OF d SKIP LOCKED;
+
+-- name: QueryAccountWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions WHERE account_id = @account_id ORDER BY created_date, subscription_id;
+
+-- name: QueryWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE
+    subscription_id = @subscription_id
+    AND (sqlc.narg('cursor')::bigint IS NULL OR delivery_id < sqlc.narg('cursor'))
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+ORDER BY delivery_id DESC
+LIMIT @row_limit;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET
+    status = @status,
+    attempts = attempts + 1,
+    next_attempt_date = @next_attempt_date,
+    last_attempt_date = @last_attempt_date,
+    last_status_code = @last_status_code,
+    last_error = @last_error
+WHERE
+    delivery_id = @delivery_id;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET
+    url = @url,
+    status = @status,
+    last_modified_date = NOW()
+WHERE
+    subscription_id = @subscription_id
+RETURNING *;
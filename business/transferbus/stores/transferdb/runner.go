@@ -0,0 +1,138 @@
+package transferdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	SerializationFailureCode = "40001"
+	DeadlockDetectedCode     = "40P01"
+)
+
+// ErrRetriesExhausted is returned when a transaction keeps failing with a
+// serialization failure or a deadlock after every allowed attempt.
+var ErrRetriesExhausted = errors.New("transaction aborted by concurrent updates, retries exhausted")
+
+// RetryConfig controls how often a transaction failing with a retryable error
+// is run again, and how long to wait in between.
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryConfig is used when no retry configuration is provided.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts: 5,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+}
+
+// backoff returns a random delay up to the exponential backoff of the attempt
+// that just failed, capped at MaxDelay, so competing transactions spread out.
+func (cfg RetryConfig) backoff(attempt int) time.Duration {
+	ceiling := cfg.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := cfg.BaseDelay << shift; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// IsRetryable reports whether the error aborted the transaction because of a
+// concurrent transaction, in which case running it again can succeed.
+func IsRetryable(err error) bool {
+	var pgError *pgconn.PgError
+	if !errors.As(err, &pgError) {
+		return false
+	}
+	return pgError.Code == SerializationFailureCode || pgError.Code == DeadlockDetectedCode
+}
+
+// InTx runs fn in a database transaction begun with opts and commits it when
+// fn succeeds. A transaction failing with a serialization failure or a
+// deadlock is retried with jittered backoff, so fn must not keep state between
+// attempts.
+func (q *TxQueries) InTx(ctx context.Context, opts pgx.TxOptions, fn func(dbtx TxQuerier) error) error {
+	attempts := max(q.Retry.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(q.Retry.backoff(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("retry transaction: %w", ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		err = q.tryTx(ctx, opts, fn)
+		if !IsRetryable(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %d attempts: %w", ErrRetriesExhausted, attempts, err)
+}
+
+// InSavepoint runs fn in a savepoint of the current transaction, so a failure
+// of fn only rolls back what fn did. It is never retried on its own, since a
+// serialization failure or deadlock aborts the enclosing transaction.
+func (q *TxQueries) InSavepoint(ctx context.Context, fn func(dbtx TxQuerier) error) (err error) {
+	if q.tx == nil {
+		return errors.New("savepoint outside of a transaction")
+	}
+
+	sp, err := q.tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+	defer rollback(ctx, sp, &err)
+
+	if err := fn(q.WithTx(sp)); err != nil {
+		return err
+	}
+
+	if err := sp.Commit(ctx); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
+
+func (q *TxQueries) tryTx(ctx context.Context, opts pgx.TxOptions, fn func(dbtx TxQuerier) error) (err error) {
+	tx, err := q.GetTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("get transaction: %w", err)
+	}
+	defer rollback(ctx, tx, &err)
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// rollback rolls back tx unless it was committed, and joins a rollback failure
+// to the error returned through err.
+func rollback(ctx context.Context, tx pgx.Tx, err *error) {
+	if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+		*err = errors.Join(*err, fmt.Errorf("rollback: %w", rbErr))
+	}
+}
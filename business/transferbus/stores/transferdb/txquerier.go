@@ -12,30 +12,39 @@ import (
 type TxQuerier interface {
 	transferdbgen.Querier
 	WithTx(tx pgx.Tx) TxQuerier
-	GetTx(ctx context.Context) (pgx.Tx, error)
+	GetTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+	InTx(ctx context.Context, opts pgx.TxOptions, fn func(dbtx TxQuerier) error) error
+	InSavepoint(ctx context.Context, fn func(dbtx TxQuerier) error) error
+	Listen(ctx context.Context, channel string, listening func(), fn func(payload string)) error
 }
 
 var _ TxQuerier = (*TxQueries)(nil)
 
-func NewTxQueries(pool *pgxpool.Pool) *TxQueries {
+func NewTxQueries(pool *pgxpool.Pool, retry RetryConfig) *TxQueries {
 	return &TxQueries{
 		Queries: transferdbgen.New(pool),
 		TxnPool: pool,
+		Retry:   retry,
 	}
 }
 
 type TxQueries struct {
 	*transferdbgen.Queries
 	TxnPool *pgxpool.Pool
+	Retry   RetryConfig
+	tx      pgx.Tx
 }
 
-func (q *TxQueries) GetTx(ctx context.Context) (pgx.Tx, error) {
-	return q.TxnPool.Begin(ctx)
+// GetTx begins a transaction with the given isolation level and access mode.
+func (q *TxQueries) GetTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	return q.TxnPool.BeginTx(ctx, opts)
 }
 
 func (q *TxQueries) WithTx(tx pgx.Tx) TxQuerier {
 	return &TxQueries{
 		Queries: q.Queries.WithTx(tx),
 		TxnPool: q.TxnPool,
+		Retry:   q.Retry,
+		tx:      tx,
 	}
 }
@@ -0,0 +1,138 @@
+package transferbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/jackc/pgx/v5"
+)
+
+// accountEventsChannel is the Postgres channel notified with the account id of
+// every recorded event once its transaction commits.
+const accountEventsChannel = "account_events"
+
+// accountStreams wakes up the streams following the events of an account. A
+// wake up only signals that there may be new events, which streams read
+// themselves, so wake ups not yet received are coalesced into one.
+type accountStreams struct {
+	mu      sync.Mutex
+	streams map[int64]map[chan struct{}]struct{}
+}
+
+func newAccountStreams() *accountStreams {
+	return &accountStreams{
+		streams: make(map[int64]map[chan struct{}]struct{}),
+	}
+}
+
+func (s *accountStreams) subscribe(accountID int64) (chan struct{}, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if s.streams[accountID] == nil {
+		s.streams[accountID] = make(map[chan struct{}]struct{})
+	}
+	s.streams[accountID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.streams[accountID], ch)
+		if len(s.streams[accountID]) == 0 {
+			delete(s.streams, accountID)
+		}
+	}
+	return ch, unsubscribe
+}
+
+func (s *accountStreams) wake(accountID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.streams[accountID] {
+		wakeUp(ch)
+	}
+}
+
+func (s *accountStreams) wakeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, streams := range s.streams {
+		for ch := range streams {
+			wakeUp(ch)
+		}
+	}
+}
+
+func wakeUp(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// =============================================================================
+
+// ListenAccountEvents wakes up the account event streams of this service
+// whenever an event of their account is committed by any instance, until the
+// context is cancelled or the database connection fails. Every stream is also
+// woken up once listening starts, so it catches up on the events committed
+// while nothing was listening.
+func (b *Bus) ListenAccountEvents(ctx context.Context) error {
+	return b.store.Listen(ctx, accountEventsChannel, b.streams.wakeAll, func(payload string) {
+		accountID, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			b.log.Error(ctx, "account events", "status", "invalid notification", "payload", payload)
+			return
+		}
+		b.streams.wake(accountID)
+	})
+}
+
+// SubscribeAccountEvents returns a channel receiving a value whenever new
+// events of the account may have been committed, and a function ending the
+// subscription. Subscribing before reading the events of the account ensures
+// none committed in between are missed.
+func (b *Bus) SubscribeAccountEvents(accountID int64) (<-chan struct{}, func()) {
+	return b.streams.subscribe(accountID)
+}
+
+// QueryAccountEvents returns up to limit events of the account recorded after
+// afterEventID, oldest first.
+func (b *Bus) QueryAccountEvents(ctx context.Context, accountID int64, afterEventID int64, limit int) ([]Event, error) {
+	dbEvents, err := b.store.GetAccountOutboxEvents(ctx, transferdbgen.GetAccountOutboxEventsParams{
+		AccountID:    accountID,
+		AfterEventID: afterEventID,
+		RowLimit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get account outbox events: %d: %w", accountID, err)
+	}
+
+	events := make([]Event, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = toEvent(dbEvent)
+	}
+	return events, nil
+}
+
+// QueryLastAccountEvent returns the latest event of the account, whose Balance
+// is the current balance of the account.
+func (b *Bus) QueryLastAccountEvent(ctx context.Context, accountID int64) (Event, error) {
+	dbEvent, err := b.store.GetLastAccountOutboxEvent(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Event{}, ErrEventNotFound
+		}
+		return Event{}, fmt.Errorf("get last account outbox event: %d: %w", accountID, err)
+	}
+
+	return toEvent(dbEvent), nil
+}
@@ -7,11 +7,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
 	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/danipurwadi/internal-transfer-system/business/types/currency"
 	"github.com/danipurwadi/internal-transfer-system/foundation/logger"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -20,43 +25,152 @@ const (
 )
 
 var (
-	ErrAccNotFound       = errors.New("account not found")
-	ErrAccAlreadyExist   = errors.New("account already exist")
-	ErrNegativeBalance   = errors.New("negative balance")
-	ErrInsufficientFunds = errors.New("insufficient funds")
-	ErrSameAccount       = errors.New("source and destination account cannot be the same")
+	ErrAccNotFound          = errors.New("account not found")
+	ErrAccAlreadyExist      = errors.New("account already exist")
+	ErrNegativeBalance      = errors.New("negative balance")
+	ErrInsufficientFunds    = errors.New("insufficient funds")
+	ErrSameAccount          = errors.New("source and destination account cannot be the same")
+	ErrIdempotencyKeyReused = errors.New("idempotency key already used with a different request")
+	ErrTransferNotFound     = errors.New("transfer not found")
+	ErrUnbalancedEntry      = errors.New("journal entry postings do not sum to zero")
+	ErrCurrencyMismatch     = errors.New("source and destination accounts hold different currencies")
+	ErrQuoteNotFound        = errors.New("fx quote not found")
+	ErrQuoteExpired         = errors.New("fx quote expired")
+	ErrQuoteUsed            = errors.New("fx quote already used")
+	ErrQuoteMismatch        = errors.New("transaction does not match the fx quote")
+	ErrNotReversible        = errors.New("only transfers can be reversed")
+	ErrReversalExceeded     = errors.New("reversal exceeds the amount left to reverse")
+	ErrHoldNotFound         = errors.New("hold not found")
+	ErrHoldClosed           = errors.New("hold already captured, voided or expired")
+	ErrHoldExpired          = errors.New("hold expired")
+	ErrCaptureExceeded      = errors.New("capture exceeds the held amount")
+	ErrTxAborted            = transferdb.ErrRetriesExhausted
+	ErrAccFrozen            = errors.New("account is frozen")
+	ErrAccClosed            = errors.New("account is closed")
+	ErrAccNotEmpty          = errors.New("account balance must be zero or swept to another account")
+	ErrAccHasHolds          = errors.New("account has funds on hold")
+	ErrSystemAccount        = errors.New("system accounts cannot be frozen or closed")
+	ErrNegativeOverdraft    = errors.New("overdraft limit cannot be negative")
+	ErrOverdraftInUse       = errors.New("overdraft limit is below the overdraft already used")
+	ErrOverdraftHeld        = errors.New("overdraft limit is below the overdraft held for authorized holds")
+	ErrLimitExceeded        = errors.New("transfer limit exceeded")
+	ErrNegativeLimit        = errors.New("transfer limits cannot be negative")
+	ErrUnknownAccountType   = errors.New("unknown account type")
+	ErrZeroAmount           = errors.New("amount rounds to zero")
+	ErrScheduleNotFound     = errors.New("scheduled transfer not found")
+	ErrScheduleClosed       = errors.New("scheduled transfer already completed, failed or cancelled")
+	ErrInvalidRecurrence    = errors.New("invalid recurrence")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+	ErrInvalidWebhookURL    = errors.New("webhook url must be an absolute http or https url")
+	ErrInvalidWebhookStatus = errors.New("unknown webhook status")
+	ErrWebhookAddress       = errors.New("webhook url resolves to a non public address")
+	ErrEventNotFound        = errors.New("account has no events")
+	ErrAccIDReserved        = fmt.Errorf("account ids from %d are allocated by the service", FirstAllocatedAccountID)
 )
 
+// FirstAllocatedAccountID is the first id given to accounts opened without
+// one. Callers choosing their own ids must stay below it.
+const FirstAllocatedAccountID int64 = 1_000_000_000_000
+
+// Isolation holds the isolation level each kind of operation runs at.
+type Isolation struct {
+	// Write is used by operations changing a single transfer, hold or account.
+	Write pgx.TxIsoLevel
+	// Batch is used by batch transfers.
+	Batch pgx.TxIsoLevel
+	// Read is used by account statements and reconciliation, which always run
+	// read only.
+	Read pgx.TxIsoLevel
+}
+
+// DefaultIsolation relies on row locks for single writes, runs batches
+// serializable and reads from a single snapshot.
+var DefaultIsolation = Isolation{
+	Write: pgx.ReadCommitted,
+	Batch: pgx.Serializable,
+	Read:  pgx.RepeatableRead,
+}
+
 type Bus struct {
-	log   *logger.Logger
-	store transferdb.TxQuerier
+	log     *logger.Logger
+	store   transferdb.TxQuerier
+	fx      *fxbus.Bus
+	writeTx pgx.TxOptions
+	batchTx pgx.TxOptions
+	readTx  pgx.TxOptions
+	streams *accountStreams
+
+	privateWebhooks bool
 }
 
-func New(store transferdb.TxQuerier, log *logger.Logger) *Bus {
+func New(store transferdb.TxQuerier, fx *fxbus.Bus, iso Isolation, log *logger.Logger) *Bus {
 	return &Bus{
-		log:   log,
-		store: store,
+		log:     log,
+		store:   store,
+		fx:      fx,
+		writeTx: pgx.TxOptions{IsoLevel: iso.Write},
+		batchTx: pgx.TxOptions{IsoLevel: iso.Batch},
+		readTx:  pgx.TxOptions{IsoLevel: iso.Read, AccessMode: pgx.ReadOnly},
+		streams: newAccountStreams(),
 	}
 }
 
+// AllowPrivateWebhooks lets webhook subscriptions point at loopback, private
+// and link local addresses. Deliveries to them still need a WebhookConfig
+// allowing private networks. It must be called before the bus is used.
+func (b *Bus) AllowPrivateWebhooks() {
+	b.privateWebhooks = true
+}
+
 func (b *Bus) CreateAccount(ctx context.Context, account NewAccount) (Account, error) {
+	if account.AccountID >= FirstAllocatedAccountID {
+		return Account{}, ErrAccIDReserved
+	}
 	if account.InitialBalance.IsNegative() {
 		return Account{}, ErrNegativeBalance
 	}
-	tx, err := b.store.GetTx(ctx)
+	if account.Currency.IsZero() {
+		account.Currency = currency.Default
+	}
+	if account.Type == "" {
+		account.Type = AccountTypeCustomer
+	}
+	if account.Labels == nil {
+		account.Labels = []string{}
+	}
+	account.InitialBalance = account.Currency.Round(account.InitialBalance)
+
+	var acc Account
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		acc, err = createAccount(ctx, dbtx, account)
+		return err
+	})
 	if err != nil {
-		return Account{}, fmt.Errorf("get transaction: %w", err)
+		return Account{}, err
 	}
-	dbtx := b.store.WithTx(tx)
-	defer func() {
-		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			b.log.Error(ctx, "rollback failed", "err", err)
+	return acc, nil
+}
+
+// createAccount creates the account, allocating its id when it has none, and
+// funds its initial balance as part of the given database transaction.
+func createAccount(ctx context.Context, dbtx transferdb.TxQuerier, account NewAccount) (Account, error) {
+	if account.AccountID == 0 {
+		accountID, err := dbtx.NextAccountID(ctx)
+		if err != nil {
+			return Account{}, fmt.Errorf("next account id: %w", err)
 		}
-	}()
+		account.AccountID = accountID
+	}
 
-	acc, err := dbtx.CreateAccount(ctx, transferdbgen.CreateAccountParams{
+	dbAcc, err := dbtx.CreateAccount(ctx, transferdbgen.CreateAccountParams{
 		AccountID:        account.AccountID,
-		Balance:          account.InitialBalance,
+		Balance:          decimal.Zero,
+		Currency:         account.Currency.String(),
+		OwnerRef:         account.OwnerRef,
+		AccountType:      string(account.Type),
+		Name:             account.Name,
+		Labels:           account.Labels,
 		CreatedDate:      time.Now(),
 		LastModifiedDate: time.Now(),
 	})
@@ -69,102 +183,264 @@ func (b *Bus) CreateAccount(ctx context.Context, account NewAccount) (Account, e
 		return Account{}, fmt.Errorf("create: %w", err)
 	}
 
-	err = dbtx.CreateTransaction(ctx, transferdbgen.CreateTransactionParams{
-		AccountID:   account.AccountID,
-		Amount:      account.InitialBalance,
-		CreatedDate: time.Now(),
-	})
-
+	// the account is announced before the opening balance funding it
+	created, err := fromDBAccount(dbAcc)
 	if err != nil {
-		return Account{}, fmt.Errorf("create transaction: %w", err)
+		return Account{}, err
+	}
+	if err := recordEvent(ctx, dbtx, EventAccountCreated, created.AccountID, created.Balance, toAccountPayload(created)); err != nil {
+		return Account{}, err
+	}
+
+	// fund the initial balance from the equity account of the currency
+	if account.InitialBalance.IsPositive() {
+		equityAccountID, err := systemAccount(ctx, dbtx, SystemAccountEquity, account.Currency)
+		if err != nil {
+			return Account{}, err
+		}
+
+		_, err = postJournalEntry(ctx, dbtx, journalEntry{
+			TransferID:           uuid.New(),
+			EntryType:            EntryTypeOpeningBalance,
+			SourceAccountID:      equityAccountID,
+			DestinationAccountID: account.AccountID,
+			Amount:               account.InitialBalance,
+			Currency:             account.Currency,
+			Postings: []posting{
+				{AccountID: equityAccountID, Amount: account.InitialBalance.Neg(), Currency: account.Currency},
+				{AccountID: account.AccountID, Amount: account.InitialBalance, Currency: account.Currency},
+			},
+		})
+		if err != nil {
+			return Account{}, fmt.Errorf("post opening balance: %w", err)
+		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return Account{}, fmt.Errorf("commit transaction: %w", err)
+	dbAcc, err = dbtx.GetAccount(ctx, account.AccountID)
+	if err != nil {
+		return Account{}, fmt.Errorf("get account: %d: %w", account.AccountID, err)
 	}
 
-	return fromDBAccount(acc), nil
+	return fromDBAccount(dbAcc)
+}
+
+func (b *Bus) CreateTransaction(ctx context.Context, transaction Transaction) (Transfer, error) {
+	var transfer Transfer
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		transfer, err = createTransaction(ctx, dbtx, transaction)
+		return err
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+	return transfer, nil
 }
 
-func (b *Bus) CreateTransaction(ctx context.Context, transaction Transaction) error {
+// createTransaction posts the transfer of the transaction as part of the given
+// database transaction.
+func createTransaction(ctx context.Context, dbtx transferdb.TxQuerier, transaction Transaction) (Transfer, error) {
 	if transaction.Amount.IsNegative() {
-		return ErrNegativeBalance
+		return Transfer{}, ErrNegativeBalance
 	}
 	if transaction.SourceAccountID == transaction.DestinationAccountID {
-		return ErrSameAccount
+		return Transfer{}, ErrSameAccount
+	}
+
+	transferID := uuid.New()
+
+	// a replayed idempotency key returns the original outcome without moving funds again
+	if transaction.IdempotencyKey != "" {
+		original, replayed, err := claimIdempotencyKey(ctx, dbtx, transaction, transferID)
+		if err != nil {
+			return Transfer{}, err
+		}
+		if replayed {
+			return original, nil
+		}
 	}
 
-	tx, err := b.store.GetTx(ctx)
+	// lock both accounts before moving funds, in the same order as every other
+	// transfer, so transfers in opposite directions queue instead of deadlocking
+	accounts, err := dbtx.LockAccounts(ctx, []int64{transaction.SourceAccountID, transaction.DestinationAccountID})
 	if err != nil {
-		return fmt.Errorf("get transaction: %w", err)
+		return Transfer{}, fmt.Errorf("lock accounts: %w", err)
+	}
+	if len(accounts) != 2 {
+		return Transfer{}, ErrAccNotFound
 	}
-	dbtx := b.store.WithTx(tx)
-	defer func() {
-		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			b.log.Error(ctx, "rollback failed", "err", err)
+
+	// frozen and closed accounts are reported before any limit they would break
+	var source transferdbgen.Account
+	for _, acc := range accounts {
+		if err := checkActive(AccountStatus(acc.Status)); err != nil {
+			return Transfer{}, fmt.Errorf("account[%d]: %w", acc.AccountID, err)
 		}
-	}()
+		if acc.AccountID == transaction.SourceAccountID {
+			source = acc
+		}
+	}
 
-	// check that both accounts exist
-	accounts, err := dbtx.GetAccounts(ctx, []int64{transaction.SourceAccountID, transaction.DestinationAccountID})
+	// the amount is posted in the currency of the source account, so it is
+	// rounded before it counts against the limits or is recorded
+	cur, err := currency.Parse(source.Currency)
 	if err != nil {
-		return fmt.Errorf("get accounts: %w", err)
+		return Transfer{}, fmt.Errorf("parse currency: account[%d]: %w", source.AccountID, err)
 	}
-	if len(accounts) != 2 {
-		return ErrAccNotFound
+	transaction.Amount = cur.Round(transaction.Amount)
+	if !transaction.Amount.IsPositive() {
+		return Transfer{}, ErrZeroAmount
 	}
 
-	debitResult, err := dbtx.DebitAccount(ctx, transferdbgen.DebitAccountParams{
-		Amount:    transaction.Amount,
-		AccountID: transaction.SourceAccountID,
-	})
+	if err := checkLimits(ctx, dbtx, source, transaction.Amount); err != nil {
+		return Transfer{}, err
+	}
+
+	// transfers between currencies convert at the rate locked by a quote
+	var entry journalEntry
+	if transaction.QuoteID != uuid.Nil {
+		entry, err = fxJournalEntry(ctx, dbtx, transaction, transferID)
+	} else {
+		entry, err = transferJournalEntry(transaction, transferID, accounts)
+	}
 	if err != nil {
-		return fmt.Errorf("debit account: %w", err)
+		return Transfer{}, err
 	}
 
-	// if no rows is updated, balance was too low
-	if debitResult.RowsAffected() == 0 {
-		return ErrInsufficientFunds
+	transfer, err := postJournalEntry(ctx, dbtx, entry)
+	if err != nil {
+		return Transfer{}, err
 	}
 
-	// if Debit was successful, credit the destination account
-	_, err = dbtx.CreditAccount(ctx, transferdbgen.CreditAccountParams{
-		Amount:    transaction.Amount,
-		AccountID: transaction.DestinationAccountID,
-	})
+	if transfer.SourceBalance, err = dbtx.GetBalance(ctx, transaction.SourceAccountID); err != nil {
+		return Transfer{}, fmt.Errorf("get source balance: %w", err)
+	}
+
+	// the balance is kept with the key so replays return the same response
+	if transaction.IdempotencyKey != "" {
+		if err := dbtx.SetIdempotencyKeySourceBalance(ctx, transferdbgen.SetIdempotencyKeySourceBalanceParams{
+			SourceBalance:  transfer.SourceBalance,
+			IdempotencyKey: transaction.IdempotencyKey,
+		}); err != nil {
+			return Transfer{}, fmt.Errorf("set idempotency key source balance: %w", err)
+		}
+	}
+
+	return transfer, nil
+}
+
+// transferJournalEntry builds the journal entry of a transfer between accounts
+// holding the same currency.
+func transferJournalEntry(transaction Transaction, transferID uuid.UUID, dbAccounts []transferdbgen.Account) (journalEntry, error) {
+	cur, err := transferCurrency(transaction, dbAccounts)
 	if err != nil {
-		return fmt.Errorf("credit account: %w", err)
+		return journalEntry{}, err
 	}
 
-	// Record Credit Transaction
-	err = dbtx.CreateTransaction(ctx, transferdbgen.CreateTransactionParams{
-		AccountID:   transaction.SourceAccountID,
-		Amount:      transaction.Amount.Neg(),
-		CreatedDate: time.Now(),
-	})
+	return journalEntry{
+		TransferID:           transferID,
+		EntryType:            EntryTypeTransfer,
+		SourceAccountID:      transaction.SourceAccountID,
+		DestinationAccountID: transaction.DestinationAccountID,
+		Amount:               transaction.Amount,
+		Currency:             cur,
+		Postings: []posting{
+			{AccountID: transaction.SourceAccountID, Amount: transaction.Amount.Neg(), Currency: cur},
+			{AccountID: transaction.DestinationAccountID, Amount: transaction.Amount, Currency: cur},
+		},
+	}, nil
+}
+
+// transferCurrency returns the currency shared by the accounts of the
+// transaction. It fails with ErrCurrencyMismatch when the accounts hold
+// different currencies or the transaction is made in another one.
+func transferCurrency(transaction Transaction, dbAccounts []transferdbgen.Account) (currency.Currency, error) {
+	var src, dst string
+	for _, dbAccount := range dbAccounts {
+		switch dbAccount.AccountID {
+		case transaction.SourceAccountID:
+			src = dbAccount.Currency
+		case transaction.DestinationAccountID:
+			dst = dbAccount.Currency
+		}
+	}
+
+	if src != dst {
+		return currency.Currency{}, fmt.Errorf("%w: %s to %s", ErrCurrencyMismatch, src, dst)
+	}
+	if !transaction.Currency.IsZero() && transaction.Currency.String() != src {
+		return currency.Currency{}, fmt.Errorf("%w: transfer in %s between %s accounts", ErrCurrencyMismatch, transaction.Currency, src)
+	}
 
+	cur, err := currency.Parse(src)
 	if err != nil {
-		return fmt.Errorf("create transaction: %w", err)
+		return currency.Currency{}, fmt.Errorf("parse currency: %w", err)
 	}
 
-	// Record Debit Transaction
-	err = dbtx.CreateTransaction(ctx, transferdbgen.CreateTransactionParams{
-		AccountID:   transaction.DestinationAccountID,
-		Amount:      transaction.Amount,
-		CreatedDate: time.Now(),
+	return cur, nil
+}
+
+// claimIdempotencyKey records the idempotency key of the transaction as part of
+// the given database transaction, so the key is only persisted if the transfer
+// commits. When the key was already used by an identical request, it returns
+// the transfer created by that request. Concurrent requests with the same key
+// block on the primary key until the first one commits or rolls back.
+func claimIdempotencyKey(ctx context.Context, dbtx transferdb.TxQuerier, transaction Transaction, transferID uuid.UUID) (Transfer, bool, error) {
+	requestHash := transaction.requestHash()
+
+	result, err := dbtx.CreateIdempotencyKey(ctx, transferdbgen.CreateIdempotencyKeyParams{
+		IdempotencyKey: transaction.IdempotencyKey,
+		RequestHash:    requestHash,
+		TransferID:     pgtype.UUID{Bytes: transferID, Valid: true},
+		CreatedDate:    time.Now(),
 	})
+	if err != nil {
+		return Transfer{}, false, fmt.Errorf("create idempotency key: %w", err)
+	}
+	if result.RowsAffected() == 1 {
+		return Transfer{}, false, nil
+	}
 
+	key, err := dbtx.GetIdempotencyKey(ctx, transaction.IdempotencyKey)
 	if err != nil {
-		return fmt.Errorf("create transaction: %w", err)
+		return Transfer{}, false, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if key.RequestHash != requestHash {
+		return Transfer{}, false, ErrIdempotencyKeyReused
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+	// keys recorded before transfers had an identifier can only echo the request
+	original := Transfer{
+		EntryType:            EntryTypeTransfer,
+		SourceAccountID:      transaction.SourceAccountID,
+		DestinationAccountID: transaction.DestinationAccountID,
+		Amount:               transaction.Amount,
+		Currency:             transaction.Currency,
+		CreatedDate:          key.CreatedDate,
 	}
-	return nil
+	if key.TransferID.Valid {
+		if original, err = queryTransfer(ctx, dbtx, key.TransferID.Bytes); err != nil {
+			return Transfer{}, false, err
+		}
+	}
+
+	// keys recorded before the balance was kept with them report the current one
+	if key.SourceBalance.Valid {
+		original.SourceBalance = decimal.NewFromBigInt(key.SourceBalance.Int, key.SourceBalance.Exp)
+	} else if original.SourceBalance, err = dbtx.GetBalance(ctx, transaction.SourceAccountID); err != nil {
+		return Transfer{}, false, fmt.Errorf("get source balance: %w", err)
+	}
+
+	return original, true, nil
 }
 
+// GetBalance returns the account with its balance. System accounts are
+// reported as not found, so they are never shown outside the ledger.
 func (b *Bus) GetBalance(ctx context.Context, accountID int64) (Account, error) {
+	if accountID < 0 {
+		return Account{}, ErrAccNotFound
+	}
+
 	// check that account exist in the first place
 	account, err := b.store.GetAccount(ctx, accountID)
 	if err != nil {
@@ -174,5 +450,76 @@ func (b *Bus) GetBalance(ctx context.Context, accountID int64) (Account, error)
 		return Account{}, fmt.Errorf("get account: %d: %w", accountID, err)
 	}
 
-	return fromDBAccount(account), nil
+	return fromDBAccount(account)
+}
+
+// UpdateAccount changes the metadata of the account. System accounts are
+// reported as not found, so their metadata cannot be changed.
+func (b *Bus) UpdateAccount(ctx context.Context, accountID int64, ua UpdateAccount) (Account, error) {
+	if accountID < 0 {
+		return Account{}, ErrAccNotFound
+	}
+
+	dbAccount, err := b.store.UpdateAccountMetadata(ctx, toDBUpdateAccount(accountID, ua))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Account{}, ErrAccNotFound
+		}
+		return Account{}, fmt.Errorf("update account metadata: %d: %w", accountID, err)
+	}
+
+	return fromDBAccount(dbAccount)
+}
+
+// QueryAccounts returns a page of the accounts matching the filter.
+func (b *Bus) QueryAccounts(ctx context.Context, filter AccountFilter) ([]Account, error) {
+	dbAccounts, err := b.store.QueryAccounts(ctx, toDBAccountFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("query accounts: %w", err)
+	}
+
+	accounts := make([]Account, len(dbAccounts))
+	for i, dbAccount := range dbAccounts {
+		if accounts[i], err = fromDBAccount(dbAccount); err != nil {
+			return nil, err
+		}
+	}
+
+	return accounts, nil
+}
+
+func (b *Bus) QueryTransfer(ctx context.Context, transferID uuid.UUID) (Transfer, error) {
+	return queryTransfer(ctx, b.store, transferID)
+}
+
+// QueryAccountTransactions returns a page of the account statement. The
+// account and its transactions are read from the same snapshot.
+func (b *Bus) QueryAccountTransactions(ctx context.Context, filter TransactionFilter) ([]TransferLeg, error) {
+	var legs []TransferLeg
+	err := b.store.InTx(ctx, b.readTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		legs, err = queryAccountTransactions(ctx, dbtx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return legs, nil
+}
+
+func queryAccountTransactions(ctx context.Context, dbtx transferdb.TxQuerier, filter TransactionFilter) ([]TransferLeg, error) {
+	// check that account exist in the first place
+	if _, err := dbtx.GetAccount(ctx, filter.AccountID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccNotFound
+		}
+		return nil, fmt.Errorf("get account: %d: %w", filter.AccountID, err)
+	}
+
+	dbTransactions, err := dbtx.QueryAccountTransactions(ctx, toDBTransactionFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("query account transactions: %d: %w", filter.AccountID, err)
+	}
+
+	return fromDBTransactions(dbTransactions)
 }
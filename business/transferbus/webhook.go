@@ -0,0 +1,525 @@
+package transferbus
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
+	transferdbgen "github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Headers sent with every webhook delivery. Receivers verify a delivery by
+// comparing the signature header with SignWebhook of the timestamp header and
+// the body, and deduplicate retried deliveries on the id header.
+const (
+	WebhookIDHeader        = "Webhook-Id"
+	WebhookEventHeader     = "Webhook-Event"
+	WebhookTimestampHeader = "Webhook-Timestamp"
+	WebhookSignatureHeader = "Webhook-Signature"
+)
+
+// DefaultWebhookBatchSize is the number of due webhook deliveries claimed at
+// once.
+const DefaultWebhookBatchSize = 20
+
+// maxWebhookErrorLen bounds how much of a failed response is kept as the
+// error of the delivery.
+const maxWebhookErrorLen = 512
+
+// WebhookConfig controls how webhook deliveries are sent and how often a
+// failed delivery is attempted again before it is dead. Deliveries are only
+// sent to public addresses unless AllowPrivateNetworks is set.
+type WebhookConfig struct {
+	Timeout              time.Duration
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	AllowPrivateNetworks bool
+}
+
+// DefaultWebhookConfig is used when no webhook configuration is provided.
+var DefaultWebhookConfig = WebhookConfig{
+	Timeout:     10 * time.Second,
+	MaxAttempts: 10,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    6 * time.Hour,
+}
+
+// backoff returns how long to wait after the attempt that just failed. It
+// doubles with every attempt, up to MaxDelay.
+func (cfg WebhookConfig) backoff(attempt int) time.Duration {
+	delay := cfg.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := cfg.BaseDelay << shift; d >= 0 && d < delay {
+			delay = d
+		}
+	}
+	return max(delay, 0)
+}
+
+// lease returns how long claimed deliveries are held back from other workers.
+// It covers every delivery of a batch timing out, so a delivery is only sent
+// again once the worker that claimed it is gone.
+func (cfg WebhookConfig) lease() time.Duration {
+	return time.Duration(DefaultWebhookBatchSize+1) * cfg.Timeout
+}
+
+// client returns the http client deliveries are sent with. Addresses are
+// checked once resolved, so a url cannot reach the internal network by
+// resolving to it or by redirecting to it.
+func (cfg WebhookConfig) client() *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = func(network string, address string, _ syscall.RawConn) error {
+			return checkWebhookAddress(address)
+		}
+	}
+
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: cfg.Timeout,
+			MaxIdleConns:        DefaultWebhookBatchSize,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// SignWebhook returns the signature of a webhook delivery: the hex encoded
+// HMAC-SHA256 of the timestamp and the body joined by a dot, keyed with the
+// secret of the subscription.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateWebhookSubscription subscribes URL to the events of an account. The
+// subscription is sent every event recorded from then on.
+func (b *Bus) CreateWebhookSubscription(ctx context.Context, nw NewWebhookSubscription) (WebhookSubscription, error) {
+	if err := b.checkWebhookURL(ctx, nw.URL); err != nil {
+		return WebhookSubscription{}, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+
+	var sub WebhookSubscription
+	err = b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		if _, err := dbtx.GetAccount(ctx, nw.AccountID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrAccNotFound
+			}
+			return fmt.Errorf("get account: %d: %w", nw.AccountID, err)
+		}
+
+		now := time.Now()
+		dbSub, err := dbtx.CreateWebhookSubscription(ctx, transferdbgen.CreateWebhookSubscriptionParams{
+			SubscriptionID:   uuid.New(),
+			AccountID:        nw.AccountID,
+			Url:              nw.URL,
+			Secret:           secret,
+			Status:           string(WebhookStatusActive),
+			CreatedDate:      now,
+			LastModifiedDate: now,
+		})
+		if err != nil {
+			return fmt.Errorf("create webhook subscription: %w", err)
+		}
+
+		sub = toWebhookSubscription(dbSub)
+		return nil
+	})
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+	return sub, nil
+}
+
+// QueryWebhookSubscription returns the webhook subscription with the given id.
+func (b *Bus) QueryWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) (WebhookSubscription, error) {
+	dbSub, err := b.store.GetWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return WebhookSubscription{}, ErrWebhookNotFound
+		}
+		return WebhookSubscription{}, fmt.Errorf("get webhook subscription: %s: %w", subscriptionID, err)
+	}
+
+	return toWebhookSubscription(dbSub), nil
+}
+
+// QueryAccountWebhookSubscriptions returns the webhook subscriptions of an
+// account in the order they were created.
+func (b *Bus) QueryAccountWebhookSubscriptions(ctx context.Context, accountID int64) ([]WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	err := b.store.InTx(ctx, b.readTx, func(dbtx transferdb.TxQuerier) error {
+		if _, err := dbtx.GetAccount(ctx, accountID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrAccNotFound
+			}
+			return fmt.Errorf("get account: %d: %w", accountID, err)
+		}
+
+		dbSubs, err := dbtx.QueryAccountWebhookSubscriptions(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("query account webhook subscriptions: %d: %w", accountID, err)
+		}
+
+		subs = make([]WebhookSubscription, len(dbSubs))
+		for i, dbSub := range dbSubs {
+			subs[i] = toWebhookSubscription(dbSub)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// UpdateWebhookSubscription changes the url of a webhook subscription or pauses
+// and resumes it.
+func (b *Bus) UpdateWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID, uw UpdateWebhookSubscription) (WebhookSubscription, error) {
+	if uw.URL != nil {
+		if err := b.checkWebhookURL(ctx, *uw.URL); err != nil {
+			return WebhookSubscription{}, err
+		}
+	}
+	if uw.Status != nil && *uw.Status != WebhookStatusActive && *uw.Status != WebhookStatusPaused {
+		return WebhookSubscription{}, fmt.Errorf("%w: %q", ErrInvalidWebhookStatus, *uw.Status)
+	}
+
+	var sub WebhookSubscription
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		dbSub, err := dbtx.GetWebhookSubscription(ctx, subscriptionID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrWebhookNotFound
+			}
+			return fmt.Errorf("get webhook subscription: %s: %w", subscriptionID, err)
+		}
+
+		if uw.URL != nil {
+			dbSub.Url = *uw.URL
+		}
+		if uw.Status != nil {
+			dbSub.Status = string(*uw.Status)
+		}
+
+		dbSub, err = dbtx.UpdateWebhookSubscription(ctx, transferdbgen.UpdateWebhookSubscriptionParams{
+			Url:            dbSub.Url,
+			Status:         dbSub.Status,
+			SubscriptionID: subscriptionID,
+		})
+		if err != nil {
+			return fmt.Errorf("update webhook subscription: %s: %w", subscriptionID, err)
+		}
+
+		sub = toWebhookSubscription(dbSub)
+		return nil
+	})
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+	return sub, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription together with its
+// delivery log. Deliveries still pending are never sent.
+func (b *Bus) DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	tag, err := b.store.DeleteWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription: %s: %w", subscriptionID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// QueryWebhookDeliveries returns a page of the delivery log of a webhook
+// subscription, newest first.
+func (b *Bus) QueryWebhookDeliveries(ctx context.Context, filter DeliveryFilter) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := b.store.InTx(ctx, b.readTx, func(dbtx transferdb.TxQuerier) error {
+		if _, err := dbtx.GetWebhookSubscription(ctx, filter.SubscriptionID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrWebhookNotFound
+			}
+			return fmt.Errorf("get webhook subscription: %s: %w", filter.SubscriptionID, err)
+		}
+
+		dbDeliveries, err := dbtx.QueryWebhookDeliveries(ctx, transferdbgen.QueryWebhookDeliveriesParams{
+			SubscriptionID: filter.SubscriptionID,
+			Cursor:         pgtype.Int8{Int64: filter.Cursor, Valid: filter.Cursor != 0},
+			Status:         pgtype.Text{String: string(filter.Status), Valid: filter.Status != ""},
+			RowLimit:       int32(filter.Limit),
+		})
+		if err != nil {
+			return fmt.Errorf("query webhook deliveries: %s: %w", filter.SubscriptionID, err)
+		}
+
+		deliveries = toWebhookDeliveries(dbDeliveries)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// DeliverWebhooks attempts every webhook delivery that is due and returns how
+// many were attempted. Deliveries failing for the MaxAttempts time are dead,
+// the others are attempted again after a backoff. Deliveries claimed by a
+// concurrent run are left to it, so several workers can share the work.
+func (b *Bus) DeliverWebhooks(ctx context.Context, cfg WebhookConfig) (int, error) {
+	client := cfg.client()
+	defer client.CloseIdleConnections()
+
+	// deliveries failing during this run are not due again before it ends
+	now := time.Now()
+
+	var attempted int
+	for {
+		n, err := b.deliverWebhooksBatch(ctx, cfg, client, now)
+		if err != nil {
+			return attempted, err
+		}
+		attempted += n
+
+		if n < DefaultWebhookBatchSize {
+			return attempted, nil
+		}
+	}
+}
+
+// deliverWebhooksBatch claims a batch of due deliveries, then sends each of
+// them outside of any database transaction and records its outcome. A
+// delivery whose outcome is never recorded is sent again once its lease
+// expires, so delivery is at least once.
+func (b *Bus) deliverWebhooksBatch(ctx context.Context, cfg WebhookConfig, client *http.Client, now time.Time) (int, error) {
+	dbDeliveries, err := b.claimWebhookDeliveries(ctx, cfg, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dbDelivery := range dbDeliveries {
+		if err := b.deliverWebhook(ctx, cfg, client, dbDelivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(dbDeliveries), nil
+}
+
+// claimWebhookDeliveries locks a batch of due deliveries and leases them by
+// moving their next attempt past the time it takes to send them, so other
+// workers skip them once the claim commits.
+func (b *Bus) claimWebhookDeliveries(ctx context.Context, cfg WebhookConfig, now time.Time) ([]transferdbgen.LockDueWebhookDeliveriesRow, error) {
+	var dbDeliveries []transferdbgen.LockDueWebhookDeliveriesRow
+	err := b.store.InTx(ctx, b.writeTx, func(dbtx transferdb.TxQuerier) error {
+		var err error
+		dbDeliveries, err = dbtx.LockDueWebhookDeliveries(ctx, transferdbgen.LockDueWebhookDeliveriesParams{
+			Now:      now,
+			RowLimit: DefaultWebhookBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("lock due webhook deliveries: %w", err)
+		}
+		if len(dbDeliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(dbDeliveries))
+		for i, dbDelivery := range dbDeliveries {
+			ids[i] = dbDelivery.DeliveryID
+		}
+
+		if err := dbtx.LeaseWebhookDeliveries(ctx, transferdbgen.LeaseWebhookDeliveriesParams{
+			LeaseUntil:  time.Now().Add(cfg.lease()),
+			DeliveryIds: ids,
+		}); err != nil {
+			return fmt.Errorf("lease webhook deliveries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbDeliveries, nil
+}
+
+// deliverWebhook sends the delivery and records the outcome of the attempt.
+func (b *Bus) deliverWebhook(ctx context.Context, cfg WebhookConfig, client *http.Client, d transferdbgen.LockDueWebhookDeliveriesRow) error {
+	statusCode, sendErr := sendWebhook(ctx, client, d)
+	if sendErr != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	attemptDate := time.Now()
+	attempt := int(d.Attempts) + 1
+
+	status := DeliveryStatusDelivered
+	nextAttempt := attemptDate
+	var lastError string
+	if sendErr != nil {
+		lastError = sendErr.Error()
+
+		status = DeliveryStatusPending
+		nextAttempt = attemptDate.Add(cfg.backoff(attempt))
+		if attempt >= cfg.MaxAttempts {
+			status = DeliveryStatusDead
+		}
+
+		b.log.Info(ctx, "webhook delivery", "status", status, "deliveryId", d.DeliveryID, "attempt", attempt, "msg", sendErr)
+	}
+
+	if err := b.store.RecordWebhookDeliveryAttempt(ctx, transferdbgen.RecordWebhookDeliveryAttemptParams{
+		Status:          string(status),
+		NextAttemptDate: nextAttempt,
+		LastAttemptDate: pgtype.Timestamptz{Time: attemptDate, Valid: true},
+		LastStatusCode:  int32(statusCode),
+		LastError:       lastError,
+		DeliveryID:      d.DeliveryID,
+	}); err != nil {
+		return fmt.Errorf("record webhook delivery attempt: %d: %w", d.DeliveryID, err)
+	}
+
+	return nil
+}
+
+// sendWebhook posts the signed event to the subscription and returns the status
+// code of the response. Any response outside 2xx fails the attempt.
+func sendWebhook(ctx context.Context, client *http.Client, d transferdbgen.LockDueWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(eventMessage{
+		EventID:     d.EventID,
+		Type:        EventType(d.EventType),
+		AccountID:   d.AccountID,
+		Balance:     d.Balance,
+		Payload:     d.Payload,
+		CreatedDate: d.CreatedDate,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, strconv.FormatInt(d.DeliveryID, 10))
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(d.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorLen))
+	return resp.StatusCode, fmt.Errorf("receiver responded %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+}
+
+// checkWebhookURL reports whether deliveries can be posted to the url. Unless
+// private webhooks are allowed, a host given as an address or resolving to one
+// that deliveries would be refused at is rejected up front. A host that does
+// not resolve yet is accepted and left to the check made when dialing, which
+// also covers a host resolving to another address later on.
+func (b *Bus) checkWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if b.privateWebhooks {
+		return nil
+	}
+
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return checkWebhookIP(ip)
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if err := checkWebhookIP(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkWebhookAddress reports whether deliveries can be sent to the resolved
+// address.
+func checkWebhookAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddress, address)
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddress, address)
+	}
+	return checkWebhookIP(ip)
+}
+
+// checkWebhookIP reports whether deliveries can be sent to the ip. Loopback,
+// private, link local and other non public addresses, including the cloud
+// metadata endpoint, are refused.
+func checkWebhookIP(ip netip.Addr) error {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddress, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier grade NAT range, which is not routed on
+// the internet but is not reported as private either.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// newWebhookSecret returns a random secret to sign the deliveries of a
+// subscription with.
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
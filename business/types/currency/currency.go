@@ -0,0 +1,107 @@
+// Package currency represents the ISO-4217 currencies supported by the system.
+package currency
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// The set of currencies that can be used.
+var (
+	EUR = newCurrency("EUR", 2)
+	GBP = newCurrency("GBP", 2)
+	IDR = newCurrency("IDR", 2)
+	JPY = newCurrency("JPY", 0)
+	KWD = newCurrency("KWD", 3)
+	SGD = newCurrency("SGD", 2)
+	USD = newCurrency("USD", 2)
+)
+
+// Default is the currency of accounts created without one.
+var Default = USD
+
+// =============================================================================
+
+// Set of known currencies.
+var currencies = make(map[string]Currency)
+
+// Currency represents a currency and the number of decimal places its amounts
+// are kept with.
+type Currency struct {
+	code      string
+	precision int32
+}
+
+func newCurrency(code string, precision int32) Currency {
+	c := Currency{code: code, precision: precision}
+	currencies[code] = c
+	return c
+}
+
+// String returns the ISO-4217 code of the currency.
+func (c Currency) String() string {
+	return c.code
+}
+
+// Precision returns the number of decimal places of the minor unit of the
+// currency.
+func (c Currency) Precision() int32 {
+	return c.precision
+}
+
+// Round rounds the amount to the precision of the currency, half away from
+// zero.
+func (c Currency) Round(amount decimal.Decimal) decimal.Decimal {
+	return amount.Round(c.precision)
+}
+
+// IsZero reports whether the currency was never set.
+func (c Currency) IsZero() bool {
+	return c.code == ""
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (c Currency) Equal(c2 Currency) bool {
+	return c.code == c2.code
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c.code), nil
+}
+
+// =============================================================================
+
+// Parse parses the string value and returns a currency if one exists.
+func Parse(value string) (Currency, error) {
+	c, exists := currencies[value]
+	if !exists {
+		return Currency{}, fmt.Errorf("invalid currency %q", value)
+	}
+
+	return c, nil
+}
+
+// MustParse parses the string value and returns a currency if one exists. If
+// an error occurs the function panics.
+func MustParse(value string) Currency {
+	c, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Codes returns the codes of all supported currencies in alphabetical order.
+func Codes() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	return codes
+}
@@ -2,6 +2,7 @@ package customerror
 
 import (
 	"fmt"
+	"slices"
 )
 
 var (
@@ -139,6 +140,11 @@ func (ec ErrCode) MarshalText() ([]byte, error) {
 	return []byte(ec.String()), nil
 }
 
+// CodeNames returns the names of every error code, ordered by value.
+func CodeNames() []string {
+	return slices.Clone(codeNames[:])
+}
+
 // Equal provides support for the go-cmp package and testing.
 func (ec ErrCode) Equal(ec2 ErrCode) bool {
 	return ec.value == ec2.value
@@ -0,0 +1,21 @@
+package customerror
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatus returns the gRPC status of the error. The error codes share their
+// values with the gRPC codes, so the code maps over unchanged. gRPC servers
+// call it for the errors returned by handlers.
+func (err Error) GRPCStatus() *status.Status {
+	return status.New(codes.Code(err.Code.Value()), err.Message)
+}
+
+// FromStatus constructs an error from a gRPC status, as returned to clients.
+func FromStatus(st *status.Status) Error {
+	return Error{
+		Code:    ErrCode{value: int(st.Code())},
+		Message: st.Message(),
+	}
+}
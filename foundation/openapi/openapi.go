@@ -0,0 +1,328 @@
+// Package openapi builds OpenAPI 3 documents describing a web API. The schemas
+// of request and response bodies are generated from Go types, using their json
+// tags for property names and their validate tags for constraints.
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the OpenAPI version of the documents.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document. Paths are keyed by path and then by the
+// lowercase HTTP method.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema describes a JSON value. Structs are described once in the components
+// of the document and referred to with Ref.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// New constructs an empty document.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+}
+
+// Add describes the operation served for a given HTTP method and path pair.
+func (d *Document) Add(method string, path string, op Operation) {
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(map[string]*Operation)
+	}
+	d.Paths[path][strings.ToLower(method)] = &op
+}
+
+// Operation returns the operation served for a given HTTP method and path
+// pair, or nil when it is not described.
+func (d *Document) Operation(method string, path string) *Operation {
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+// Schema returns the schema of the type of v. Structs are added to the
+// components of the document and referred to.
+func (d *Document) Schema(v any) *Schema {
+	return d.schemaOf(reflect.TypeOf(v))
+}
+
+// =============================================================================
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return d.schemaOf(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.objectSchema(t)
+		}
+
+		// the component is added before its fields so recursive types end
+		if _, exists := d.Components.Schemas[t.Name()]; !exists {
+			s := &Schema{}
+			d.Components.Schemas[t.Name()] = s
+			*s = *d.objectSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+
+	return &Schema{}
+}
+
+// objectSchema describes the fields of a struct the way encoding/json encodes
+// them. Fields are required unless they are omitted when empty, or when their
+// validate tag requires them.
+func (d *Document) objectSchema(t reflect.Type) *Schema {
+	s := Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// embedded structs without a name have their fields promoted
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				promoted := d.objectSchema(embedded)
+				for propName, prop := range promoted.Properties {
+					s.Properties[propName] = prop
+				}
+				s.Required = append(s.Required, promoted.Required...)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := d.schemaOf(field.Type)
+		validate := field.Tag.Get("validate")
+		applyValidate(prop, validate)
+		s.Properties[name] = prop
+
+		omitEmpty := strings.Contains(","+opts+",", ",omitempty,")
+		if !omitEmpty || requires(validate) {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return &s
+}
+
+// requires reports whether the validate tag requires the value itself, rather
+// than the items it dives into.
+func requires(validate string) bool {
+	for _, rule := range strings.Split(validate, ",") {
+		switch rule {
+		case "required":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
+
+// applyValidate adds the constraints of a validate tag to the schema. Rules
+// following dive constrain the items of the value.
+func applyValidate(s *Schema, validate string) {
+	if validate == "" || s.Ref != "" {
+		return
+	}
+
+	target := s
+	for _, rule := range strings.Split(validate, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			if target.Items == nil {
+				return
+			}
+			target = target.Items
+
+		case "required":
+			// required strings must not be empty
+			if target.Type == "string" && target.MinLength == nil {
+				target.MinLength = ptr(1)
+			}
+
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			applyBound(target, name, n)
+
+		case "oneof":
+			target.Enum = strings.Fields(param)
+
+		case "url":
+			target.Format = "uri"
+
+		case "uuid":
+			target.Format = "uuid"
+
+		case "email":
+			target.Format = "email"
+		}
+	}
+}
+
+// applyBound adds a min, max or len rule to the schema, which bounds the value
+// of numbers, the length of strings and the items of arrays.
+func applyBound(s *Schema, rule string, n float64) {
+	isMin := rule == "min" || rule == "len"
+	isMax := rule == "max" || rule == "len"
+
+	switch s.Type {
+	case "integer", "number":
+		if isMin {
+			s.Minimum = ptr(n)
+		}
+		if isMax {
+			s.Maximum = ptr(n)
+		}
+	case "string":
+		if isMin {
+			s.MinLength = ptr(int(n))
+		}
+		if isMax {
+			s.MaxLength = ptr(int(n))
+		}
+	case "array":
+		if isMin {
+			s.MinItems = ptr(int(n))
+		}
+		if isMax {
+			s.MaxItems = ptr(int(n))
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,97 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// streamWriteTimeout bounds every write of a stream. Streams outlive the
+// server WriteTimeout, so each write extends the deadline instead.
+const streamWriteTimeout = 10 * time.Second
+
+// Stream writes server-sent events to the client.
+type Stream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewStream starts a server-sent events response.
+func NewStream(ctx context.Context, w http.ResponseWriter) (*Stream, error) {
+	setStatusCode(ctx, http.StatusOK)
+
+	s := Stream{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+
+	if err := s.setWriteDeadline(); err != nil {
+		return nil, fmt.Errorf("web.stream: set write deadline: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := s.rc.Flush(); err != nil {
+		return nil, fmt.Errorf("web.stream: flush: %w", err)
+	}
+
+	return &s, nil
+}
+
+// Send converts a Go value to JSON and sends it to the client as an event with
+// the given id and type.
+func (s *Stream) Send(id string, event string, data any) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("web.stream: marshal: %w", err)
+	}
+
+	var b strings.Builder
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", id)
+	}
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event)
+	}
+	fmt.Fprintf(&b, "data: %s\n\n", jsonData)
+
+	return s.write(b.String())
+}
+
+// Comment sends a comment, which clients ignore, to keep the connection alive.
+func (s *Stream) Comment(text string) error {
+	return s.write(fmt.Sprintf(": %s\n\n", text))
+}
+
+func (s *Stream) write(msg string) error {
+	if err := s.setWriteDeadline(); err != nil {
+		return fmt.Errorf("web.stream: set write deadline: %w", err)
+	}
+
+	if _, err := s.w.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("web.stream: write: %w", err)
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("web.stream: flush: %w", err)
+	}
+
+	return nil
+}
+
+// setWriteDeadline extends the write deadline of the connection. Writers not
+// supporting deadlines, like test recorders, are written to without one.
+func (s *Stream) setWriteDeadline() error {
+	err := s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,17 +19,29 @@ type Handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) e
 // to the logs.
 type Logger func(ctx context.Context, msg string, v ...any)
 
+// Route is a method and path pair registered to the client.
+type Route struct {
+	Method string
+	Path   string
+}
+
 type Client struct {
 	*http.ServeMux
-	mw []MidHandler
+	mw           []MidHandler
+	routes       []Route
+	streamCtx    context.Context
+	closeStreams context.CancelFunc
 }
 
 func NewClient(mw ...MidHandler) *Client {
 	mux := http.NewServeMux()
+	streamCtx, closeStreams := context.WithCancel(context.Background())
 
 	return &Client{
-		ServeMux: mux,
-		mw:       mw,
+		ServeMux:     mux,
+		mw:           mw,
+		streamCtx:    streamCtx,
+		closeStreams: closeStreams,
 	}
 }
 
@@ -52,4 +65,33 @@ func (a *Client) Handle(method string, path string, handler Handler, mw ...MidHa
 	}
 	finalPath := fmt.Sprintf("%s %s", method, path)
 	a.ServeMux.HandleFunc(finalPath, h)
+	a.routes = append(a.routes, Route{Method: method, Path: path})
+}
+
+// Routes returns the routes registered to the client in registration order.
+func (a *Client) Routes() []Route {
+	return slices.Clone(a.routes)
+}
+
+// HandleStream sets a long-lived streaming handler function for a given HTTP
+// method and path pair to the application server mux. The context of the
+// handler is cancelled when CloseStreams is called.
+func (a *Client) HandleStream(method string, path string, handler Handler, mw ...MidHandler) {
+	stream := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stop := context.AfterFunc(a.streamCtx, cancel)
+		defer stop()
+
+		return handler(ctx, w, r)
+	}
+
+	a.Handle(method, path, stream, mw...)
+}
+
+// CloseStreams cancels the context of every streaming handler, so a graceful
+// shutdown does not wait on them.
+func (a *Client) CloseStreams() {
+	a.closeStreams()
 }
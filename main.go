@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,10 +17,12 @@ import (
 	"github.com/danipurwadi/internal-transfer-system/app/api/middleware"
 	"github.com/danipurwadi/internal-transfer-system/app/transferapp"
 	"github.com/danipurwadi/internal-transfer-system/business/api/db"
+	"github.com/danipurwadi/internal-transfer-system/business/fxbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus"
 	"github.com/danipurwadi/internal-transfer-system/business/transferbus/stores/transferdb"
 	"github.com/danipurwadi/internal-transfer-system/foundation/logger"
 	"github.com/danipurwadi/internal-transfer-system/foundation/web"
+	"google.golang.org/grpc"
 )
 
 var build = "develop"
@@ -62,13 +65,15 @@ func run(ctx context.Context, log *logger.Logger) error {
 	// CONFIGS
 	cfg := struct {
 		conf.Version
-		Web struct {
+		Args conf.Args
+		Web  struct {
 			ReadTimeout        time.Duration `conf:"default:5s"`
 			WriteTimeout       time.Duration `conf:"default:10s"`
 			IdleTimeout        time.Duration `conf:"default:120s"`
 			ShutdownTimeout    time.Duration `conf:"default:20s"`
 			APIHost            string        `conf:"default:0.0.0.0:8080"`
 			DebugHost          string        `conf:"default:0.0.0.0:8090"`
+			GRPCHost           string        `conf:"default:0.0.0.0:9090"`
 			CORSAllowedOrigins []string      `conf:"default:*"`
 		}
 		DB struct {
@@ -78,6 +83,45 @@ func run(ctx context.Context, log *logger.Logger) error {
 			Port       int    `conf:"default:5432"`
 			Name       string `conf:"default:transfer"`
 			DisableTLS bool   `conf:"default:true"`
+			Retry      struct {
+				MaxAttempts int           `conf:"default:5"`
+				BaseDelay   time.Duration `conf:"default:10ms"`
+				MaxDelay    time.Duration `conf:"default:500ms"`
+			}
+			Isolation struct {
+				Write string `conf:"default:read-committed"`
+				Batch string `conf:"default:serializable"`
+				Read  string `conf:"default:repeatable-read"`
+			}
+		}
+		FX struct {
+			RatesFile string        `conf:"default:zarf/fx/rates.json"`
+			QuoteTTL  time.Duration `conf:"default:30s"`
+		}
+		Reconcile struct {
+			BatchSize int  `conf:"default:500"`
+			Alert     bool `conf:"default:true"`
+		}
+		Holds struct {
+			ExpiryInterval time.Duration `conf:"default:1m"`
+		}
+		Scheduler struct {
+			Interval time.Duration `conf:"default:10s"`
+		}
+		Outbox struct {
+			EventsFile    string        `conf:"default:zarf/outbox/events.jsonl"`
+			RelayInterval time.Duration `conf:"default:1s"`
+		}
+		Webhooks struct {
+			Interval             time.Duration `conf:"default:5s"`
+			Timeout              time.Duration `conf:"default:10s"`
+			MaxAttempts          int           `conf:"default:10"`
+			BaseDelay            time.Duration `conf:"default:30s"`
+			MaxDelay             time.Duration `conf:"default:6h"`
+			AllowPrivateNetworks bool          `conf:"default:false"`
+		}
+		Streams struct {
+			ListenRetryDelay time.Duration `conf:"default:5s"`
 		}
 	}{
 		Version: conf.Version{
@@ -120,7 +164,47 @@ func run(ctx context.Context, log *logger.Logger) error {
 	dbConn := db.New(dbConfig)
 	db.Migrate(dbConfig)
 
-	dbClient := transferdb.NewTxQueries(dbConn)
+	dbClient := transferdb.NewTxQueries(dbConn, transferdb.RetryConfig{
+		MaxAttempts: cfg.DB.Retry.MaxAttempts,
+		BaseDelay:   cfg.DB.Retry.BaseDelay,
+		MaxDelay:    cfg.DB.Retry.MaxDelay,
+	})
+
+	// -------------------------------------------------------------------------
+	// FX Support
+
+	log.Info(ctx, "startup", "status", "initializing fx support", "ratesFile", cfg.FX.RatesFile)
+
+	rates, err := fxbus.NewFileRates(cfg.FX.RatesFile)
+	if err != nil {
+		return fmt.Errorf("loading fx rates: %w", err)
+	}
+
+	iso, err := isolation(cfg.DB.Isolation.Write, cfg.DB.Isolation.Batch, cfg.DB.Isolation.Read)
+	if err != nil {
+		return fmt.Errorf("parsing isolation levels: %w", err)
+	}
+
+	// initialise business layer
+	fxBus := fxbus.New(rates, cfg.FX.QuoteTTL)
+	transferBus := transferbus.New(dbClient, fxBus, iso, log)
+	if cfg.Webhooks.AllowPrivateNetworks {
+		transferBus.AllowPrivateWebhooks()
+	}
+
+	// -------------------------------------------------------------------------
+	// Subcommands
+
+	switch cfg.Args.Num(0) {
+	case "":
+	case "reconcile":
+		return reconcile(ctx, log, transferBus, transferbus.ReconcileConfig{
+			BatchSize: cfg.Reconcile.BatchSize,
+			Alert:     cfg.Reconcile.Alert,
+		})
+	default:
+		return fmt.Errorf("unknown command %q", cfg.Args.Num(0))
+	}
 
 	// -------------------------------------------------------------------------
 	// Start API Service
@@ -130,9 +214,6 @@ func run(ctx context.Context, log *logger.Logger) error {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
-	// initialise business layer
-	transferBus := transferbus.New(dbClient, log)
-
 	// initialise app layer
 	transferApp := transferapp.NewApp(transferBus)
 
@@ -149,13 +230,32 @@ func run(ctx context.Context, log *logger.Logger) error {
 		ErrorLog:     logger.NewStdLogger(log, logger.LevelError),
 	}
 
-	serverErrors := make(chan error, 1)
+	// event streams outlive the write timeout and would hold up the shutdown
+	api.RegisterOnShutdown(webClient.CloseStreams)
+
+	serverErrors := make(chan error, 2)
 
 	go func() {
 		log.Info(ctx, "startup", "status", "api router started", "host", api.Addr)
 		serverErrors <- api.ListenAndServe()
 	}()
 
+	// -------------------------------------------------------------------------
+	// Start gRPC Service
+
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(middleware.GRPCLogger(log), middleware.GRPCErrors(log)))
+	transferApp.RegisterGRPC(grpcServer)
+
+	grpcListener, err := net.Listen("tcp", cfg.Web.GRPCHost)
+	if err != nil {
+		return fmt.Errorf("listening for grpc: %w", err)
+	}
+
+	go func() {
+		log.Info(ctx, "startup", "status", "grpc router started", "host", cfg.Web.GRPCHost)
+		serverErrors <- grpcServer.Serve(grpcListener)
+	}()
+
 	// -------------------------------------------------------------------------
 	// Start Debug Service
 
@@ -167,6 +267,80 @@ func run(ctx context.Context, log *logger.Logger) error {
 		}
 	}()
 
+	// -------------------------------------------------------------------------
+	// Start Hold Expiry
+
+	expiryCtx, stopExpiry := context.WithCancel(ctx)
+	defer stopExpiry()
+
+	expiryDone := make(chan struct{})
+	go func() {
+		defer close(expiryDone)
+		expireHolds(expiryCtx, log, transferBus, cfg.Holds.ExpiryInterval)
+	}()
+
+	// -------------------------------------------------------------------------
+	// Start Transfer Scheduler
+
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	defer stopScheduler()
+
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		runScheduledTransfers(schedulerCtx, log, transferBus, cfg.Scheduler.Interval)
+	}()
+
+	// -------------------------------------------------------------------------
+	// Start Outbox Relay
+
+	log.Info(ctx, "startup", "status", "initializing outbox relay", "eventsFile", cfg.Outbox.EventsFile)
+
+	publisher, err := transferbus.NewFilePublisher(cfg.Outbox.EventsFile)
+	if err != nil {
+		return fmt.Errorf("opening events file: %w", err)
+	}
+	defer publisher.Close()
+
+	relayCtx, stopRelay := context.WithCancel(ctx)
+	defer stopRelay()
+
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relayEvents(relayCtx, log, transferBus, publisher, cfg.Outbox.RelayInterval)
+	}()
+
+	// -------------------------------------------------------------------------
+	// Start Webhook Delivery
+
+	webhooksCtx, stopWebhooks := context.WithCancel(ctx)
+	defer stopWebhooks()
+
+	webhooksDone := make(chan struct{})
+	go func() {
+		defer close(webhooksDone)
+		deliverWebhooks(webhooksCtx, log, transferBus, cfg.Webhooks.Interval, transferbus.WebhookConfig{
+			Timeout:              cfg.Webhooks.Timeout,
+			MaxAttempts:          cfg.Webhooks.MaxAttempts,
+			BaseDelay:            cfg.Webhooks.BaseDelay,
+			MaxDelay:             cfg.Webhooks.MaxDelay,
+			AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+		})
+	}()
+
+	// -------------------------------------------------------------------------
+	// Start Account Event Listener
+
+	listenerCtx, stopListener := context.WithCancel(ctx)
+	defer stopListener()
+
+	listenerDone := make(chan struct{})
+	go func() {
+		defer close(listenerDone)
+		listenAccountEvents(listenerCtx, log, transferBus, cfg.Streams.ListenRetryDelay)
+	}()
+
 	// -------------------------------------------------------------------------
 	// Handle shutdown
 
@@ -178,14 +352,216 @@ func run(ctx context.Context, log *logger.Logger) error {
 		log.Info(ctx, "shutdown", "status", "shutdown started", "signal", sig)
 		defer log.Info(ctx, "shutdown", "status", "shutdown complete", "signal", sig)
 
+		// a run in progress is rolled back rather than left half done
+		stopExpiry()
+		<-expiryDone
+
+		stopScheduler()
+		<-schedulerDone
+
+		// the relay publishes what was committed before the scheduler stopped
+		stopRelay()
+		<-relayDone
+
+		stopWebhooks()
+		<-webhooksDone
+
 		ctx, cancel := context.WithTimeout(ctx, cfg.Web.ShutdownTimeout)
 		defer cancel()
 
+		// both servers drain their in-flight requests within the same timeout
+		grpcStopped := make(chan struct{})
+		go func() {
+			defer close(grpcStopped)
+			grpcServer.GracefulStop()
+		}()
+
 		if err := api.Shutdown(ctx); err != nil {
 			api.Close()
+			grpcServer.Stop()
 			return fmt.Errorf("could not stop server gracefully: %w", err)
 		}
+
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+			return fmt.Errorf("could not stop grpc server gracefully: %w", ctx.Err())
+		}
+
+		stopListener()
+		<-listenerDone
 	}
 
 	return nil
 }
+
+// reconcile runs a single balance reconciliation and fails when drift is found,
+// so it can be scheduled as a job whose exit code signals the outcome.
+func reconcile(ctx context.Context, log *logger.Logger, bus *transferbus.Bus, cfg transferbus.ReconcileConfig) error {
+	log.Info(ctx, "reconciliation", "status", "started", "batchSize", cfg.BatchSize)
+
+	result, err := bus.Reconcile(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("reconcile: %w", err)
+	}
+
+	log.Info(ctx, "reconciliation", "status", "completed", "accountsScanned", result.AccountsScanned,
+		"drifts", len(result.Drifts), "totalBalances", result.TotalBalances)
+
+	if !result.Balanced() {
+		return fmt.Errorf("reconcile: %d accounts drifted, ledger totals %v", len(result.Drifts), result.TotalBalances)
+	}
+
+	return nil
+}
+
+// expireHolds periodically releases the funds of expired holds until the
+// context is cancelled.
+func expireHolds(ctx context.Context, log *logger.Logger, bus *transferbus.Bus, interval time.Duration) {
+	log.Info(ctx, "startup", "status", "hold expiry started", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := bus.ExpireHolds(ctx)
+			if err != nil {
+				log.Error(ctx, "hold expiry", "status", "failed", "err", err)
+				continue
+			}
+			if expired > 0 {
+				log.Info(ctx, "hold expiry", "status", "released", "holds", expired)
+			}
+		}
+	}
+}
+
+// runScheduledTransfers periodically runs the scheduled transfers that are due
+// until the context is cancelled.
+func runScheduledTransfers(ctx context.Context, log *logger.Logger, bus *transferbus.Bus, interval time.Duration) {
+	log.Info(ctx, "startup", "status", "transfer scheduler started", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ran, err := bus.RunScheduledTransfers(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Error(ctx, "transfer scheduler", "status", "failed", "err", err)
+				continue
+			}
+			if ran > 0 {
+				log.Info(ctx, "transfer scheduler", "status", "ran", "transfers", ran)
+			}
+		}
+	}
+}
+
+// relayEvents periodically publishes the events written to the outbox until
+// the context is cancelled.
+func relayEvents(ctx context.Context, log *logger.Logger, bus *transferbus.Bus, publisher transferbus.Publisher, interval time.Duration) {
+	log.Info(ctx, "startup", "status", "outbox relay started", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := bus.RelayEvents(ctx, publisher)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Error(ctx, "outbox relay", "status", "failed", "published", published, "err", err)
+			}
+		}
+	}
+}
+
+// deliverWebhooks periodically attempts the webhook deliveries that are due
+// until the context is cancelled.
+func deliverWebhooks(ctx context.Context, log *logger.Logger, bus *transferbus.Bus, interval time.Duration, cfg transferbus.WebhookConfig) {
+	log.Info(ctx, "startup", "status", "webhook delivery started", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			attempted, err := bus.DeliverWebhooks(ctx, cfg)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Error(ctx, "webhook delivery", "status", "failed", "err", err)
+				continue
+			}
+			if attempted > 0 {
+				log.Info(ctx, "webhook delivery", "status", "attempted", "deliveries", attempted)
+			}
+		}
+	}
+}
+
+// listenAccountEvents wakes up the account event streams as events commit until
+// the context is cancelled, listening again after the retry delay whenever the
+// database connection fails.
+func listenAccountEvents(ctx context.Context, log *logger.Logger, bus *transferbus.Bus, retryDelay time.Duration) {
+	log.Info(ctx, "startup", "status", "account event listener started", "retryDelay", retryDelay)
+
+	for {
+		err := bus.ListenAccountEvents(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error(ctx, "account events", "status", "listen failed", "err", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// isolation parses the configured isolation level of each kind of operation.
+func isolation(write string, batch string, read string) (transferbus.Isolation, error) {
+	writeLevel, err := transferdb.ParseIsoLevel(write)
+	if err != nil {
+		return transferbus.Isolation{}, fmt.Errorf("write: %w", err)
+	}
+
+	batchLevel, err := transferdb.ParseIsoLevel(batch)
+	if err != nil {
+		return transferbus.Isolation{}, fmt.Errorf("batch: %w", err)
+	}
+
+	readLevel, err := transferdb.ParseIsoLevel(read)
+	if err != nil {
+		return transferbus.Isolation{}, fmt.Errorf("read: %w", err)
+	}
+
+	return transferbus.Isolation{
+		Write: writeLevel,
+		Batch: batchLevel,
+		Read:  readLevel,
+	}, nil
+}